@@ -0,0 +1,305 @@
+////////////////////////////////////////////////////////////////////////////////
+//	apply.go  -  Oct-17-2026  -  aldebap
+//
+//	Apply a declarative state to Kong
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// ids of the entities known while applying a state
+type kongStateIds struct {
+	services       map[string]string
+	routes         map[string]string
+	consumers      map[string]string
+	consumerGroups map[string]string
+	upstreams      map[string]string
+}
+
+// collect the ids of the entities in the current state
+func newKongStateIds(current *KongState) *kongStateIds {
+
+	ids := &kongStateIds{
+		services:       make(map[string]string),
+		routes:         make(map[string]string),
+		consumers:      make(map[string]string),
+		consumerGroups: current.consumerGroupIds,
+		upstreams:      make(map[string]string),
+	}
+
+	for i := range current.Services {
+		ids.services[current.Services[i].key()] = current.Services[i].id
+	}
+	for i := range current.Routes {
+		ids.routes[current.Routes[i].key()] = current.Routes[i].id
+	}
+	for i := range current.Consumers {
+		ids.consumers[current.Consumers[i].key()] = current.Consumers[i].id
+	}
+	for i := range current.Upstreams {
+		ids.upstreams[current.Upstreams[i].key()] = current.Upstreams[i].id
+	}
+
+	return ids
+}
+
+// resolve a state reference into a Kong foreign key
+func stateForeignKey(names map[string]string, name string) map[string]string {
+
+	id, ok := names[name]
+	if !ok {
+		id = name
+	}
+
+	return map[string]string{"id": id}
+}
+
+// kong apply result payload
+type KongApplyResponse struct {
+	Changes []KongStateChange `json:"changes"`
+}
+
+// apply a declarative state to Kong
+func (ks *KongServerDomain) ApplyState(desiredState *KongState, options Options) error {
+
+	currentState, err := ks.fetchKongState()
+	if err != nil {
+		return err
+	}
+
+	changes, err := planKongState(desiredState, currentState)
+	if err != nil {
+		return err
+	}
+
+	//	basic auth passwords aren't dumped, so a credential without one is refused before any change is sent
+	for _, change := range changes {
+		basicAuth, ok := change.desired.(*KongStateBasicAuth)
+		if ok && change.Action == stateActionCreate && len(basicAuth.Password) == 0 {
+			return errors.New("missing password for " + stateEntityBasicAuth + " " + change.Key + ": passwords are not dumped, so set it in the state file")
+		}
+	}
+
+	ids := newKongStateIds(currentState)
+
+	for i := range changes {
+		status, err := ks.applyStateChange(&changes[i], ids)
+		if err != nil {
+			return err
+		}
+
+		if !options.jsonOutput {
+			if options.verbose {
+				fmt.Printf("http response status code: %s\n", status)
+			}
+			fmt.Printf("%s %s: %s\n", changes[i].Action, changes[i].Entity, changes[i].Key)
+		}
+	}
+
+	if options.jsonOutput {
+		payload, err := json.Marshal(KongApplyResponse{
+			Changes: changes,
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s\n", string(payload))
+	} else {
+		if len(changes) == 0 {
+			fmt.Printf("No changes\n")
+		}
+	}
+
+	return nil
+}
+
+// send a single state change to Kong
+func (ks *KongServerDomain) applyStateChange(change *KongStateChange, ids *kongStateIds) (string, error) {
+
+	var (
+		collectionURL string
+		payload       map[string]interface{}
+		err           error
+	)
+
+	switch entity := change.desired.(type) {
+	case *KongStateService:
+		collectionURL = fmt.Sprintf("%s/%s", ks.ServerURL(), servicesResource)
+
+	case *KongStateRoute:
+		collectionURL = fmt.Sprintf("%s/%s", ks.ServerURL(), routesResource)
+
+		if change.Action != stateActionDelete {
+			payload, err = stateFields(entity)
+			if err != nil {
+				return "", err
+			}
+
+			if len(entity.Service) > 0 {
+				payload["service"] = stateForeignKey(ids.services, entity.Service)
+			}
+		}
+
+	case *KongStateConsumer:
+		collectionURL = fmt.Sprintf("%s/%s", ks.ServerURL(), consumersResource)
+
+	case *KongStateBasicAuth, *KongStateKeyAuth, *KongStateJWT, *KongStateACL, *KongStateHMACAuth:
+		var consumer, credentialResource string
+
+		switch credential := entity.(type) {
+		case *KongStateBasicAuth:
+			consumer, credentialResource = credential.consumer, basicAuthPlugins
+
+		case *KongStateKeyAuth:
+			consumer, credentialResource = credential.consumer, keyAuthPlugins
+
+		case *KongStateJWT:
+			consumer, credentialResource = credential.consumer, jwtPlugins
+
+		case *KongStateACL:
+			consumer, credentialResource = credential.consumer, aclsResource
+
+		case *KongStateHMACAuth:
+			consumer, credentialResource = credential.consumer, hmacAuthPlugins
+		}
+
+		consumerId, ok := ids.consumers[consumer]
+		if !ok {
+			return "", errors.New("consumer not found: " + consumer)
+		}
+
+		collectionURL = fmt.Sprintf("%s/%s/%s/%s", ks.ServerURL(), consumersResource, consumerId, credentialResource)
+
+	case *KongStatePlugin:
+		collectionURL = fmt.Sprintf("%s/%s", ks.ServerURL(), pluginsResource)
+
+		if change.Action != stateActionDelete {
+			payload, err = stateFields(entity)
+			if err != nil {
+				return "", err
+			}
+
+			if len(entity.Service) > 0 {
+				payload["service"] = stateForeignKey(ids.services, entity.Service)
+			}
+			if len(entity.Route) > 0 {
+				payload["route"] = stateForeignKey(ids.routes, entity.Route)
+			}
+			if len(entity.Consumer) > 0 {
+				payload["consumer"] = stateForeignKey(ids.consumers, entity.Consumer)
+			}
+			if len(entity.ConsumerGroup) > 0 {
+				if _, ok := ids.consumerGroups[entity.ConsumerGroup]; !ok {
+					return "", errors.New("consumer group not found: " + entity.ConsumerGroup)
+				}
+				payload["consumer_group"] = stateForeignKey(ids.consumerGroups, entity.ConsumerGroup)
+			}
+		}
+
+	case *KongStateUpstream:
+		collectionURL = fmt.Sprintf("%s/%s", ks.ServerURL(), upstreamResource)
+
+	case *KongStateUpstreamTarget:
+		upstreamId, ok := ids.upstreams[entity.upstream]
+		if !ok {
+			return "", errors.New("upstream not found: " + entity.upstream)
+		}
+
+		collectionURL = fmt.Sprintf("%s/%s/%s/%s", ks.ServerURL(), upstreamResource, upstreamId, upstreamTargetResource)
+
+	default:
+		return "", errors.New("invalid entity for apply: " + change.Entity)
+	}
+
+	if payload == nil && change.Action != stateActionDelete {
+		payload, err = stateFields(change.desired)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	var (
+		method         string
+		entityURL      string = collectionURL
+		expectedStatus int
+	)
+
+	switch change.Action {
+	case stateActionCreate:
+		method = "POST"
+		expectedStatus = http.StatusCreated
+
+	case stateActionUpdate:
+		method = "PATCH"
+		entityURL = collectionURL + "/" + change.Id
+		expectedStatus = http.StatusOK
+
+	case stateActionDelete:
+		method = "DELETE"
+		entityURL = collectionURL + "/" + change.Id
+		expectedStatus = http.StatusNoContent
+	}
+
+	var reqPayload []byte
+
+	if payload != nil {
+		reqPayload, err = json.Marshal(payload)
+		if err != nil {
+			return "", err
+		}
+	}
+
+	resp, err := ks.sendRequest(method, entityURL, reqPayload)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != expectedStatus {
+		return "", newKongAPIError("fail sending "+change.Action+" "+change.Entity+" "+change.Key+" command to Kong", resp)
+	}
+
+	//	keep the id of created entities so that dependent entities can reference them
+	if change.Action == stateActionCreate {
+		var respPayload []byte
+
+		respPayload, err = io.ReadAll(resp.Body)
+		if err != nil {
+			return "", err
+		}
+
+		var entityResp struct {
+			Id string `json:"id"`
+		}
+
+		err = json.Unmarshal(respPayload, &entityResp)
+		if err != nil {
+			return "", err
+		}
+		change.Id = entityResp.Id
+
+		switch change.Entity {
+		case stateEntityService:
+			ids.services[change.Key] = entityResp.Id
+
+		case stateEntityRoute:
+			ids.routes[change.Key] = entityResp.Id
+
+		case stateEntityConsumer:
+			ids.consumers[change.Key] = entityResp.Id
+
+		case stateEntityUpstream:
+			ids.upstreams[change.Key] = entityResp.Id
+		}
+	}
+
+	return resp.Status, nil
+}
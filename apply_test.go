@@ -0,0 +1,239 @@
+////////////////////////////////////////////////////////////////////////////////
+//	apply_test.go  -  Oct-17-2026  -  aldebap
+//
+//	Test cases for applying a declarative state to Kong
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+// Test_ApplyState unit tests for ApplyState() method
+func Test_ApplyState(t *testing.T) {
+
+	t.Run(">>> ApplyState: scenario 1 - error fetching current state", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("fail fetching services from Kong: 500 Internal Server Error")
+		got := kongServer.ApplyState(&KongState{}, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed applying state: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> ApplyState: scenario 2 - service and route created in order", func(t *testing.T) {
+
+		var requests []string
+		var routeServiceId string
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+
+			switch r.Method {
+			case "GET":
+				if r.URL.Path == "/services" && len(r.URL.Query().Get("offset")) == 0 {
+					w.WriteHeader(http.StatusOK)
+					w.Write([]byte(`{ "data": [], "next": "/services?offset=1" }`))
+					return
+				}
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{ "data": [], "next": null }`))
+
+			case "POST":
+				if r.URL.Path == "/routes" {
+					var route struct {
+						Service struct {
+							Id string `json:"id"`
+						} `json:"service"`
+					}
+
+					payload, _ := io.ReadAll(r.Body)
+					json.Unmarshal(payload, &route)
+					routeServiceId = route.Service.Id
+				}
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{ "id": "1343894e-404a-4f9e-a982-9e5c0e9d1733" }`))
+			}
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		desiredState := &KongState{
+			Services: []KongStateService{{Name: "Produtos", Host: "192.168.68.107", Port: 8080}},
+			Routes:   []KongStateRoute{{Name: "Produtos", Service: "Produtos", Paths: []string{"/api/v1/produto"}}},
+		}
+
+		var want error = nil
+		got := kongServer.ApplyState(desiredState, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if want != got {
+			t.Fatalf("failed applying state: success expected: result: %s", got.Error())
+		}
+
+		wantRequests := "GET /services GET /services GET /routes GET /consumers GET /basic-auths GET /key-auths GET /jwts GET /acls GET /hmac-auths GET /upstreams " +
+			"GET /consumer_groups GET /plugins POST /services POST /routes"
+		if len(requests) != 14 || strings.Join(requests, " ") != wantRequests {
+			t.Errorf("failed applying state: expected requests: %s result: %v", wantRequests, requests)
+		}
+
+		if routeServiceId != "1343894e-404a-4f9e-a982-9e5c0e9d1733" {
+			t.Errorf("failed applying state: route service id expected: 1343894e-404a-4f9e-a982-9e5c0e9d1733 result: %s", routeServiceId)
+		}
+	})
+
+	t.Run(">>> ApplyState: scenario 3 - plugin scoped to a consumer group", func(t *testing.T) {
+
+		var requests []string
+		var pluginConsumerGroupId string
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+
+			switch r.Method {
+			case "GET":
+				w.WriteHeader(http.StatusOK)
+
+				switch r.URL.Path {
+				case "/consumer_groups":
+					w.Write([]byte(`{ "data": [ { "id": "4a5b6c7d-8e9f-4a0b-9c1d-2e3f4a5b6c7d", "name": "gold" } ], "next": null }`))
+
+				case "/plugins":
+					w.Write([]byte(`{ "data": [
+						{ "id": "p1", "name": "rate-limiting", "enabled": true, "config": { "minute": 10 } }
+					], "next": null }`))
+
+				default:
+					w.Write([]byte(`{ "data": [], "next": null }`))
+				}
+
+			case "POST":
+				var plugin struct {
+					ConsumerGroup struct {
+						Id string `json:"id"`
+					} `json:"consumer_group"`
+				}
+
+				payload, _ := io.ReadAll(r.Body)
+				json.Unmarshal(payload, &plugin)
+				pluginConsumerGroupId = plugin.ConsumerGroup.Id
+
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{ "id": "p2" }`))
+			}
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		//	the global plugin is unchanged, and the plugin scoped to the group is a different plugin
+		enabled := true
+		desiredState := &KongState{
+			Plugins: []KongStatePlugin{
+				{Name: "rate-limiting", Enabled: &enabled, Config: map[string]interface{}{"minute": 10.0}},
+				{Name: "rate-limiting", ConsumerGroup: "gold", Config: map[string]interface{}{"minute": 100.0}},
+			},
+		}
+
+		var want error = nil
+		got := kongServer.ApplyState(desiredState, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if want != got {
+			t.Fatalf("failed applying state: success expected: result: %s", got.Error())
+		}
+
+		if requests[len(requests)-1] != "POST /plugins" || requests[len(requests)-2] != "GET /plugins" {
+			t.Errorf("failed applying state: single plugin creation expected: result: %v", requests)
+		}
+
+		if pluginConsumerGroupId != "4a5b6c7d-8e9f-4a0b-9c1d-2e3f4a5b6c7d" {
+			t.Errorf("failed applying state: plugin consumer group id expected: 4a5b6c7d-8e9f-4a0b-9c1d-2e3f4a5b6c7d result: %s", pluginConsumerGroupId)
+		}
+	})
+
+	t.Run(">>> ApplyState: scenario 4 - basic auth without password refused", func(t *testing.T) {
+
+		var requests []string
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r.Method+" "+r.URL.Path)
+
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{ "data": [], "next": null }`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		desiredState := &KongState{
+			Consumers: []KongStateConsumer{
+				{UserName: "guest", BasicAuth: []KongStateBasicAuth{{consumer: "guest", UserName: "guest"}}},
+			},
+		}
+
+		want := errors.New("missing password for consumer-basic-auth guest/guest: passwords are not dumped, so set it in the state file")
+		got := kongServer.ApplyState(desiredState, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed applying state: error expected: %v result: %v", want, got)
+		}
+
+		for _, request := range requests {
+			if !strings.HasPrefix(request, "GET ") {
+				t.Errorf("failed applying state: no change expected: result: %v", requests)
+				break
+			}
+		}
+	})
+}
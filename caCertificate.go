@@ -0,0 +1,279 @@
+////////////////////////////////////////////////////////////////////////////////
+//	caCertificate.go  -  Oct-17-2026  -  aldebap
+//
+//	Kong CA certificate configuration
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// kong CA certificate attributes
+type KongCACertificate struct {
+	cert string
+	tags []string
+}
+
+// create a new Kong CA certificate
+func NewKongCACertificate(cert string, tags []string) *KongCACertificate {
+
+	return &KongCACertificate{
+		cert: cert,
+		tags: tags,
+	}
+}
+
+// kong CA certificate request payload
+type KongCACertificateRequest struct {
+	Cert string   `json:"cert,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// kong CA certificate response payload
+type KongCACertificateResponse struct {
+	Id         string   `json:"id"`
+	Cert       string   `json:"cert"`
+	CertDigest string   `json:"cert_digest"`
+	Tags       []string `json:"tags"`
+	CreatedAt  uint64   `json:"created_at"`
+}
+
+// kong CA certificate list response payload
+type KongCACertificateListResponse struct {
+	Data []KongCACertificateResponse `json:"data"`
+	Next string                      `json:"next"`
+}
+
+const (
+	caCertificatesResource string = "ca_certificates"
+)
+
+// add a new CA certificate to Kong
+func (ks *KongServerDomain) AddCACertificate(newKongCACertificate *KongCACertificate, options Options) error {
+
+	var caCertificateURL string = fmt.Sprintf("%s/%s", ks.ServerURL(), caCertificatesResource)
+
+	payload, err := json.Marshal(KongCACertificateRequest{
+		Cert: newKongCACertificate.cert,
+		Tags: newKongCACertificate.tags,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := ks.sendRequest("POST", caCertificateURL, payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return newKongAPIError("fail sending add ca certificate command to Kong", resp)
+	}
+
+	//	parse response payload
+	var respPayload []byte
+
+	respPayload, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var caCertificateResp KongCACertificateResponse
+
+	err = json.Unmarshal(respPayload, &caCertificateResp)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+	} else {
+		if options.verbose {
+			fmt.Printf("http response status code: %s\nnew ca certificate ID: %s\n", resp.Status, caCertificateResp.Id)
+		} else {
+			fmt.Printf("%s\n", caCertificateResp.Id)
+		}
+	}
+
+	return nil
+}
+
+// query a CA certificate by Id
+func (ks *KongServerDomain) QueryCACertificate(id string, options Options) error {
+
+	var caCertificateURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), caCertificatesResource, id)
+
+	//	send a request to Kong to query the CA certificate by id
+	resp, err := ks.sendRequest("GET", caCertificateURL, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return newKongNotFoundError("ca certificate", resp)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return newKongAPIError("fail sending query ca certificate command to Kong", resp)
+	}
+
+	var respPayload []byte
+
+	respPayload, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+	} else {
+		var caCertificateResp KongCACertificateResponse
+
+		err = json.Unmarshal(respPayload, &caCertificateResp)
+		if err != nil {
+			return err
+		}
+
+		if options.verbose {
+			fmt.Printf("http response status code: %s\n", resp.Status)
+		}
+		fmt.Printf("%s: %s (%s)\n", caCertificateResp.Id, certificateSummary(caCertificateResp.Cert), caCertificateResp.Tags)
+	}
+
+	return nil
+}
+
+// list all CA certificates
+func (ks *KongServerDomain) ListCACertificates(pagination *KongPagination, options Options) error {
+
+	//	send a request to Kong to get a list of all CA certificates
+	collection, status, err := ks.fetchCollection(caCertificatesResource, pagination, "fail sending list ca certificates command to Kong")
+	if err != nil {
+		return err
+	}
+
+	respPayload, err := json.Marshal(collection)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", status, string(respPayload))
+	} else {
+		var caCertificateListResp KongCACertificateListResponse
+
+		err = json.Unmarshal(respPayload, &caCertificateListResp)
+		if err != nil {
+			return err
+		}
+
+		if len(caCertificateListResp.Data) == 0 {
+			if options.verbose {
+				fmt.Printf("%s\nNo ca certificates\n", status)
+			} else {
+				fmt.Printf("No ca certificates\n")
+			}
+
+			return nil
+		}
+
+		if options.verbose {
+			fmt.Printf("http response status code: %s\nca certificate list\n", status)
+		}
+
+		for _, caCertificate := range caCertificateListResp.Data {
+			fmt.Printf("%s: %s (%s)\n", caCertificate.Id, certificateSummary(caCertificate.Cert), caCertificate.Tags)
+		}
+		printNextOffset(collection)
+	}
+
+	return nil
+}
+
+// update a CA certificate in Kong
+func (ks *KongServerDomain) UpdateCACertificate(id string, updatedKongCACertificate *KongCACertificate, options Options) error {
+
+	var caCertificateURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), caCertificatesResource, id)
+
+	payload, err := json.Marshal(KongCACertificateRequest{
+		Cert: updatedKongCACertificate.cert,
+		Tags: updatedKongCACertificate.tags,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := ks.sendRequest("PATCH", caCertificateURL, payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return newKongNotFoundError("ca certificate", resp)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return newKongAPIError("fail sending patch ca certificate command to Kong", resp)
+	}
+
+	//	parse response payload
+	var respPayload []byte
+
+	respPayload, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var caCertificateResp KongCACertificateResponse
+
+	err = json.Unmarshal(respPayload, &caCertificateResp)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+	} else {
+		if options.verbose {
+			fmt.Printf("http response status code: %s\n", resp.Status)
+		}
+		fmt.Printf("%s: %s (%s)\n", caCertificateResp.Id, certificateSummary(caCertificateResp.Cert), caCertificateResp.Tags)
+	}
+
+	return nil
+}
+
+// delete a CA certificate in Kong
+func (ks *KongServerDomain) DeleteCACertificate(id string, options Options) error {
+
+	var caCertificateURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), caCertificatesResource, id)
+
+	//	send a request to Kong to delete the CA certificate by id
+	resp, err := ks.sendRequest("DELETE", caCertificateURL, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newKongAPIError("fail sending delete ca certificate command to Kong", resp)
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n{}\n", resp.Status)
+	} else {
+		if options.verbose {
+			fmt.Printf("http response status code: %s\n", resp.Status)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,140 @@
+////////////////////////////////////////////////////////////////////////////////
+//	caCertificate_test.go  -  Oct-17-2026  -  aldebap
+//
+//	Test cases for Kong CA Certificate Configuration
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+)
+
+// Test_AddCACertificate unit tests for AddCACertificate() method
+func Test_AddCACertificate(t *testing.T) {
+
+	t.Run(">>> AddCACertificate: scenario 1 - error with the request", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("fail sending add ca certificate command to Kong: 400 Bad Request")
+		got := kongServer.AddCACertificate(&KongCACertificate{}, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed adding ca certificate: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> AddCACertificate: scenario 2 - ca certificate created successfuly", func(t *testing.T) {
+
+		certFile, _, _ := writeCertificateFiles(t, "Internal CA", nil, time.Now().Add(time.Hour))
+		var request KongCACertificateRequest
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&request)
+
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "5b1c0f8e-6a4d-4e2b-9c3f-7d8e9f0a1b2c"}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"add", "ca-certificate", "--cert-file=" + certFile, "--tags=internal"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed adding ca certificate: success expected: result: %s", got.Error())
+		}
+
+		if len(request.Cert) == 0 || len(request.Tags) != 1 {
+			t.Errorf("failed adding ca certificate: PEM content expected in the request: %+v", request)
+		}
+	})
+}
+
+// Test_ListCACertificates unit tests for ListCACertificates() method
+func Test_ListCACertificates(t *testing.T) {
+
+	t.Run(">>> ListCACertificates: scenario 1 - ca certificates listed", func(t *testing.T) {
+
+		_, _, certPEM := writeCertificateFiles(t, "Internal CA", nil, time.Now().Add(time.Hour))
+		caCertificates, _ := json.Marshal(KongCACertificateListResponse{Data: []KongCACertificateResponse{
+			{Id: "5b1c0f8e-6a4d-4e2b-9c3f-7d8e9f0a1b2c", Cert: certPEM},
+		}})
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write(caCertificates)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kongServer.ListCACertificates(nil, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got != nil {
+			t.Errorf("failed listing ca certificates: success expected: result: %s", got.Error())
+		}
+	})
+}
+
+// Test_DeleteCACertificate unit tests for DeleteCACertificate() method
+func Test_DeleteCACertificate(t *testing.T) {
+
+	t.Run(">>> DeleteCACertificate: scenario 1 - ca certificate not deleted", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("fail sending delete ca certificate command to Kong: 400 Bad Request")
+		got := kongServer.DeleteCACertificate("5b1c0f8e-6a4d-4e2b-9c3f-7d8e9f0a1b2c", Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed deleting ca certificate: error expected: %v result: %v", want, got)
+		}
+	})
+}
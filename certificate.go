@@ -0,0 +1,616 @@
+////////////////////////////////////////////////////////////////////////////////
+//	certificate.go  -  Oct-17-2026  -  aldebap
+//
+//	Kong certificate configuration
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"math/big"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// kong certificate attributes
+type KongCertificate struct {
+	cert    string
+	key     string
+	certAlt string
+	keyAlt  string
+	tags    []string
+}
+
+// create a new Kong certificate
+func NewKongCertificate(cert string, key string, certAlt string, keyAlt string, tags []string) *KongCertificate {
+
+	return &KongCertificate{
+		cert:    cert,
+		key:     key,
+		certAlt: certAlt,
+		keyAlt:  keyAlt,
+		tags:    tags,
+	}
+}
+
+// kong certificate request payload
+type KongCertificateRequest struct {
+	Cert    string   `json:"cert,omitempty"`
+	Key     string   `json:"key,omitempty"`
+	CertAlt string   `json:"cert_alt,omitempty"`
+	KeyAlt  string   `json:"key_alt,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+}
+
+// kong certificate response payload
+type KongCertificateResponse struct {
+	Id        string   `json:"id"`
+	Cert      string   `json:"cert"`
+	Key       string   `json:"key"`
+	CertAlt   string   `json:"cert_alt,omitempty"`
+	KeyAlt    string   `json:"key_alt,omitempty"`
+	Snis      []string `json:"snis,omitempty"`
+	Tags      []string `json:"tags,omitempty"`
+	CreatedAt uint64   `json:"created_at"`
+}
+
+// kong certificate list response payload
+type KongCertificateListResponse struct {
+	Data []KongCertificateResponse `json:"data"`
+	Next string                    `json:"next"`
+}
+
+const (
+	certificatesResource string = "certificates"
+)
+
+// read a PEM file with the given block type (e.g. CERTIFICATE or PRIVATE KEY)
+func readPEMFile(fileName string, blockType string) (string, error) {
+
+	if len(fileName) == 0 {
+		return "", nil
+	}
+
+	content, err := os.ReadFile(fileName)
+	if err != nil {
+		return "", err
+	}
+
+	block, _ := pem.Decode(content)
+	if block == nil || !strings.Contains(block.Type, blockType) {
+		return "", errors.New("no PEM " + strings.ToLower(blockType) + " found in file: " + fileName)
+	}
+
+	return string(content), nil
+}
+
+// parse the leaf certificate of a PEM certificate chain
+func parsePEMCertificate(certPEM string) (*x509.Certificate, error) {
+
+	block, _ := pem.Decode([]byte(certPEM))
+	if block == nil {
+		return nil, errors.New("invalid PEM certificate")
+	}
+
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// summary of a PEM certificate: subject, SANs and expiry
+func certificateSummary(certPEM string) string {
+
+	certificate, err := parsePEMCertificate(certPEM)
+	if err != nil {
+		return "invalid certificate: " + err.Error()
+	}
+
+	sans := append([]string{}, certificate.DNSNames...)
+	for _, ip := range certificate.IPAddresses {
+		sans = append(sans, ip.String())
+	}
+
+	return fmt.Sprintf("%s %s expires %s", certificate.Subject.String(), sans, certificate.NotAfter.UTC().Format(time.RFC3339))
+}
+
+// add a new certificate to Kong
+func (ks *KongServerDomain) AddCertificate(newKongCertificate *KongCertificate, options Options) error {
+
+	var certificateURL string = fmt.Sprintf("%s/%s", ks.ServerURL(), certificatesResource)
+
+	payload, err := json.Marshal(KongCertificateRequest{
+		Cert:    newKongCertificate.cert,
+		Key:     newKongCertificate.key,
+		CertAlt: newKongCertificate.certAlt,
+		KeyAlt:  newKongCertificate.keyAlt,
+		Tags:    newKongCertificate.tags,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := ks.sendRequest("POST", certificateURL, payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return newKongAPIError("fail sending add certificate command to Kong", resp)
+	}
+
+	//	parse response payload
+	var respPayload []byte
+
+	respPayload, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var certificateResp KongCertificateResponse
+
+	err = json.Unmarshal(respPayload, &certificateResp)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+	} else {
+		if options.verbose {
+			fmt.Printf("http response status code: %s\nnew certificate ID: %s\n", resp.Status, certificateResp.Id)
+		} else {
+			fmt.Printf("%s\n", certificateResp.Id)
+		}
+	}
+
+	return nil
+}
+
+// query a certificate by Id
+func (ks *KongServerDomain) QueryCertificate(id string, options Options) error {
+
+	var certificateURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), certificatesResource, id)
+
+	//	send a request to Kong to query the certificate by id
+	resp, err := ks.sendRequest("GET", certificateURL, nil)
+	if err != nil {
+		return err
+	}
+
+	if resp.StatusCode == http.StatusNotFound {
+		return newKongNotFoundError("certificate", resp)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return newKongAPIError("fail sending query certificate command to Kong", resp)
+	}
+
+	var respPayload []byte
+
+	respPayload, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+	} else {
+		var certificateResp KongCertificateResponse
+
+		err = json.Unmarshal(respPayload, &certificateResp)
+		if err != nil {
+			return err
+		}
+
+		if options.verbose {
+			fmt.Printf("http response status code: %s\n", resp.Status)
+		}
+		printCertificate(&certificateResp)
+	}
+
+	return nil
+}
+
+// print a certificate summary, including the alternate certificate
+func printCertificate(certificate *KongCertificateResponse) {
+
+	fmt.Printf("%s: %s - snis: %s (%s)\n", certificate.Id, certificateSummary(certificate.Cert), certificate.Snis, certificate.Tags)
+	if len(certificate.CertAlt) > 0 {
+		fmt.Printf("    alternate: %s\n", certificateSummary(certificate.CertAlt))
+	}
+}
+
+// list all certificates
+func (ks *KongServerDomain) ListCertificates(pagination *KongPagination, options Options) error {
+
+	//	send a request to Kong to get a list of all certificates
+	collection, status, err := ks.fetchCollection(certificatesResource, pagination, "fail sending list certificates command to Kong")
+	if err != nil {
+		return err
+	}
+
+	respPayload, err := json.Marshal(collection)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", status, string(respPayload))
+	} else {
+		var certificateListResp KongCertificateListResponse
+
+		err = json.Unmarshal(respPayload, &certificateListResp)
+		if err != nil {
+			return err
+		}
+
+		if len(certificateListResp.Data) == 0 {
+			if options.verbose {
+				fmt.Printf("%s\nNo certificates\n", status)
+			} else {
+				fmt.Printf("No certificates\n")
+			}
+
+			return nil
+		}
+
+		if options.verbose {
+			fmt.Printf("http response status code: %s\ncertificate list\n", status)
+		}
+
+		for _, certificate := range certificateListResp.Data {
+			printCertificate(&certificate)
+		}
+		printNextOffset(collection)
+	}
+
+	return nil
+}
+
+// update a certificate in Kong
+func (ks *KongServerDomain) UpdateCertificate(id string, updatedKongCertificate *KongCertificate, options Options) error {
+
+	var certificateURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), certificatesResource, id)
+
+	payload, err := json.Marshal(KongCertificateRequest{
+		Cert:    updatedKongCertificate.cert,
+		Key:     updatedKongCertificate.key,
+		CertAlt: updatedKongCertificate.certAlt,
+		KeyAlt:  updatedKongCertificate.keyAlt,
+		Tags:    updatedKongCertificate.tags,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := ks.sendRequest("PATCH", certificateURL, payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return newKongNotFoundError("certificate", resp)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return newKongAPIError("fail sending patch certificate command to Kong", resp)
+	}
+
+	//	parse response payload
+	var respPayload []byte
+
+	respPayload, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var certificateResp KongCertificateResponse
+
+	err = json.Unmarshal(respPayload, &certificateResp)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+	} else {
+		if options.verbose {
+			fmt.Printf("http response status code: %s\n", resp.Status)
+		}
+		printCertificate(&certificateResp)
+	}
+
+	return nil
+}
+
+// delete a certificate in Kong
+func (ks *KongServerDomain) DeleteCertificate(id string, options Options) error {
+
+	var certificateURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), certificatesResource, id)
+
+	//	send a request to Kong to delete the certificate by id
+	resp, err := ks.sendRequest("DELETE", certificateURL, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newKongAPIError("fail sending delete certificate command to Kong", resp)
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n{}\n", resp.Status)
+	} else {
+		if options.verbose {
+			fmt.Printf("http response status code: %s\n", resp.Status)
+		}
+	}
+
+	return nil
+}
+
+// error returned when there are certificates expiring within the given period, or certificates that can't be parsed
+var errCertificatesExpiring = errors.New("certificates expiring within the given period")
+
+const (
+	expiringExitStatus int = 3
+
+	defaultCertificateValidity time.Duration = 365 * 24 * time.Hour
+)
+
+// kong certificate expiry report entry: a certificate that can't be parsed is reported with the parse error
+type KongCertificateExpiry struct {
+	Id            string     `json:"id"`
+	Subject       string     `json:"subject"`
+	Snis          []string   `json:"snis"`
+	NotAfter      *time.Time `json:"not_after,omitempty"`
+	DaysRemaining *int       `json:"days_remaining,omitempty"`
+	Error         string     `json:"error,omitempty"`
+}
+
+// parse a period like 30d, or a Go duration like 12h
+func parsePeriod(period string) (time.Duration, error) {
+
+	if days, found := strings.CutSuffix(period, "d"); found {
+		value, err := strconv.Atoi(days)
+		if err != nil || value < 0 {
+			return 0, errors.New("invalid period: " + period)
+		}
+
+		return time.Duration(value) * 24 * time.Hour, nil
+	}
+
+	value, err := time.ParseDuration(period)
+	if err != nil || value < 0 {
+		return 0, errors.New("invalid period: " + period)
+	}
+
+	return value, nil
+}
+
+// the time until a certificate expires, or since it expired, in hours when under one day
+func expiryPeriod(remaining time.Duration) string {
+
+	if remaining < 0 {
+		remaining = -remaining
+	}
+
+	if remaining < 24*time.Hour {
+		//	rounded up, so a certificate about to expire (or just expired) is never reported as 0 hours
+		hours := int((remaining + time.Hour - 1) / time.Hour)
+		if hours == 1 {
+			return "1 hour"
+		}
+		return fmt.Sprintf("%d hours", hours)
+	}
+
+	days := int(remaining / (24 * time.Hour))
+	if days == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", days)
+}
+
+// list the certificates expiring within a period, including the expired ones
+func (ks *KongServerDomain) ListExpiringCertificates(within time.Duration, options Options) error {
+
+	//	send a request to Kong to get a list of all certificates
+	collection, _, err := ks.fetchCollection(certificatesResource, nil, "fail sending list certificates command to Kong")
+	if err != nil {
+		return err
+	}
+
+	respPayload, err := json.Marshal(collection)
+	if err != nil {
+		return err
+	}
+
+	var certificateListResp KongCertificateListResponse
+
+	err = json.Unmarshal(respPayload, &certificateListResp)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	expiring := []KongCertificateExpiry{}
+
+	for _, certificate := range certificateListResp.Data {
+		x509Certificate, err := parsePEMCertificate(certificate.Cert)
+		if err != nil {
+			expiring = append(expiring, KongCertificateExpiry{
+				Id:    certificate.Id,
+				Snis:  certificate.Snis,
+				Error: err.Error(),
+			})
+			continue
+		}
+
+		remaining := x509Certificate.NotAfter.Sub(now)
+		if remaining > within {
+			continue
+		}
+
+		//	the days remaining are rounded down, so an expired certificate always has negative days remaining
+		notAfter := x509Certificate.NotAfter.UTC()
+		daysRemaining := int(math.Floor(remaining.Hours() / 24))
+
+		expiring = append(expiring, KongCertificateExpiry{
+			Id:            certificate.Id,
+			Subject:       x509Certificate.Subject.String(),
+			Snis:          certificate.Snis,
+			NotAfter:      &notAfter,
+			DaysRemaining: &daysRemaining,
+		})
+	}
+
+	if options.jsonOutput {
+		payload, err := json.Marshal(expiring)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s\n", string(payload))
+	} else {
+		if len(expiring) == 0 {
+			if within%(24*time.Hour) == 0 {
+				fmt.Printf("No certificates expiring within %d days\n", int(within.Hours()/24))
+			} else {
+				fmt.Printf("No certificates expiring within %s\n", within)
+			}
+		}
+
+		for _, certificate := range expiring {
+			if len(certificate.Error) > 0 {
+				fmt.Printf("%s: invalid certificate: %s - snis: %s\n", certificate.Id, certificate.Error, certificate.Snis)
+				continue
+			}
+
+			remaining := certificate.NotAfter.Sub(now)
+			if remaining < 0 {
+				fmt.Printf("%s: %s - snis: %s - expired %s ago\n", certificate.Id, certificate.Subject, certificate.Snis, expiryPeriod(remaining))
+			} else {
+				fmt.Printf("%s: %s - snis: %s - expires in %s\n", certificate.Id, certificate.Subject, certificate.Snis, expiryPeriod(remaining))
+			}
+		}
+	}
+
+	if len(expiring) > 0 {
+		return errCertificatesExpiring
+	}
+
+	return nil
+}
+
+// parse a PEM private key: PKCS #8, EC or PKCS #1
+func parsePEMPrivateKey(keyPEM string) (crypto.Signer, error) {
+
+	block, _ := pem.Decode([]byte(keyPEM))
+	if block == nil {
+		return nil, errors.New("invalid PEM private key")
+	}
+
+	if key, err := x509.ParsePKCS8PrivateKey(block.Bytes); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+	}
+
+	if key, err := x509.ParseECPrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	if key, err := x509.ParsePKCS1PrivateKey(block.Bytes); err == nil {
+		return key, nil
+	}
+
+	return nil, errors.New("unsupported PEM private key")
+}
+
+// generate a certificate and key pair: self signed, or signed by the given CA
+func generateCertificate(commonName string, sans []string, validity time.Duration, caCertPEM string, caKeyPEM string) (string, string, error) {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return "", "", err
+	}
+
+	serialNumber, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return "", "", err
+	}
+
+	now := time.Now()
+	template := &x509.Certificate{
+		SerialNumber: serialNumber,
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    now.Add(-5 * time.Minute),
+		NotAfter:     now.Add(validity),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	//	the common name is always one of the SANs, since clients ignore the CN
+	names := make(map[string]bool)
+
+	for _, san := range append([]string{commonName}, sans...) {
+		if names[san] {
+			continue
+		}
+		names[san] = true
+
+		if ip := net.ParseIP(san); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, san)
+		}
+	}
+
+	//	self signed, unless a CA certificate and key are given
+	var parent *x509.Certificate = template
+	var signer crypto.Signer = key
+
+	if len(caCertPEM) > 0 {
+		parent, err = parsePEMCertificate(caCertPEM)
+		if err != nil {
+			return "", "", err
+		}
+
+		signer, err = parsePEMPrivateKey(caKeyPEM)
+		if err != nil {
+			return "", "", err
+		}
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, parent, &key.PublicKey, signer)
+	if err != nil {
+		return "", "", err
+	}
+
+	keyDER, err := x509.MarshalPKCS8PrivateKey(key)
+	if err != nil {
+		return "", "", err
+	}
+
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+	keyPEM := pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER})
+
+	return string(certPEM), string(keyPEM), nil
+}
@@ -0,0 +1,503 @@
+////////////////////////////////////////////////////////////////////////////////
+//	certificate_test.go  -  Oct-17-2026  -  aldebap
+//
+//	Test cases for Kong Certificate Configuration
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+	"time"
+)
+
+// generate a self signed server certificate and write the PEM certificate and key files
+func writeCertificateFiles(t *testing.T, commonName string, dnsNames []string, notAfter time.Time) (string, string, string) {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("fail generating certificate key: %s", err.Error())
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		NotBefore:    notAfter.Add(-24 * time.Hour),
+		NotAfter:     notAfter,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth},
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("fail generating certificate: %s", err.Error())
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("fail encoding certificate key: %s", err.Error())
+	}
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "server.pem")
+	keyFile := filepath.Join(dir, "server-key.pem")
+	certPEM := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER})
+
+	os.WriteFile(certFile, certPEM, 0644)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+
+	return certFile, keyFile, string(certPEM)
+}
+
+// Test_certificateSummary unit tests for certificateSummary() function
+func Test_certificateSummary(t *testing.T) {
+
+	t.Run(">>> certificateSummary: scenario 1 - subject, SANs and expiry", func(t *testing.T) {
+
+		notAfter := time.Date(2027, time.January, 15, 12, 0, 0, 0, time.UTC)
+		_, _, certPEM := writeCertificateFiles(t, "api.example.com", []string{"api.example.com", "www.example.com"}, notAfter)
+
+		want := "CN=api.example.com [api.example.com www.example.com] expires 2027-01-15T12:00:00Z"
+		got := certificateSummary(certPEM)
+
+		//	check the invocation result
+		if want != got {
+			t.Errorf("failed summarizing certificate: expected: %s result: %s", want, got)
+		}
+	})
+
+	t.Run(">>> certificateSummary: scenario 2 - invalid certificate", func(t *testing.T) {
+
+		want := "invalid certificate: invalid PEM certificate"
+		got := certificateSummary("not a certificate")
+
+		//	check the invocation result
+		if want != got {
+			t.Errorf("failed summarizing certificate: expected: %s result: %s", want, got)
+		}
+	})
+}
+
+// Test_AddCertificate unit tests for AddCertificate() method
+func Test_AddCertificate(t *testing.T) {
+
+	t.Run(">>> AddCertificate: scenario 1 - error with the request", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("fail sending add certificate command to Kong: 400 Bad Request")
+		got := kongServer.AddCertificate(&KongCertificate{}, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed adding certificate: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> AddCertificate: scenario 2 - certificate created successfuly", func(t *testing.T) {
+
+		certFile, keyFile, _ := writeCertificateFiles(t, "api.example.com", []string{"api.example.com"}, time.Now().Add(time.Hour))
+		var request KongCertificateRequest
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&request)
+
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{
+				"id": "7d3a8c2e-2f1b-4b1e-9a55-2a0c1a3d4f10",
+				"snis": [],
+				"tags": null
+			}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"add", "certificate", "--cert-file=" + certFile, "--key-file=" + keyFile, "--tags=edge"}, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed adding certificate: success expected: result: %s", got.Error())
+		}
+
+		if len(request.Cert) == 0 || len(request.Key) == 0 || len(request.Tags) != 1 || request.Tags[0] != "edge" {
+			t.Errorf("failed adding certificate: PEM content expected in the request: %+v", request)
+		}
+	})
+
+	t.Run(">>> AddCertificate: scenario 3 - key file without a private key", func(t *testing.T) {
+
+		certFile, _, _ := writeCertificateFiles(t, "api.example.com", nil, time.Now().Add(time.Hour))
+
+		want := errors.New("no PEM private key found in file: " + certFile)
+		got := kconf(NewKongServer("http://localhost:8001", 0), []string{"add", "certificate", "--cert-file=" + certFile, "--key-file=" + certFile}, Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed reading certificate files: error expected: %v result: %v", want, got)
+		}
+	})
+}
+
+// Test_QueryCertificate unit tests for QueryCertificate() method
+func Test_QueryCertificate(t *testing.T) {
+
+	t.Run(">>> QueryCertificate: scenario 1 - certificate not found", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("certificate not found")
+		got := kongServer.QueryCertificate("7d3a8c2e-2f1b-4b1e-9a55-2a0c1a3d4f10", Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed querying certificate: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> QueryCertificate: scenario 2 - certificate found", func(t *testing.T) {
+
+		_, _, certPEM := writeCertificateFiles(t, "api.example.com", []string{"api.example.com"}, time.Now().Add(time.Hour))
+		certificate, _ := json.Marshal(KongCertificateResponse{Id: "7d3a8c2e-2f1b-4b1e-9a55-2a0c1a3d4f10", Cert: certPEM})
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write(certificate)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kongServer.QueryCertificate("7d3a8c2e-2f1b-4b1e-9a55-2a0c1a3d4f10", Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got != nil {
+			t.Errorf("failed querying certificate: success expected: result: %s", got.Error())
+		}
+	})
+}
+
+// Test_ListCertificates unit tests for ListCertificates() method
+func Test_ListCertificates(t *testing.T) {
+
+	t.Run(">>> ListCertificates: scenario 1 - certificates listed", func(t *testing.T) {
+
+		_, _, certPEM := writeCertificateFiles(t, "api.example.com", []string{"api.example.com"}, time.Now().Add(time.Hour))
+		certificates, _ := json.Marshal(KongCertificateListResponse{Data: []KongCertificateResponse{
+			{Id: "7d3a8c2e-2f1b-4b1e-9a55-2a0c1a3d4f10", Cert: certPEM, Snis: []string{"api.example.com"}},
+		}})
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write(certificates)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kongServer.ListCertificates(nil, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got != nil {
+			t.Errorf("failed listing certificates: success expected: result: %s", got.Error())
+		}
+	})
+}
+
+// Test_DeleteCertificate unit tests for DeleteCertificate() method
+func Test_DeleteCertificate(t *testing.T) {
+
+	t.Run(">>> DeleteCertificate: scenario 1 - certificate deleted", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kongServer.DeleteCertificate("7d3a8c2e-2f1b-4b1e-9a55-2a0c1a3d4f10", Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got != nil {
+			t.Errorf("failed deleting certificate: success expected: result: %s", got.Error())
+		}
+	})
+}
+
+// Test_parsePeriod unit tests for parsePeriod() function
+func Test_parsePeriod(t *testing.T) {
+
+	t.Run(">>> parsePeriod: scenario 1 - days and durations", func(t *testing.T) {
+
+		for period, want := range map[string]time.Duration{"30d": 30 * 24 * time.Hour, "0d": 0, "12h": 12 * time.Hour} {
+			got, err := parsePeriod(period)
+			if err != nil || want != got {
+				t.Errorf("failed parsing period %s: expected: %s result: %s %v", period, want, got, err)
+			}
+		}
+	})
+
+	t.Run(">>> parsePeriod: scenario 2 - invalid period", func(t *testing.T) {
+
+		for _, period := range []string{"thirty", "-3d", "3w"} {
+			_, err := parsePeriod(period)
+			if err == nil {
+				t.Errorf("failed parsing period %s: error expected", period)
+			}
+		}
+	})
+}
+
+// Test_ListExpiringCertificates unit tests for ListExpiringCertificates() method
+func Test_ListExpiringCertificates(t *testing.T) {
+
+	_, _, expiringPEM := writeCertificateFiles(t, "old.example.com", []string{"old.example.com"}, time.Now().Add(10*24*time.Hour))
+	_, _, validPEM := writeCertificateFiles(t, "new.example.com", []string{"new.example.com"}, time.Now().Add(100*24*time.Hour))
+	certificates, _ := json.Marshal(KongCertificateListResponse{Data: []KongCertificateResponse{
+		{Id: "7d3a8c2e-2f1b-4b1e-9a55-2a0c1a3d4f10", Cert: expiringPEM, Snis: []string{"old.example.com"}},
+		{Id: "9a1b2c3d-4e5f-4a6b-8c7d-0e1f2a3b4c5d", Cert: validPEM, Snis: []string{"new.example.com"}},
+	}})
+
+	//	mock for Kong Admin
+	var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+		w.Write(certificates)
+	}))
+	defer mockKongAdmin.Close()
+
+	//	connect to mock server
+	kongServer := NewKongServer(mockKongAdmin.URL, 0)
+	if kongServer == nil {
+		t.Errorf("fail connectring to mock Kong Admin")
+	}
+
+	t.Run(">>> ListExpiringCertificates: scenario 1 - certificate expiring within 30 days", func(t *testing.T) {
+
+		got := kconf(kongServer, []string{"certificate", "expiring", "--within=30d"}, Options{})
+
+		//	check the invocation result
+		if !errors.Is(got, errCertificatesExpiring) {
+			t.Errorf("failed listing expiring certificates: error expected: %v result: %v", errCertificatesExpiring, got)
+		}
+	})
+
+	t.Run(">>> ListExpiringCertificates: scenario 2 - no certificate expiring within 5 days", func(t *testing.T) {
+
+		got := kconf(kongServer, []string{"certificate", "expiring", "--within=5d"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Errorf("failed listing expiring certificates: success expected: result: %s", got.Error())
+		}
+	})
+
+	t.Run(">>> ListExpiringCertificates: scenario 3 - invalid certificate reported", func(t *testing.T) {
+
+		invalidCertificates, _ := json.Marshal(KongCertificateListResponse{Data: []KongCertificateResponse{
+			{Id: "9a1b2c3d-4e5f-4a6b-8c7d-0e1f2a3b4c5d", Cert: validPEM, Snis: []string{"new.example.com"}},
+			{Id: "3c4d5e6f-7a8b-4c9d-8e0f-1a2b3c4d5e6f", Cert: "-----BEGIN CERTIFICATE-----", Snis: []string{"bad.example.com"}},
+		}})
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write(invalidCertificates)
+		}))
+		defer mockKongAdmin.Close()
+
+		got := kconf(NewKongServer(mockKongAdmin.URL, 0), []string{"certificate", "expiring", "--within=5d"}, Options{})
+
+		//	check the invocation result
+		if !errors.Is(got, errCertificatesExpiring) {
+			t.Errorf("failed listing expiring certificates: error expected: %v result: %v", errCertificatesExpiring, got)
+		}
+	})
+}
+
+// Test_expiryPeriod unit tests for expiryPeriod() function
+func Test_expiryPeriod(t *testing.T) {
+
+	var testScenarios = []struct {
+		remaining time.Duration
+		want      string
+	}{
+		{remaining: 12 * 24 * time.Hour, want: "12 days"},
+		{remaining: 30 * time.Hour, want: "1 day"},
+		{remaining: 5*time.Hour + 10*time.Minute, want: "6 hours"},
+		{remaining: -20 * time.Minute, want: "1 hour"},
+		{remaining: -3 * 24 * time.Hour, want: "3 days"},
+	}
+
+	for i, test := range testScenarios {
+		t.Run(">>> expiryPeriod: scenario "+strconv.Itoa(i+1), func(t *testing.T) {
+
+			got := expiryPeriod(test.remaining)
+
+			if got != test.want {
+				t.Errorf("failed checking expiry period: expected: %s result: %s", test.want, got)
+			}
+		})
+	}
+}
+
+// Test_generateCertificate unit tests for generateCertificate() function
+func Test_generateCertificate(t *testing.T) {
+
+	t.Run(">>> generateCertificate: scenario 1 - self signed certificate", func(t *testing.T) {
+
+		certPEM, keyPEM, err := generateCertificate("dev.example.com", []string{"*.dev.example.com", "127.0.0.1"}, 24*time.Hour, "", "")
+		if err != nil {
+			t.Fatalf("failed generating certificate: success expected: result: %s", err.Error())
+		}
+
+		certificate, err := parsePEMCertificate(certPEM)
+		if err != nil {
+			t.Fatalf("failed parsing generated certificate: %s", err.Error())
+		}
+
+		err = certificate.CheckSignature(certificate.SignatureAlgorithm, certificate.RawTBSCertificate, certificate.Signature)
+		if len(certificate.DNSNames) != 2 || len(certificate.IPAddresses) != 1 || err != nil {
+			t.Errorf("failed generating certificate: self signed certificate with SANs expected: %v %v", certificate.DNSNames, certificate.IPAddresses)
+		}
+
+		if _, err = parsePEMPrivateKey(keyPEM); err != nil {
+			t.Errorf("failed generating certificate: valid private key expected: %s", err.Error())
+		}
+	})
+
+	t.Run(">>> generateCertificate: scenario 2 - CA signed certificate", func(t *testing.T) {
+
+		caKey, _ := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+		caTemplate := &x509.Certificate{
+			SerialNumber:          big.NewInt(1),
+			Subject:               pkix.Name{CommonName: "Dev CA"},
+			NotBefore:             time.Now().Add(-time.Hour),
+			NotAfter:              time.Now().Add(time.Hour),
+			KeyUsage:              x509.KeyUsageCertSign,
+			BasicConstraintsValid: true,
+			IsCA:                  true,
+		}
+		caDER, _ := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+		caKeyDER, _ := x509.MarshalECPrivateKey(caKey)
+
+		caCertPEM := string(pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: caDER}))
+		caKeyPEM := string(pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: caKeyDER}))
+
+		certPEM, _, err := generateCertificate("dev.example.com", nil, 24*time.Hour, caCertPEM, caKeyPEM)
+		if err != nil {
+			t.Fatalf("failed generating certificate: success expected: result: %s", err.Error())
+		}
+
+		caCertificate, _ := parsePEMCertificate(caCertPEM)
+		certificate, _ := parsePEMCertificate(certPEM)
+
+		if certificate.Issuer.CommonName != "Dev CA" || certificate.CheckSignatureFrom(caCertificate) != nil {
+			t.Errorf("failed generating certificate: certificate signed by Dev CA expected: issuer: %s", certificate.Issuer)
+		}
+	})
+
+	t.Run(">>> generateCertificate: scenario 3 - generated certificate uploaded", func(t *testing.T) {
+
+		var request KongCertificateRequest
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&request)
+
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "7d3a8c2e-2f1b-4b1e-9a55-2a0c1a3d4f10"}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"certificate", "generate", "--cn=dev.example.com", "--san=localhost,127.0.0.1", "--days=30"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed generating certificate: success expected: result: %s", got.Error())
+		}
+
+		if summary := certificateSummary(request.Cert); !strings.HasPrefix(summary, "CN=dev.example.com [dev.example.com localhost 127.0.0.1]") {
+			t.Errorf("failed generating certificate: unexpected certificate uploaded: %s", summary)
+		}
+	})
+}
@@ -0,0 +1,330 @@
+////////////////////////////////////////////////////////////////////////////////
+//	config.go  -  Oct-17-2026  -  aldebap
+//
+//	kconf config file with named contexts
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kconf config file
+type KconfConfig struct {
+	CurrentContext string         `json:"current-context,omitempty" yaml:"current-context,omitempty"`
+	Contexts       []KconfContext `json:"contexts,omitempty" yaml:"contexts,omitempty"`
+}
+
+// kconf named context: how to reach a Kong Admin API
+type KconfContext struct {
+	Name               string   `json:"name" yaml:"name"`
+	KongAddress        string   `json:"kong-address,omitempty" yaml:"kong-address,omitempty"`
+	Port               int      `json:"port,omitempty" yaml:"port,omitempty"`
+	TLS                bool     `json:"tls,omitempty" yaml:"tls,omitempty"`
+	CACert             string   `json:"ca-cert,omitempty" yaml:"ca-cert,omitempty"`
+	ClientCert         string   `json:"client-cert,omitempty" yaml:"client-cert,omitempty"`
+	ClientKey          string   `json:"client-key,omitempty" yaml:"client-key,omitempty"`
+	TLSServerName      string   `json:"tls-server-name,omitempty" yaml:"tls-server-name,omitempty"`
+	InsecureSkipVerify bool     `json:"insecure-skip-verify,omitempty" yaml:"insecure-skip-verify,omitempty"`
+	AdminToken         string   `json:"admin-token,omitempty" yaml:"admin-token,omitempty"`
+	BasicAuthUser      string   `json:"basic-auth-user,omitempty" yaml:"basic-auth-user,omitempty"`
+	BasicAuthPassword  string   `json:"basic-auth-password,omitempty" yaml:"basic-auth-password,omitempty"`
+	Headers            []string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Output             string   `json:"output,omitempty" yaml:"output,omitempty"`
+	Workspace          string   `json:"workspace,omitempty" yaml:"workspace,omitempty"`
+}
+
+const (
+	contextEnvironmentVariable string = "KCONF_CONTEXT"
+
+	contextOutputText string = "text"
+	contextOutputJSON string = "json"
+
+	redactedSecret string = "********"
+)
+
+// the kconf config file: $XDG_CONFIG_HOME/kconf/config.yaml, by default ~/.config/kconf/config.yaml
+func kconfConfigFile() (string, error) {
+
+	configDir := os.Getenv("XDG_CONFIG_HOME")
+	if len(configDir) == 0 {
+		homeDir, err := os.UserHomeDir()
+		if err != nil {
+			return "", err
+		}
+		configDir = filepath.Join(homeDir, ".config")
+	}
+
+	return filepath.Join(configDir, "kconf", "config.yaml"), nil
+}
+
+// load the kconf config file: a missing file is an empty config
+func LoadKconfConfig(fileName string) (*KconfConfig, error) {
+
+	var config KconfConfig
+
+	content, err := os.ReadFile(fileName)
+	if errors.Is(err, os.ErrNotExist) {
+		return &config, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(content))
+	decoder.KnownFields(true)
+
+	err = decoder.Decode(&config)
+	if err != nil && !errors.Is(err, io.EOF) {
+		return nil, errors.New("fail parsing config file " + fileName + ": " + err.Error())
+	}
+
+	return &config, nil
+}
+
+// save the kconf config file: it's only readable by the user since it may hold credentials
+func (config *KconfConfig) save(fileName string) error {
+
+	err := os.MkdirAll(filepath.Dir(fileName), 0700)
+	if err != nil {
+		return err
+	}
+
+	var content bytes.Buffer
+
+	encoder := yaml.NewEncoder(&content)
+	encoder.SetIndent(2)
+
+	err = encoder.Encode(config)
+	if err != nil {
+		return err
+	}
+
+	return os.WriteFile(fileName, content.Bytes(), 0600)
+}
+
+// find a context by name
+func (config *KconfConfig) context(name string) *KconfContext {
+
+	for i := range config.Contexts {
+		if config.Contexts[i].Name == name {
+			return &config.Contexts[i]
+		}
+	}
+
+	return nil
+}
+
+// select the context to be used: -context option, then KCONF_CONTEXT environment variable, then the current context
+func (config *KconfConfig) selectContext(contextOption string) (*KconfContext, error) {
+
+	name := contextOption
+	if len(name) == 0 {
+		name = os.Getenv(contextEnvironmentVariable)
+	}
+	if len(name) == 0 {
+		name = config.CurrentContext
+	}
+	if len(name) == 0 {
+		return nil, nil
+	}
+
+	context := config.context(name)
+	if context == nil {
+		return nil, errors.New("unknown context: " + name)
+	}
+
+	return context, nil
+}
+
+// load the context selected by the -context option, KCONF_CONTEXT environment variable or the current context
+func loadContext(contextOption string) (*KconfContext, error) {
+
+	configFile, err := kconfConfigFile()
+	if err != nil {
+		return nil, err
+	}
+
+	config, err := LoadKconfConfig(configFile)
+	if err != nil {
+		return nil, err
+	}
+
+	return config.selectContext(contextOption)
+}
+
+// list all contexts, marking the current one
+func (config *KconfConfig) listContexts(options Options) error {
+
+	if options.jsonOutput {
+		var names []string
+
+		for _, context := range config.Contexts {
+			names = append(names, context.Name)
+		}
+
+		payload, err := json.Marshal(struct {
+			CurrentContext string   `json:"current-context"`
+			Contexts       []string `json:"contexts"`
+		}{config.CurrentContext, names})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s\n", string(payload))
+		return nil
+	}
+
+	if len(config.Contexts) == 0 {
+		fmt.Printf("No contexts\n")
+		return nil
+	}
+
+	for _, context := range config.Contexts {
+		current := " "
+		if context.Name == config.CurrentContext {
+			current = "*"
+		}
+		fmt.Printf("%s %s\n", current, context.Name)
+	}
+
+	return nil
+}
+
+// show a context with its secrets redacted
+func (context KconfContext) show(options Options) error {
+
+	if len(context.AdminToken) > 0 {
+		context.AdminToken = redactedSecret
+	}
+	if len(context.BasicAuthPassword) > 0 {
+		context.BasicAuthPassword = redactedSecret
+	}
+
+	if options.jsonOutput {
+		payload, err := json.Marshal(context)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s\n", string(payload))
+		return nil
+	}
+
+	var content bytes.Buffer
+
+	encoder := yaml.NewEncoder(&content)
+	encoder.SetIndent(2)
+
+	err := encoder.Encode(context)
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s", content.String())
+
+	return nil
+}
+
+// create or update a context attribute
+func (context *KconfContext) set(attribute string, value string) error {
+
+	var err error
+
+	switch attribute {
+	case "kong-address":
+		context.KongAddress = value
+
+	case "port":
+		context.Port, err = strconv.Atoi(value)
+
+	case "tls":
+		context.TLS, err = contextBoolValue(value)
+
+	case "ca-cert":
+		context.CACert = value
+
+	case "client-cert":
+		context.ClientCert = value
+
+	case "client-key":
+		context.ClientKey = value
+
+	case "tls-server-name":
+		context.TLSServerName = value
+
+	case "insecure-skip-verify":
+		context.InsecureSkipVerify, err = contextBoolValue(value)
+
+	case "admin-token":
+		context.AdminToken = value
+
+	case "basic-auth-user":
+		context.BasicAuthUser = value
+
+	case "basic-auth-password":
+		context.BasicAuthPassword = value
+
+	case "header":
+		//	headers are added one at a time, and an empty header removes all of them
+		if len(value) == 0 {
+			context.Headers = nil
+		} else {
+			context.Headers = append(context.Headers, value)
+		}
+
+	case "output":
+		if value != contextOutputText && value != contextOutputJSON {
+			return errors.New("wrong value for option --output: " + value + ": must be text or json")
+		}
+		context.Output = value
+
+	case "workspace":
+		context.Workspace = value
+
+	default:
+		return errors.New("invalid context attribute: " + attribute + ": available attributes: " + contextAttributes())
+	}
+
+	if err != nil {
+		return errors.New("wrong value for option --" + attribute + ": " + value)
+	}
+
+	return nil
+}
+
+// parse a boolean context attribute
+func contextBoolValue(value string) (bool, error) {
+
+	switch value {
+	case "true":
+		return true, nil
+
+	case "false":
+		return false, nil
+	}
+
+	return false, errors.New("invalid boolean value")
+}
+
+// the attributes of a context in the config file
+func contextAttributes() string {
+
+	attributes := []string{"kong-address", "port", "tls", "ca-cert", "client-cert", "client-key", "tls-server-name",
+		"insecure-skip-verify", "admin-token", "basic-auth-user", "basic-auth-password", "header", "output", "workspace"}
+	sort.Strings(attributes)
+
+	return strings.Join(attributes, ", ")
+}
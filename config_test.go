@@ -0,0 +1,140 @@
+////////////////////////////////////////////////////////////////////////////////
+//	config_test.go  -  Oct-17-2026  -  aldebap
+//
+//	Test cases for kconf config file with named contexts
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"errors"
+	"testing"
+)
+
+// Test_selectContext unit tests for selectContext() method
+func Test_selectContext(t *testing.T) {
+
+	config := &KconfConfig{
+		CurrentContext: "dev",
+		Contexts: []KconfContext{
+			{Name: "dev", KongAddress: "localhost"},
+			{Name: "staging", KongAddress: "kong.staging"},
+			{Name: "prod", KongAddress: "kong.prod", Port: 8444, TLS: true},
+		},
+	}
+
+	t.Run(">>> selectContext: scenario 1 - current context", func(t *testing.T) {
+
+		t.Setenv(contextEnvironmentVariable, "")
+
+		got, err := config.selectContext("")
+		if err != nil || got == nil || got.Name != "dev" {
+			t.Errorf("failed selecting context: dev expected: result: %+v %v", got, err)
+		}
+	})
+
+	t.Run(">>> selectContext: scenario 2 - environment variable over current context", func(t *testing.T) {
+
+		t.Setenv(contextEnvironmentVariable, "staging")
+
+		got, err := config.selectContext("")
+		if err != nil || got == nil || got.Name != "staging" {
+			t.Errorf("failed selecting context: staging expected: result: %+v %v", got, err)
+		}
+	})
+
+	t.Run(">>> selectContext: scenario 3 - option over environment variable", func(t *testing.T) {
+
+		t.Setenv(contextEnvironmentVariable, "staging")
+
+		got, err := config.selectContext("prod")
+		if err != nil || got == nil || got.Name != "prod" {
+			t.Errorf("failed selecting context: prod expected: result: %+v %v", got, err)
+		}
+	})
+
+	t.Run(">>> selectContext: scenario 4 - unknown context", func(t *testing.T) {
+
+		t.Setenv(contextEnvironmentVariable, "")
+
+		want := errors.New("unknown context: qa")
+		_, got := config.selectContext("qa")
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed selecting context: error expected: %v result: %v", want, got)
+		}
+	})
+}
+
+// Test_commandContext unit tests for context command
+func Test_commandContext(t *testing.T) {
+
+	t.Setenv("XDG_CONFIG_HOME", t.TempDir())
+	t.Setenv(contextEnvironmentVariable, "")
+
+	t.Run(">>> commandContext: scenario 1 - set contexts", func(t *testing.T) {
+
+		err := kconf(nil, []string{"context", "set", "dev", "--kong-address=localhost", "--port=8001"}, Options{})
+		if err != nil {
+			t.Fatalf("failed setting context: success expected: result: %s", err.Error())
+		}
+
+		err = kconf(nil, []string{"context", "set", "prod", "--kong-address=kong.prod", "--port=8444", "--tls=true",
+			"--admin-token=secret", "--header=apikey: admin-key", "--output=json", "--workspace=payments"}, Options{})
+		if err != nil {
+			t.Fatalf("failed setting context: success expected: result: %s", err.Error())
+		}
+
+		configFile, _ := kconfConfigFile()
+		config, err := LoadKconfConfig(configFile)
+		if err != nil {
+			t.Fatalf("failed loading config file: success expected: result: %s", err.Error())
+		}
+
+		if config.CurrentContext != "dev" || len(config.Contexts) != 2 {
+			t.Errorf("failed setting context: dev as current context expected: result: %+v", config)
+		}
+
+		prod := config.context("prod")
+		if prod == nil || prod.Port != 8444 || !prod.TLS || prod.AdminToken != "secret" || prod.Headers[0] != "apikey: admin-key" ||
+			prod.Output != contextOutputJSON || prod.Workspace != "payments" {
+			t.Errorf("failed setting context: unexpected prod context: %+v", prod)
+		}
+	})
+
+	t.Run(">>> commandContext: scenario 2 - use context", func(t *testing.T) {
+
+		err := kconf(nil, []string{"context", "use", "prod"}, Options{})
+		if err != nil {
+			t.Fatalf("failed using context: success expected: result: %s", err.Error())
+		}
+
+		got, err := loadContext("")
+		if err != nil || got == nil || got.Name != "prod" {
+			t.Errorf("failed using context: prod expected: result: %+v %v", got, err)
+		}
+	})
+
+	t.Run(">>> commandContext: scenario 3 - invalid context attribute", func(t *testing.T) {
+
+		want := errors.New("wrong value for option --port: https")
+		got := kconf(nil, []string{"context", "set", "dev", "--port=https"}, Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed setting context: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> commandContext: scenario 4 - use unknown context", func(t *testing.T) {
+
+		want := errors.New("unknown context: qa")
+		got := kconf(nil, []string{"context", "use", "qa"}, Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed using context: error expected: %v result: %v", want, got)
+		}
+	})
+}
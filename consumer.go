@@ -7,9 +7,7 @@
 package main
 
 import (
-	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -71,21 +69,14 @@ func (ks *KongServerDomain) AddConsumer(newKongConsumer *KongConsumer, options O
 		return err
 	}
 
-	req, err := http.NewRequest("POST", consumerURL, bytes.NewBuffer([]byte(payload)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ks.sendRequest("POST", consumerURL, payload)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		return errors.New("fail sending add consumer command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending add consumer command to Kong", resp)
 	}
 
 	//	parse response payload
@@ -122,17 +113,17 @@ func (ks *KongServerDomain) QueryConsumer(id string, options Options) error {
 	var consumerURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), consumersResource, id)
 
 	//	send a request to Kong to query the consumer by id
-	resp, err := http.Get(consumerURL)
+	resp, err := ks.sendRequest("GET", consumerURL, nil)
 	if err != nil {
 		return err
 	}
 
 	if resp.StatusCode == http.StatusNotFound {
-		return errors.New("consumer not found")
+		return newKongNotFoundError("consumer", resp)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.New("fail sending query consumer command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending query consumer command to Kong", resp)
 	}
 
 	var respPayload []byte
@@ -165,29 +156,21 @@ func (ks *KongServerDomain) QueryConsumer(id string, options Options) error {
 }
 
 // query a consumer by Id
-func (ks *KongServerDomain) ListConsumers(options Options) error {
-
-	var consumerURL string = fmt.Sprintf("%s/%s/", ks.ServerURL(), consumersResource)
+func (ks *KongServerDomain) ListConsumers(pagination *KongPagination, options Options) error {
 
 	//	send a request to Kong to get a list of all consumers
-	resp, err := http.Get(consumerURL)
+	collection, status, err := ks.fetchCollection(consumersResource, pagination, "fail sending list consumers command to Kong")
 	if err != nil {
 		return err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return errors.New("fail sending list consumers command to Kong: " + resp.Status)
-	}
-
-	var respPayload []byte
-
-	respPayload, err = io.ReadAll(resp.Body)
+	respPayload, err := json.Marshal(collection)
 	if err != nil {
 		return err
 	}
 
 	if options.jsonOutput {
-		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+		fmt.Printf("%s\n%s\n", status, string(respPayload))
 	} else {
 		var consumerListResp KongConsumerListResponse
 
@@ -198,7 +181,7 @@ func (ks *KongServerDomain) ListConsumers(options Options) error {
 
 		if len(consumerListResp.Data) == 0 {
 			if options.verbose {
-				fmt.Printf("%s\nNo consumers\n", resp.Status)
+				fmt.Printf("%s\nNo consumers\n", status)
 			} else {
 				fmt.Printf("No consumers\n")
 			}
@@ -207,13 +190,14 @@ func (ks *KongServerDomain) ListConsumers(options Options) error {
 		}
 
 		if options.verbose {
-			fmt.Printf("http response status code: %s\nconsumer list\n", resp.Status)
+			fmt.Printf("http response status code: %s\nconsumer list\n", status)
 		}
 
 		for _, consumer := range consumerListResp.Data {
 			fmt.Printf("%s: (%s) %s %s\n", consumer.Id,
 				consumer.CustomId, consumer.UserName, consumer.Tags)
 		}
+		printNextOffset(collection)
 	}
 
 	return nil
@@ -235,25 +219,18 @@ func (ks *KongServerDomain) UpdateConsumer(id string, updatedKongConsumer *KongC
 
 	//	log.Printf("[debug] patch payload: %s", payload)
 
-	req, err := http.NewRequest("PATCH", consumerURL, bytes.NewBuffer([]byte(payload)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ks.sendRequest("PATCH", consumerURL, payload)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return errors.New("consumer not found")
+		return newKongNotFoundError("consumer", resp)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.New("fail sending patch consumer command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending patch consumer command to Kong", resp)
 	}
 
 	//	parse response payload
@@ -292,21 +269,14 @@ func (ks *KongServerDomain) DeleteConsumer(id string, options Options) error {
 	var consumerURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), consumersResource, id)
 
 	//	send a request to Kong to delete the consumer by id
-	req, err := http.NewRequest("DELETE", consumerURL, bytes.NewBuffer([]byte("")))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ks.sendRequest("DELETE", consumerURL, nil)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		return errors.New("fail sending delete consumer command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending delete consumer command to Kong", resp)
 	}
 
 	if options.jsonOutput {
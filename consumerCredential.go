@@ -0,0 +1,596 @@
+////////////////////////////////////////////////////////////////////////////////
+//	consumerCredential.go  -  Oct-17-2026  -  aldebap
+//
+//	Kong consumer credentials
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+const (
+	aclsResource      string = "acls"
+	hmacAuthPlugins   string = "hmac-auth"
+	hmacAuthsResource string = "hmac-auths"
+	oauth2Plugins     string = "oauth2"
+	oauth2Resource    string = "oauth2"
+	mtlsAuthPlugins   string = "mtls-auth"
+	mtlsAuthsResource string = "mtls-auths"
+)
+
+// kong basic auth list response payload
+type KongBasicAuthListResponse struct {
+	Data []KongBasicAuthResponse `json:"data"`
+	Next string                  `json:"next"`
+}
+
+// kong key auth list response payload
+type KongKeyAuthListResponse struct {
+	Data []KongKeyAuthResponse `json:"data"`
+	Next string                `json:"next"`
+}
+
+// kong JWT list response payload
+type KongJWTListResponse struct {
+	Data []KongJWTResponse `json:"data"`
+	Next string            `json:"next"`
+}
+
+// the credentials collection: the consumer nested one, or the global one when no consumer is given
+func consumerCredentialsResource(id string, credentialPlugin string, globalResource string) string {
+
+	if len(id) == 0 {
+		return globalResource
+	}
+
+	return fmt.Sprintf("%s/%s/%s", consumersResource, id, credentialPlugin)
+}
+
+// fetch the credentials collection and print it as JSON when asked to
+func (ks *KongServerDomain) fetchConsumerCredentials(resource string, description string, pagination *KongPagination,
+	list interface{}, options Options) (*KongCollectionPage, string, bool, error) {
+
+	collection, status, err := ks.fetchCollection(resource, pagination, "fail sending list consumer "+description+" command to Kong")
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	respPayload, err := json.Marshal(collection)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", status, string(respPayload))
+		return collection, status, true, nil
+	}
+
+	err = json.Unmarshal(respPayload, list)
+	if err != nil {
+		return nil, "", false, err
+	}
+
+	return collection, status, false, nil
+}
+
+// print the header of a credentials list, or a message when it is empty
+func printConsumerCredentialsHeader(status string, description string, count int, options Options) bool {
+
+	if count == 0 {
+		if options.verbose {
+			fmt.Printf("%s\nNo consumer %s credentials\n", status, description)
+		} else {
+			fmt.Printf("No consumer %s credentials\n", description)
+		}
+
+		return false
+	}
+
+	if options.verbose {
+		fmt.Printf("http response status code: %s\nconsumer %s credential list\n", status, description)
+	}
+
+	return true
+}
+
+// the consumer of a credential: Kong may omit it in some responses
+func credentialConsumerId(consumer *KongConsumerID) string {
+
+	if consumer == nil {
+		return ""
+	}
+
+	return consumer.Id
+}
+
+// list the basic auth credentials of a consumer, or of all consumers
+func (ks *KongServerDomain) ListConsumerBasicAuths(id string, pagination *KongPagination, options Options) error {
+
+	var basicAuthListResp KongBasicAuthListResponse
+
+	collection, status, done, err := ks.fetchConsumerCredentials(consumerCredentialsResource(id, basicAuthPlugins, basicAuthsResource),
+		"basic auth", pagination, &basicAuthListResp, options)
+	if err != nil || done {
+		return err
+	}
+
+	if printConsumerCredentialsHeader(status, "basic auth", len(basicAuthListResp.Data), options) {
+		for _, basicAuth := range basicAuthListResp.Data {
+			fmt.Printf("%s: %s --> consumer: %s (%s)\n", basicAuth.Id, basicAuth.UserName, basicAuth.Consumer.Id, basicAuth.Tags)
+		}
+		printNextOffset(collection)
+	}
+
+	return nil
+}
+
+// list the key auth credentials of a consumer, or of all consumers
+func (ks *KongServerDomain) ListConsumerKeyAuths(id string, pagination *KongPagination, options Options) error {
+
+	var keyAuthListResp KongKeyAuthListResponse
+
+	collection, status, done, err := ks.fetchConsumerCredentials(consumerCredentialsResource(id, keyAuthPlugins, keyAuthsResource),
+		"key auth", pagination, &keyAuthListResp, options)
+	if err != nil || done {
+		return err
+	}
+
+	if printConsumerCredentialsHeader(status, "key auth", len(keyAuthListResp.Data), options) {
+		for _, keyAuth := range keyAuthListResp.Data {
+			fmt.Printf("%s: %s (ttl: %d) --> consumer: %s (%s)\n", keyAuth.Id, keyAuth.Key, keyAuth.Ttl, keyAuth.Consumer.Id, keyAuth.Tags)
+		}
+		printNextOffset(collection)
+	}
+
+	return nil
+}
+
+// list the JWT credentials of a consumer, or of all consumers
+func (ks *KongServerDomain) ListConsumerJWTs(id string, pagination *KongPagination, options Options) error {
+
+	var jwtListResp KongJWTListResponse
+
+	collection, status, done, err := ks.fetchConsumerCredentials(consumerCredentialsResource(id, jwtPlugins, jwtsResource),
+		"JWT", pagination, &jwtListResp, options)
+	if err != nil || done {
+		return err
+	}
+
+	if printConsumerCredentialsHeader(status, "JWT", len(jwtListResp.Data), options) {
+		for _, jwt := range jwtListResp.Data {
+			fmt.Printf("%s: %s %s --> consumer: %s (%s)\n", jwt.Id, jwt.Algorithm, jwt.Key, credentialConsumerId(jwt.Consumer), jwt.Tags)
+		}
+		printNextOffset(collection)
+	}
+
+	return nil
+}
+
+// delete a credential of a consumer in Kong
+func (ks *KongServerDomain) deleteConsumerCredential(id string, credentialPlugin string, credentialId string, description string, options Options) error {
+
+	var credentialURL string = fmt.Sprintf("%s/%s/%s/%s/%s", ks.ServerURL(), consumersResource, id, credentialPlugin, credentialId)
+
+	//	send a request to Kong to delete the credential by id
+	resp, err := ks.sendRequest("DELETE", credentialURL, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return newKongNotFoundError("consumer "+description+" credential", resp)
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newKongAPIError("fail sending delete consumer "+description+" command to Kong", resp)
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n{}\n", resp.Status)
+	} else {
+		if options.verbose {
+			fmt.Printf("http response status code: %s\n", resp.Status)
+		}
+	}
+
+	return nil
+}
+
+// delete a basic auth credential of a consumer
+func (ks *KongServerDomain) DeleteConsumerBasicAuth(id string, credentialId string, options Options) error {
+
+	return ks.deleteConsumerCredential(id, basicAuthPlugins, credentialId, "basic auth", options)
+}
+
+// delete a key auth credential of a consumer
+func (ks *KongServerDomain) DeleteConsumerKeyAuth(id string, credentialId string, options Options) error {
+
+	return ks.deleteConsumerCredential(id, keyAuthPlugins, credentialId, "key auth", options)
+}
+
+// delete a JWT credential of a consumer
+func (ks *KongServerDomain) DeleteConsumerJWT(id string, credentialId string, options Options) error {
+
+	return ks.deleteConsumerCredential(id, jwtPlugins, credentialId, "JWT", options)
+}
+
+// add a credential to a consumer in Kong and print its id
+func (ks *KongServerDomain) addConsumerCredential(id string, credentialPlugin string, request interface{}, description string, options Options) error {
+
+	var credentialURL string = fmt.Sprintf("%s/%s/%s/%s", ks.ServerURL(), consumersResource, id, credentialPlugin)
+
+	payload, err := json.Marshal(request)
+	if err != nil {
+		return err
+	}
+
+	resp, err := ks.sendRequest("POST", credentialURL, payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return newKongNotFoundError("consumer", resp)
+	}
+
+	if resp.StatusCode != http.StatusCreated {
+		return newKongAPIError("fail sending add consumer "+description+" command to Kong", resp)
+	}
+
+	//	parse response payload
+	var respPayload []byte
+
+	respPayload, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var credentialResp struct {
+		Id string `json:"id"`
+	}
+
+	err = json.Unmarshal(respPayload, &credentialResp)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+	} else {
+		if options.verbose {
+			fmt.Printf("http response status code: %s\nnew %s credential ID: %s\n", resp.Status, description, credentialResp.Id)
+		} else {
+			fmt.Printf("%s\n", credentialResp.Id)
+		}
+	}
+
+	return nil
+}
+
+// kong ACL config attributes
+type KongACLConfig struct {
+	group string
+	tags  []string
+}
+
+// create a new kong ACL config
+func NewKongACLConfig(group string, tags []string) *KongACLConfig {
+
+	return &KongACLConfig{
+		group: group,
+		tags:  tags,
+	}
+}
+
+// kong consumer ACL request payload
+type KongACLRequest struct {
+	Group string   `json:"group,omitempty"`
+	Tags  []string `json:"tags,omitempty"`
+}
+
+// kong consumer ACL response payload
+type KongACLResponse struct {
+	Id       string         `json:"id"`
+	Consumer KongConsumerID `json:"consumer"`
+	Group    string         `json:"group"`
+	Tags     []string       `json:"tags"`
+}
+
+// kong consumer ACL list response payload
+type KongACLListResponse struct {
+	Data []KongACLResponse `json:"data"`
+	Next string            `json:"next"`
+}
+
+// add a consumer to an ACL group
+func (ks *KongServerDomain) AddConsumerACL(id string, newKongACLConfig *KongACLConfig, options Options) error {
+
+	return ks.addConsumerCredential(id, aclsResource, KongACLRequest{
+		Group: newKongACLConfig.group,
+		Tags:  newKongACLConfig.tags,
+	}, "ACL", options)
+}
+
+// list the ACL groups of a consumer, or of all consumers
+func (ks *KongServerDomain) ListConsumerACLs(id string, pagination *KongPagination, options Options) error {
+
+	var aclListResp KongACLListResponse
+
+	collection, status, done, err := ks.fetchConsumerCredentials(consumerCredentialsResource(id, aclsResource, aclsResource),
+		"ACL", pagination, &aclListResp, options)
+	if err != nil || done {
+		return err
+	}
+
+	if printConsumerCredentialsHeader(status, "ACL", len(aclListResp.Data), options) {
+		for _, acl := range aclListResp.Data {
+			fmt.Printf("%s: %s --> consumer: %s (%s)\n", acl.Id, acl.Group, acl.Consumer.Id, acl.Tags)
+		}
+		printNextOffset(collection)
+	}
+
+	return nil
+}
+
+// remove a consumer from an ACL group
+func (ks *KongServerDomain) DeleteConsumerACL(id string, credentialId string, options Options) error {
+
+	return ks.deleteConsumerCredential(id, aclsResource, credentialId, "ACL", options)
+}
+
+// kong HMAC auth config attributes
+type KongHMACAuthConfig struct {
+	userName string
+	secret   string
+	tags     []string
+}
+
+// create a new kong HMAC auth config
+func NewKongHMACAuthConfig(userName string, secret string, tags []string) *KongHMACAuthConfig {
+
+	return &KongHMACAuthConfig{
+		userName: userName,
+		secret:   secret,
+		tags:     tags,
+	}
+}
+
+// kong consumer HMAC auth request payload
+type KongHMACAuthRequest struct {
+	UserName string   `json:"username,omitempty"`
+	Secret   string   `json:"secret,omitempty"`
+	Tags     []string `json:"tags,omitempty"`
+}
+
+// kong consumer HMAC auth response payload
+type KongHMACAuthResponse struct {
+	Id       string         `json:"id"`
+	Consumer KongConsumerID `json:"consumer"`
+	UserName string         `json:"username"`
+	Secret   string         `json:"secret"`
+	Tags     []string       `json:"tags"`
+}
+
+// kong consumer HMAC auth list response payload
+type KongHMACAuthListResponse struct {
+	Data []KongHMACAuthResponse `json:"data"`
+	Next string                 `json:"next"`
+}
+
+// add a HMAC auth credential to a consumer
+func (ks *KongServerDomain) AddConsumerHMACAuth(id string, newKongHMACAuthConfig *KongHMACAuthConfig, options Options) error {
+
+	return ks.addConsumerCredential(id, hmacAuthPlugins, KongHMACAuthRequest{
+		UserName: newKongHMACAuthConfig.userName,
+		Secret:   newKongHMACAuthConfig.secret,
+		Tags:     newKongHMACAuthConfig.tags,
+	}, "HMAC auth", options)
+}
+
+// list the HMAC auth credentials of a consumer, or of all consumers
+func (ks *KongServerDomain) ListConsumerHMACAuths(id string, pagination *KongPagination, options Options) error {
+
+	var hmacAuthListResp KongHMACAuthListResponse
+
+	collection, status, done, err := ks.fetchConsumerCredentials(consumerCredentialsResource(id, hmacAuthPlugins, hmacAuthsResource),
+		"HMAC auth", pagination, &hmacAuthListResp, options)
+	if err != nil || done {
+		return err
+	}
+
+	if printConsumerCredentialsHeader(status, "HMAC auth", len(hmacAuthListResp.Data), options) {
+		for _, hmacAuth := range hmacAuthListResp.Data {
+			fmt.Printf("%s: %s --> consumer: %s (%s)\n", hmacAuth.Id, hmacAuth.UserName, hmacAuth.Consumer.Id, hmacAuth.Tags)
+		}
+		printNextOffset(collection)
+	}
+
+	return nil
+}
+
+// delete a HMAC auth credential of a consumer
+func (ks *KongServerDomain) DeleteConsumerHMACAuth(id string, credentialId string, options Options) error {
+
+	return ks.deleteConsumerCredential(id, hmacAuthPlugins, credentialId, "HMAC auth", options)
+}
+
+// kong OAuth2 application attributes
+type KongOAuth2Config struct {
+	name         string
+	clientId     string
+	clientSecret string
+	redirectURIs []string
+	tags         []string
+}
+
+// create a new kong OAuth2 application
+func NewKongOAuth2Config(name string, clientId string, clientSecret string, redirectURIs []string, tags []string) *KongOAuth2Config {
+
+	return &KongOAuth2Config{
+		name:         name,
+		clientId:     clientId,
+		clientSecret: clientSecret,
+		redirectURIs: redirectURIs,
+		tags:         tags,
+	}
+}
+
+// kong consumer OAuth2 request payload
+type KongOAuth2Request struct {
+	Name         string   `json:"name,omitempty"`
+	ClientId     string   `json:"client_id,omitempty"`
+	ClientSecret string   `json:"client_secret,omitempty"`
+	RedirectURIs []string `json:"redirect_uris,omitempty"`
+	Tags         []string `json:"tags,omitempty"`
+}
+
+// kong consumer OAuth2 response payload
+type KongOAuth2Response struct {
+	Id           string         `json:"id"`
+	Consumer     KongConsumerID `json:"consumer"`
+	Name         string         `json:"name"`
+	ClientId     string         `json:"client_id"`
+	RedirectURIs []string       `json:"redirect_uris"`
+	Tags         []string       `json:"tags"`
+}
+
+// kong consumer OAuth2 list response payload
+type KongOAuth2ListResponse struct {
+	Data []KongOAuth2Response `json:"data"`
+	Next string               `json:"next"`
+}
+
+// add an OAuth2 application to a consumer
+func (ks *KongServerDomain) AddConsumerOAuth2(id string, newKongOAuth2Config *KongOAuth2Config, options Options) error {
+
+	return ks.addConsumerCredential(id, oauth2Plugins, KongOAuth2Request{
+		Name:         newKongOAuth2Config.name,
+		ClientId:     newKongOAuth2Config.clientId,
+		ClientSecret: newKongOAuth2Config.clientSecret,
+		RedirectURIs: newKongOAuth2Config.redirectURIs,
+		Tags:         newKongOAuth2Config.tags,
+	}, "OAuth2", options)
+}
+
+// list the OAuth2 applications of a consumer, or of all consumers
+func (ks *KongServerDomain) ListConsumerOAuth2s(id string, pagination *KongPagination, options Options) error {
+
+	var oauth2ListResp KongOAuth2ListResponse
+
+	collection, status, done, err := ks.fetchConsumerCredentials(consumerCredentialsResource(id, oauth2Plugins, oauth2Resource),
+		"OAuth2", pagination, &oauth2ListResp, options)
+	if err != nil || done {
+		return err
+	}
+
+	if printConsumerCredentialsHeader(status, "OAuth2", len(oauth2ListResp.Data), options) {
+		for _, oauth2 := range oauth2ListResp.Data {
+			fmt.Printf("%s: %s - client id: %s - redirect uris: %s --> consumer: %s (%s)\n", oauth2.Id, oauth2.Name, oauth2.ClientId,
+				oauth2.RedirectURIs, oauth2.Consumer.Id, oauth2.Tags)
+		}
+		printNextOffset(collection)
+	}
+
+	return nil
+}
+
+// delete an OAuth2 application of a consumer
+func (ks *KongServerDomain) DeleteConsumerOAuth2(id string, credentialId string, options Options) error {
+
+	return ks.deleteConsumerCredential(id, oauth2Plugins, credentialId, "OAuth2", options)
+}
+
+// kong mTLS auth config attributes
+type KongMTLSAuthConfig struct {
+	subjectName     string
+	caCertificateId string
+	tags            []string
+}
+
+// create a new kong mTLS auth config
+func NewKongMTLSAuthConfig(subjectName string, caCertificateId string, tags []string) *KongMTLSAuthConfig {
+
+	return &KongMTLSAuthConfig{
+		subjectName:     subjectName,
+		caCertificateId: caCertificateId,
+		tags:            tags,
+	}
+}
+
+// kong consumer mTLS auth request payload
+type KongMTLSAuthRequest struct {
+	SubjectName   string         `json:"subject_name,omitempty"`
+	CACertificate *certificateId `json:"ca_certificate,omitempty"`
+	Tags          []string       `json:"tags,omitempty"`
+}
+
+// kong consumer mTLS auth response payload
+type KongMTLSAuthResponse struct {
+	Id            string         `json:"id"`
+	Consumer      KongConsumerID `json:"consumer"`
+	SubjectName   string         `json:"subject_name"`
+	CACertificate *certificateId `json:"ca_certificate"`
+	Tags          []string       `json:"tags"`
+}
+
+// kong consumer mTLS auth list response payload
+type KongMTLSAuthListResponse struct {
+	Data []KongMTLSAuthResponse `json:"data"`
+	Next string                 `json:"next"`
+}
+
+// add a mTLS auth credential to a consumer
+func (ks *KongServerDomain) AddConsumerMTLSAuth(id string, newKongMTLSAuthConfig *KongMTLSAuthConfig, options Options) error {
+
+	request := KongMTLSAuthRequest{
+		SubjectName: newKongMTLSAuthConfig.subjectName,
+		Tags:        newKongMTLSAuthConfig.tags,
+	}
+
+	//	without a CA certificate Kong matches the subject name against any of the plugin CA certificates
+	if len(newKongMTLSAuthConfig.caCertificateId) > 0 {
+		request.CACertificate = &certificateId{Id: newKongMTLSAuthConfig.caCertificateId}
+	}
+
+	return ks.addConsumerCredential(id, mtlsAuthPlugins, request, "mTLS auth", options)
+}
+
+// list the mTLS auth credentials of a consumer, or of all consumers
+func (ks *KongServerDomain) ListConsumerMTLSAuths(id string, pagination *KongPagination, options Options) error {
+
+	var mtlsAuthListResp KongMTLSAuthListResponse
+
+	collection, status, done, err := ks.fetchConsumerCredentials(consumerCredentialsResource(id, mtlsAuthPlugins, mtlsAuthsResource),
+		"mTLS auth", pagination, &mtlsAuthListResp, options)
+	if err != nil || done {
+		return err
+	}
+
+	if printConsumerCredentialsHeader(status, "mTLS auth", len(mtlsAuthListResp.Data), options) {
+		for _, mtlsAuth := range mtlsAuthListResp.Data {
+			var caCertificate string
+
+			if mtlsAuth.CACertificate != nil {
+				caCertificate = mtlsAuth.CACertificate.Id
+			}
+			fmt.Printf("%s: %s - ca certificate: %s --> consumer: %s (%s)\n", mtlsAuth.Id, mtlsAuth.SubjectName, caCertificate,
+				mtlsAuth.Consumer.Id, mtlsAuth.Tags)
+		}
+		printNextOffset(collection)
+	}
+
+	return nil
+}
+
+// delete a mTLS auth credential of a consumer
+func (ks *KongServerDomain) DeleteConsumerMTLSAuth(id string, credentialId string, options Options) error {
+
+	return ks.deleteConsumerCredential(id, mtlsAuthPlugins, credentialId, "mTLS auth", options)
+}
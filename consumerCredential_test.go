@@ -0,0 +1,440 @@
+////////////////////////////////////////////////////////////////////////////////
+//	consumerCredential_test.go  -  Oct-17-2026  -  aldebap
+//
+//	Test cases for Kong consumer credentials listing and deletion
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test_ListConsumerBasicAuths unit tests for ListConsumerBasicAuths() method
+func Test_ListConsumerBasicAuths(t *testing.T) {
+
+	t.Run(">>> ListConsumerBasicAuths: scenario 1 - credentials of a consumer", func(t *testing.T) {
+
+		var listPath string
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			listPath = r.URL.Path
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"data": [
+					{"id": "6a2e4c1d-3b5f-4e7a-9c8d-1f2e3d4c5b6a", "username": "partner", "consumer": {"id": "2b3c4d5e-6f7a-4b8c-9d0e-1f2a3b4c5d6e"}}
+				],
+				"next": null
+			}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"list", "consumer-basic-auth", "--id=2b3c4d5e-6f7a-4b8c-9d0e-1f2a3b4c5d6e"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed listing consumer basic auth credentials: success expected: result: %s", got.Error())
+		}
+
+		if listPath != "/consumers/2b3c4d5e-6f7a-4b8c-9d0e-1f2a3b4c5d6e/basic-auth" {
+			t.Errorf("failed listing consumer basic auth credentials: nested consumer resource expected: result: %s", listPath)
+		}
+	})
+
+	t.Run(">>> ListConsumerBasicAuths: scenario 2 - internal server error", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("fail sending list consumer basic auth command to Kong: 500 Internal Server Error")
+		got := kongServer.ListConsumerBasicAuths("", nil, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed listing consumer basic auth credentials: error expected: %v result: %v", want, got)
+		}
+	})
+}
+
+// Test_ListConsumerKeyAuths unit tests for ListConsumerKeyAuths() method
+func Test_ListConsumerKeyAuths(t *testing.T) {
+
+	t.Run(">>> ListConsumerKeyAuths: scenario 1 - credentials of all consumers", func(t *testing.T) {
+
+		var listPath string
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			listPath = r.URL.Path
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"data": [
+					{"id": "7b3f5d2e-4c6a-4f8b-8d9e-2a3b4c5d6e7f", "key": "a1b2c3", "ttl": 3600, "consumer": {"id": "2b3c4d5e-6f7a-4b8c-9d0e-1f2a3b4c5d6e"}}
+				],
+				"next": null
+			}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"list", "consumer-key-auth"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed listing consumer key auth credentials: success expected: result: %s", got.Error())
+		}
+
+		if listPath != "/key-auths" {
+			t.Errorf("failed listing consumer key auth credentials: global resource expected: result: %s", listPath)
+		}
+	})
+}
+
+// Test_ListConsumerJWTs unit tests for ListConsumerJWTs() method
+func Test_ListConsumerJWTs(t *testing.T) {
+
+	t.Run(">>> ListConsumerJWTs: scenario 1 - no credentials", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data": [], "next": null}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kongServer.ListConsumerJWTs("", nil, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got != nil {
+			t.Errorf("failed listing consumer JWT credentials: success expected: result: %s", got.Error())
+		}
+	})
+}
+
+// Test_DeleteConsumerCredential unit tests for DeleteConsumerBasicAuth(), DeleteConsumerKeyAuth() and DeleteConsumerJWT() methods
+func Test_DeleteConsumerCredential(t *testing.T) {
+
+	t.Run(">>> DeleteConsumerCredential: scenario 1 - missing credential id", func(t *testing.T) {
+
+		want := errors.New("missing credential id: option --credential-id={id} required for this command")
+		got := kconf(NewKongServer("http://localhost:8001", 0), []string{"delete", "consumer-jwt", "--id=partner"}, Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed deleting consumer credential: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> DeleteConsumerCredential: scenario 2 - credential not found", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("consumer key auth credential not found")
+		got := kongServer.DeleteConsumerKeyAuth("partner", "7b3f5d2e-4c6a-4f8b-8d9e-2a3b4c5d6e7f", Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed deleting consumer credential: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> DeleteConsumerCredential: scenario 3 - credential deleted", func(t *testing.T) {
+
+		var deletePath string
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			deletePath = r.URL.Path
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"delete", "consumer-basic-auth", "--id=partner", "--credential-id=6a2e4c1d-3b5f-4e7a-9c8d-1f2e3d4c5b6a"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed deleting consumer credential: success expected: result: %s", got.Error())
+		}
+
+		if deletePath != "/consumers/partner/basic-auth/6a2e4c1d-3b5f-4e7a-9c8d-1f2e3d4c5b6a" {
+			t.Errorf("failed deleting consumer credential: unexpected path: %s", deletePath)
+		}
+	})
+}
+
+// Test_AddConsumerACL unit tests for AddConsumerACL() method
+func Test_AddConsumerACL(t *testing.T) {
+
+	t.Run(">>> AddConsumerACL: scenario 1 - missing group", func(t *testing.T) {
+
+		want := errors.New("missing ACL group: option --group={group} required for this command")
+		got := kconf(NewKongServer("http://localhost:8001", 0), []string{"add", "consumer-acl", "--id=partner"}, Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed adding consumer ACL: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> AddConsumerACL: scenario 2 - consumer not found", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("consumer not found")
+		got := kongServer.AddConsumerACL("partner", NewKongACLConfig("gold", nil), Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed adding consumer ACL: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> AddConsumerACL: scenario 3 - consumer added to the group", func(t *testing.T) {
+
+		var aclPath string
+		var request KongACLRequest
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			aclPath = r.URL.Path
+			json.NewDecoder(r.Body).Decode(&request)
+
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "8c4a6e3f-5d7b-4a9c-8e0f-3b4c5d6e7f8a", "group": "gold", "consumer": {"id": "2b3c4d5e-6f7a-4b8c-9d0e-1f2a3b4c5d6e"}}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"add", "consumer-acl", "--id=partner", "--group=gold", "--tags=plans"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed adding consumer ACL: success expected: result: %s", got.Error())
+		}
+
+		if aclPath != "/consumers/partner/acls" || request.Group != "gold" || len(request.Tags) != 1 {
+			t.Errorf("failed adding consumer ACL: unexpected request: %s %+v", aclPath, request)
+		}
+	})
+}
+
+// Test_AddConsumerHMACAuth unit tests for AddConsumerHMACAuth() method
+func Test_AddConsumerHMACAuth(t *testing.T) {
+
+	t.Run(">>> AddConsumerHMACAuth: scenario 1 - credential created", func(t *testing.T) {
+
+		var request KongHMACAuthRequest
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&request)
+
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "9d5b7f4a-6e8c-4b0d-9f1a-4c5d6e7f8a9b", "username": "partner-hmac"}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"add", "consumer-hmac-auth", "--id=partner", "--user-name=partner-hmac", "--secret=s3cr3t"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed adding consumer HMAC auth: success expected: result: %s", got.Error())
+		}
+
+		if request.UserName != "partner-hmac" || request.Secret != "s3cr3t" {
+			t.Errorf("failed adding consumer HMAC auth: unexpected request: %+v", request)
+		}
+	})
+}
+
+// Test_AddConsumerOAuth2 unit tests for AddConsumerOAuth2() method
+func Test_AddConsumerOAuth2(t *testing.T) {
+
+	t.Run(">>> AddConsumerOAuth2: scenario 1 - application created", func(t *testing.T) {
+
+		var request KongOAuth2Request
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&request)
+
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "0e6c8a5b-7f9d-4c1e-8a2b-5d6e7f8a9b0c", "name": "partner-app"}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"add", "consumer-oauth2", "--id=partner", "--name=partner-app", "--client-id=partner",
+			"--redirect-uris=https://partner.example.com/callback,https://partner.example.com/alt"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed adding consumer OAuth2: success expected: result: %s", got.Error())
+		}
+
+		if request.Name != "partner-app" || request.ClientId != "partner" || len(request.RedirectURIs) != 2 {
+			t.Errorf("failed adding consumer OAuth2: unexpected request: %+v", request)
+		}
+	})
+}
+
+// Test_AddConsumerMTLSAuth unit tests for AddConsumerMTLSAuth() method
+func Test_AddConsumerMTLSAuth(t *testing.T) {
+
+	t.Run(">>> AddConsumerMTLSAuth: scenario 1 - credential created with a CA certificate", func(t *testing.T) {
+
+		var request KongMTLSAuthRequest
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&request)
+
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "1f7d9b6c-8a0e-4d2f-9b3c-6e7f8a9b0c1d", "subject_name": "partner.example.com"}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"add", "consumer-mtls-auth", "--id=partner", "--subject-name=partner.example.com",
+			"--ca-certificate-id=5e6f7a8b-9c0d-4e1f-a2b3-c4d5e6f7a8b9"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed adding consumer mTLS auth: success expected: result: %s", got.Error())
+		}
+
+		if request.SubjectName != "partner.example.com" || request.CACertificate == nil || request.CACertificate.Id != "5e6f7a8b-9c0d-4e1f-a2b3-c4d5e6f7a8b9" {
+			t.Errorf("failed adding consumer mTLS auth: unexpected request: %+v", request)
+		}
+	})
+}
+
+// Test_ListConsumerOAuth2s unit tests for ListConsumerOAuth2s() method
+func Test_ListConsumerOAuth2s(t *testing.T) {
+
+	t.Run(">>> ListConsumerOAuth2s: scenario 1 - applications of all consumers", func(t *testing.T) {
+
+		var listPath string
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			listPath = r.URL.Path
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"data": [
+					{"id": "0e6c8a5b-7f9d-4c1e-8a2b-5d6e7f8a9b0c", "name": "partner-app", "client_id": "partner",
+					 "redirect_uris": ["https://partner.example.com/callback"], "consumer": {"id": "2b3c4d5e-6f7a-4b8c-9d0e-1f2a3b4c5d6e"}}
+				],
+				"next": null
+			}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"list", "consumer-oauth2"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed listing consumer OAuth2 applications: success expected: result: %s", got.Error())
+		}
+
+		if listPath != "/oauth2" {
+			t.Errorf("failed listing consumer OAuth2 applications: global resource expected: result: %s", listPath)
+		}
+	})
+}
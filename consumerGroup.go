@@ -0,0 +1,444 @@
+////////////////////////////////////////////////////////////////////////////////
+//	consumerGroup.go  -  Oct-17-2026  -  aldebap
+//
+//	Kong consumer group configuration
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// kong consumer group attributes
+type KongConsumerGroup struct {
+	name string
+	tags []string
+}
+
+// create a new Kong consumer group
+func NewKongConsumerGroup(name string, tags []string) *KongConsumerGroup {
+
+	return &KongConsumerGroup{
+		name: name,
+		tags: tags,
+	}
+}
+
+// kong consumer group request payload
+type KongConsumerGroupRequest struct {
+	Name string   `json:"name,omitempty"`
+	Tags []string `json:"tags,omitempty"`
+}
+
+// kong consumer group response payload
+type KongConsumerGroupResponse struct {
+	Id        string   `json:"id"`
+	Name      string   `json:"name"`
+	Tags      []string `json:"tags"`
+	CreatedAt uint64   `json:"created_at"`
+}
+
+// kong consumer group query response payload: the group with its members and plugins
+type KongConsumerGroupQueryResponse struct {
+	ConsumerGroup KongConsumerGroupResponse `json:"consumer_group"`
+	Consumers     []KongConsumerResponse    `json:"consumers"`
+	Plugins       []KongPluginResponse      `json:"plugins"`
+}
+
+// kong consumer group list response payload
+type KongConsumerGroupListResponse struct {
+	Data []KongConsumerGroupResponse `json:"data"`
+	Next string                      `json:"next"`
+}
+
+// kong consumer group member request payload
+type KongConsumerGroupMemberRequest struct {
+	Consumer string `json:"consumer"`
+}
+
+const (
+	consumerGroupsResource string = "consumer_groups"
+)
+
+// add a new consumer group to Kong
+func (ks *KongServerDomain) AddConsumerGroup(newKongConsumerGroup *KongConsumerGroup, options Options) error {
+
+	var consumerGroupURL string = fmt.Sprintf("%s/%s", ks.ServerURL(), consumerGroupsResource)
+
+	payload, err := json.Marshal(KongConsumerGroupRequest{
+		Name: newKongConsumerGroup.name,
+		Tags: newKongConsumerGroup.tags,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := ks.sendRequest("POST", consumerGroupURL, payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return newKongAPIError("fail sending add consumer group command to Kong", resp)
+	}
+
+	//	parse response payload
+	var respPayload []byte
+
+	respPayload, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var consumerGroupResp KongConsumerGroupResponse
+
+	err = json.Unmarshal(respPayload, &consumerGroupResp)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+	} else {
+		if options.verbose {
+			fmt.Printf("http response status code: %s\nnew consumer group ID: %s\n", resp.Status, consumerGroupResp.Id)
+		} else {
+			fmt.Printf("%s\n", consumerGroupResp.Id)
+		}
+	}
+
+	return nil
+}
+
+// query a consumer group by Id or name
+func (ks *KongServerDomain) QueryConsumerGroup(id string, options Options) error {
+
+	var consumerGroupURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), consumerGroupsResource, id)
+
+	//	send a request to Kong to query the consumer group by id
+	resp, err := ks.sendRequest("GET", consumerGroupURL, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return newKongNotFoundError("consumer group", resp)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return newKongAPIError("fail sending query consumer group command to Kong", resp)
+	}
+
+	var respPayload []byte
+
+	respPayload, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+	} else {
+		var consumerGroupResp KongConsumerGroupQueryResponse
+
+		err = json.Unmarshal(respPayload, &consumerGroupResp)
+		if err != nil {
+			return err
+		}
+
+		//	some Kong versions return only the group
+		if len(consumerGroupResp.ConsumerGroup.Id) == 0 {
+			err = json.Unmarshal(respPayload, &consumerGroupResp.ConsumerGroup)
+			if err != nil {
+				return err
+			}
+		}
+
+		if options.verbose {
+			fmt.Printf("http response status code: %s\n", resp.Status)
+		}
+		fmt.Printf("%s: %s (%s)\n", consumerGroupResp.ConsumerGroup.Id, consumerGroupResp.ConsumerGroup.Name, consumerGroupResp.ConsumerGroup.Tags)
+
+		for _, consumer := range consumerGroupResp.Consumers {
+			fmt.Printf("    consumer: %s: %s\n", consumer.Id, consumer.UserName)
+		}
+		for _, plugin := range consumerGroupResp.Plugins {
+			fmt.Printf("    plugin: %s: %s\n", plugin.Id, plugin.Name)
+		}
+	}
+
+	return nil
+}
+
+// list all consumer groups
+func (ks *KongServerDomain) ListConsumerGroups(pagination *KongPagination, options Options) error {
+
+	//	send a request to Kong to get a list of all consumer groups
+	collection, status, err := ks.fetchCollection(consumerGroupsResource, pagination, "fail sending list consumer groups command to Kong")
+	if err != nil {
+		return err
+	}
+
+	respPayload, err := json.Marshal(collection)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", status, string(respPayload))
+	} else {
+		var consumerGroupListResp KongConsumerGroupListResponse
+
+		err = json.Unmarshal(respPayload, &consumerGroupListResp)
+		if err != nil {
+			return err
+		}
+
+		if len(consumerGroupListResp.Data) == 0 {
+			if options.verbose {
+				fmt.Printf("%s\nNo consumer groups\n", status)
+			} else {
+				fmt.Printf("No consumer groups\n")
+			}
+
+			return nil
+		}
+
+		if options.verbose {
+			fmt.Printf("http response status code: %s\nconsumer group list\n", status)
+		}
+
+		for _, consumerGroup := range consumerGroupListResp.Data {
+			fmt.Printf("%s: %s (%s)\n", consumerGroup.Id, consumerGroup.Name, consumerGroup.Tags)
+		}
+		printNextOffset(collection)
+	}
+
+	return nil
+}
+
+// update a consumer group in Kong
+func (ks *KongServerDomain) UpdateConsumerGroup(id string, updatedKongConsumerGroup *KongConsumerGroup, options Options) error {
+
+	var consumerGroupURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), consumerGroupsResource, id)
+
+	payload, err := json.Marshal(KongConsumerGroupRequest{
+		Name: updatedKongConsumerGroup.name,
+		Tags: updatedKongConsumerGroup.tags,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := ks.sendRequest("PATCH", consumerGroupURL, payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return newKongNotFoundError("consumer group", resp)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return newKongAPIError("fail sending patch consumer group command to Kong", resp)
+	}
+
+	//	parse response payload
+	var respPayload []byte
+
+	respPayload, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var consumerGroupResp KongConsumerGroupResponse
+
+	err = json.Unmarshal(respPayload, &consumerGroupResp)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+	} else {
+		if options.verbose {
+			fmt.Printf("http response status code: %s\n", resp.Status)
+		}
+		fmt.Printf("%s: %s (%s)\n", consumerGroupResp.Id, consumerGroupResp.Name, consumerGroupResp.Tags)
+	}
+
+	return nil
+}
+
+// delete a consumer group in Kong
+func (ks *KongServerDomain) DeleteConsumerGroup(id string, options Options) error {
+
+	var consumerGroupURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), consumerGroupsResource, id)
+
+	//	send a request to Kong to delete the consumer group by id
+	resp, err := ks.sendRequest("DELETE", consumerGroupURL, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newKongAPIError("fail sending delete consumer group command to Kong", resp)
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n{}\n", resp.Status)
+	} else {
+		if options.verbose {
+			fmt.Printf("http response status code: %s\n", resp.Status)
+		}
+	}
+
+	return nil
+}
+
+// add a consumer to a consumer group
+func (ks *KongServerDomain) AddConsumerGroupMember(id string, consumerId string, options Options) error {
+
+	var membersURL string = fmt.Sprintf("%s/%s/%s/%s", ks.ServerURL(), consumerGroupsResource, id, consumersResource)
+
+	payload, err := json.Marshal(KongConsumerGroupMemberRequest{
+		Consumer: consumerId,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := ks.sendRequest("POST", membersURL, payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return newKongNotFoundError("consumer group", resp)
+	}
+
+	if resp.StatusCode != http.StatusCreated && resp.StatusCode != http.StatusOK {
+		return newKongAPIError("fail sending add consumer group member command to Kong", resp)
+	}
+
+	respPayload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	//	Kong replies with the group and the consumers added, so the consumer id is resolved from the username
+	var memberResp KongConsumerGroupQueryResponse
+
+	err = json.Unmarshal(respPayload, &memberResp)
+	if err != nil {
+		return err
+	}
+
+	var memberId string = consumerId
+
+	for _, consumer := range memberResp.Consumers {
+		if consumer.Id == consumerId || consumer.UserName == consumerId {
+			memberId = consumer.Id
+		}
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+	} else {
+		if options.verbose {
+			fmt.Printf("http response status code: %s\nconsumer %s added to consumer group %s\n", resp.Status, memberId, id)
+		} else {
+			fmt.Printf("%s\n", memberId)
+		}
+	}
+
+	return nil
+}
+
+// remove a consumer from a consumer group
+func (ks *KongServerDomain) RemoveConsumerGroupMember(id string, consumerId string, options Options) error {
+
+	var memberURL string = fmt.Sprintf("%s/%s/%s/%s/%s", ks.ServerURL(), consumerGroupsResource, id, consumersResource, consumerId)
+
+	resp, err := ks.sendRequest("DELETE", memberURL, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return newKongNotFoundError("consumer group member", resp)
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newKongAPIError("fail sending remove consumer group member command to Kong", resp)
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n{}\n", resp.Status)
+	} else {
+		if options.verbose {
+			fmt.Printf("http response status code: %s\n", resp.Status)
+		}
+	}
+
+	return nil
+}
+
+// list the consumers of a consumer group
+func (ks *KongServerDomain) ListConsumerGroupMembers(id string, pagination *KongPagination, options Options) error {
+
+	var resource string = fmt.Sprintf("%s/%s/%s", consumerGroupsResource, id, consumersResource)
+
+	//	send a request to Kong to get a list of the group members
+	collection, status, err := ks.fetchCollection(resource, pagination, "fail sending list consumer group members command to Kong")
+	if err != nil {
+		return err
+	}
+
+	respPayload, err := json.Marshal(collection)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", status, string(respPayload))
+	} else {
+		var consumerListResp KongConsumerListResponse
+
+		err = json.Unmarshal(respPayload, &consumerListResp)
+		if err != nil {
+			return err
+		}
+
+		if len(consumerListResp.Data) == 0 {
+			if options.verbose {
+				fmt.Printf("%s\nNo consumer group members\n", status)
+			} else {
+				fmt.Printf("No consumer group members\n")
+			}
+
+			return nil
+		}
+
+		if options.verbose {
+			fmt.Printf("http response status code: %s\nconsumer group member list\n", status)
+		}
+
+		for _, consumer := range consumerListResp.Data {
+			fmt.Printf("%s: (%s) %s %s\n", consumer.Id, consumer.CustomId, consumer.UserName, consumer.Tags)
+		}
+		printNextOffset(collection)
+	}
+
+	return nil
+}
@@ -0,0 +1,228 @@
+////////////////////////////////////////////////////////////////////////////////
+//	consumerGroup_test.go  -  Oct-17-2026  -  aldebap
+//
+//	Test cases for Kong consumer group Configuration
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test_AddConsumerGroup unit tests for AddConsumerGroup() method
+func Test_AddConsumerGroup(t *testing.T) {
+
+	t.Run(">>> AddConsumerGroup: scenario 1 - missing name", func(t *testing.T) {
+
+		want := errors.New("missing consumer group name: option --name={name} required for this command")
+		got := kconf(NewKongServer("http://localhost:8001", 0), []string{"add", "consumer-group", "--tags=plans"}, Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed adding consumer group: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> AddConsumerGroup: scenario 2 - consumer group created successfuly", func(t *testing.T) {
+
+		var request KongConsumerGroupRequest
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&request)
+
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "4a5b6c7d-8e9f-4a0b-9c1d-2e3f4a5b6c7d", "name": "gold"}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"add", "consumer-group", "--name=gold", "--tags=plans"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed adding consumer group: success expected: result: %s", got.Error())
+		}
+
+		if request.Name != "gold" || len(request.Tags) != 1 {
+			t.Errorf("failed adding consumer group: unexpected request: %+v", request)
+		}
+	})
+}
+
+// Test_QueryConsumerGroup unit tests for QueryConsumerGroup() method
+func Test_QueryConsumerGroup(t *testing.T) {
+
+	t.Run(">>> QueryConsumerGroup: scenario 1 - consumer group not found", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("consumer group not found")
+		got := kongServer.QueryConsumerGroup("gold", Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed querying consumer group: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> QueryConsumerGroup: scenario 2 - consumer group with members and plugins", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"consumer_group": {"id": "4a5b6c7d-8e9f-4a0b-9c1d-2e3f4a5b6c7d", "name": "gold"},
+				"consumers": [{"id": "2b3c4d5e-6f7a-4b8c-9d0e-1f2a3b4c5d6e", "username": "partner"}],
+				"plugins": [{"id": "5b6c7d8e-9f0a-4b1c-8d2e-3f4a5b6c7d8e", "name": "rate-limiting"}]
+			}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kongServer.QueryConsumerGroup("gold", Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got != nil {
+			t.Errorf("failed querying consumer group: success expected: result: %s", got.Error())
+		}
+	})
+}
+
+// Test_ConsumerGroupMembers unit tests for AddConsumerGroupMember(), RemoveConsumerGroupMember() and ListConsumerGroupMembers() methods
+func Test_ConsumerGroupMembers(t *testing.T) {
+
+	t.Run(">>> ConsumerGroupMembers: scenario 1 - missing consumer id", func(t *testing.T) {
+
+		want := errors.New("missing consumer id: option --consumer-id={id} required for this command")
+		got := kconf(NewKongServer("http://localhost:8001", 0), []string{"consumer-group", "add-member", "--id=gold"}, Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed adding consumer group member: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> ConsumerGroupMembers: scenario 2 - member added", func(t *testing.T) {
+
+		var memberPath string
+		var request KongConsumerGroupMemberRequest
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			memberPath = r.URL.Path
+			json.NewDecoder(r.Body).Decode(&request)
+
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"consumer_group": {"id": "4a5b6c7d-8e9f-4a0b-9c1d-2e3f4a5b6c7d", "name": "gold"},
+				"consumers": [{"id": "8f2a6b1c-3d4e-4f5a-9b6c-7d8e9f0a1b2c", "username": "partner"}]}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"consumer-group", "add-member", "--id=gold", "--consumer-id=partner"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed adding consumer group member: success expected: result: %s", got.Error())
+		}
+
+		if memberPath != "/consumer_groups/gold/consumers" || request.Consumer != "partner" {
+			t.Errorf("failed adding consumer group member: unexpected request: %s %+v", memberPath, request)
+		}
+	})
+
+	t.Run(">>> ConsumerGroupMembers: scenario 3 - member removed", func(t *testing.T) {
+
+		var memberPath string
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			memberPath = r.URL.Path
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"consumer-group", "remove-member", "--id=gold", "--consumer-id=partner"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed removing consumer group member: success expected: result: %s", got.Error())
+		}
+
+		if memberPath != "/consumer_groups/gold/consumers/partner" {
+			t.Errorf("failed removing consumer group member: unexpected path: %s", memberPath)
+		}
+	})
+
+	t.Run(">>> ConsumerGroupMembers: scenario 4 - members listed", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"data": [{"id": "2b3c4d5e-6f7a-4b8c-9d0e-1f2a3b4c5d6e", "username": "partner"}],
+				"next": null
+			}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kongServer.ListConsumerGroupMembers("gold", nil, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got != nil {
+			t.Errorf("failed listing consumer group members: success expected: result: %s", got.Error())
+		}
+	})
+}
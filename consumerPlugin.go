@@ -7,9 +7,7 @@
 package main
 
 import (
-	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -43,9 +41,18 @@ type KongBasicAuthRequest struct {
 
 // kong response payload
 type KongBasicAuthResponse struct {
-	Id string `json:"id"`
+	Id       string         `json:"id"`
+	Consumer KongConsumerID `json:"consumer"`
+	UserName string         `json:"username"`
+	Tags     []string       `json:"tags"`
 }
 
+const (
+	basicAuthsResource string = "basic-auths"
+	keyAuthsResource   string = "key-auths"
+	jwtsResource       string = "jwts"
+)
+
 // add a new consumer to Kong
 func (ks *KongServerDomain) AddConsumerBasicAuth(id string, newKongBasicAuthConfig *KongBasicAuthConfig, options Options) error {
 
@@ -59,25 +66,18 @@ func (ks *KongServerDomain) AddConsumerBasicAuth(id string, newKongBasicAuthConf
 		return err
 	}
 
-	req, err := http.NewRequest("POST", consumerPluginURL, bytes.NewBuffer([]byte(payload)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ks.sendRequest("POST", consumerPluginURL, payload)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return errors.New("consumer not found")
+		return newKongNotFoundError("consumer", resp)
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return errors.New("fail sending add consumer basic auth command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending add consumer basic auth command to Kong", resp)
 	}
 
 	//	parse response payload
@@ -131,7 +131,11 @@ type KongKeyAuthRequest struct {
 
 // kong consumer KeyAuth response payload
 type KongKeyAuthResponse struct {
-	Id string `json:"id"`
+	Id       string         `json:"id"`
+	Consumer KongConsumerID `json:"consumer"`
+	Key      string         `json:"key"`
+	Ttl      int64          `json:"ttl"`
+	Tags     []string       `json:"tags"`
 }
 
 func (ks *KongServerDomain) AddConsumerKeyAuth(id string, newKongKeyAuthConfig *KongKeyAuthConfig, options Options) error {
@@ -146,25 +150,18 @@ func (ks *KongServerDomain) AddConsumerKeyAuth(id string, newKongKeyAuthConfig *
 		return err
 	}
 
-	req, err := http.NewRequest("POST", consumerPluginURL, bytes.NewBuffer([]byte(payload)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ks.sendRequest("POST", consumerPluginURL, payload)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return errors.New("consumer not found")
+		return newKongNotFoundError("consumer", resp)
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return errors.New("fail sending add consumer keyAuth command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending add consumer keyAuth command to Kong", resp)
 	}
 
 	//	parse response payload
@@ -222,7 +219,7 @@ type KongJWTRequest struct {
 // kong consumer JWT response payload
 type KongJWTResponse struct {
 	Id        string          `json:"id"`
-	Consumer  *KongConsumerID `json:"service,omitempty"`
+	Consumer  *KongConsumerID `json:"consumer,omitempty"`
 	Algorithm string          `json:"algorithm,omitempty"`
 	Key       string          `json:"key,omitempty"`
 	Secret    string          `json:"secret,omitempty"`
@@ -242,25 +239,18 @@ func (ks *KongServerDomain) AddConsumerJWT(id string, newKongJWTConfig *KongJWTC
 		return err
 	}
 
-	req, err := http.NewRequest("POST", consumerPluginURL, bytes.NewBuffer([]byte(payload)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ks.sendRequest("POST", consumerPluginURL, payload)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return errors.New("consumer not found")
+		return newKongNotFoundError("consumer", resp)
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return errors.New("fail sending add consumer JWT command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending add consumer JWT command to Kong", resp)
 	}
 
 	//	parse response payload
@@ -340,13 +330,21 @@ func (ks *KongServerDomain) AddConsumerIPRestriction(id string, newKongIPRestric
 
 	var consumerPluginURL string = fmt.Sprintf("%s/%s/%s/%s", ks.ServerURL(), consumersResource, id, pluginsResource)
 
+	pluginConfig := &KongIPRestrictionRequestConfig{
+		Allow: newKongIPRestrictionConfig.config.allow,
+		Deny:  newKongIPRestrictionConfig.config.deny,
+	}
+
+	//	validate the plugin config before sending it to Kong
+	_, err := ks.validatePluginConfig(IPRestrictionPlugins, pluginConfig, false, options)
+	if err != nil {
+		return err
+	}
+
 	payload, err := json.Marshal(KongIPRestrictionRequest{
 		Name:         IPRestrictionPlugins,
 		InstanceName: newKongIPRestrictionConfig.name,
-		Config: &KongIPRestrictionRequestConfig{
-			Allow: newKongIPRestrictionConfig.config.allow,
-			Deny:  newKongIPRestrictionConfig.config.deny,
-		},
+		Config:       pluginConfig,
 	})
 	if err != nil {
 		return err
@@ -355,25 +353,18 @@ func (ks *KongServerDomain) AddConsumerIPRestriction(id string, newKongIPRestric
 	//log.Printf("[debug] URL: %s", consumerPluginURL)
 	//log.Printf("[debug] post payload: %s", payload)
 
-	req, err := http.NewRequest("POST", consumerPluginURL, bytes.NewBuffer([]byte(payload)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ks.sendRequest("POST", consumerPluginURL, payload)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return errors.New("consumer not found")
+		return newKongNotFoundError("consumer", resp)
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return errors.New("fail sending add consumer IP Restriction command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending add consumer IP Restriction command to Kong", resp)
 	}
 
 	//	parse response payload
@@ -462,16 +453,24 @@ func (ks *KongServerDomain) AddConsumerRateLimiting(id string, newKongRateLimiti
 
 	var consumerPluginURL string = fmt.Sprintf("%s/%s/%s/%s", ks.ServerURL(), consumersResource, id, pluginsResource)
 
+	pluginConfig := &KongRateLimitingRequestConfig{
+		Second:       newKongRateLimitingPlugin.config.second,
+		Minute:       newKongRateLimitingPlugin.config.minute,
+		Hour:         newKongRateLimitingPlugin.config.hour,
+		ErrorCode:    newKongRateLimitingPlugin.config.errorCode,
+		ErrorMessage: newKongRateLimitingPlugin.config.errorMessage,
+	}
+
+	//	validate the plugin config before sending it to Kong
+	_, err := ks.validatePluginConfig(RateLimitingPlugins, pluginConfig, false, options)
+	if err != nil {
+		return err
+	}
+
 	payload, err := json.Marshal(KongRateLimitingRequest{
 		Name:         RateLimitingPlugins,
 		InstanceName: newKongRateLimitingPlugin.name,
-		Config: &KongRateLimitingRequestConfig{
-			Second:       newKongRateLimitingPlugin.config.second,
-			Minute:       newKongRateLimitingPlugin.config.minute,
-			Hour:         newKongRateLimitingPlugin.config.hour,
-			ErrorCode:    newKongRateLimitingPlugin.config.errorCode,
-			ErrorMessage: newKongRateLimitingPlugin.config.errorMessage,
-		},
+		Config:       pluginConfig,
 	})
 	if err != nil {
 		return err
@@ -480,25 +479,18 @@ func (ks *KongServerDomain) AddConsumerRateLimiting(id string, newKongRateLimiti
 	//log.Printf("[debug] URL: %s", consumerPluginURL)
 	//log.Printf("[debug] post payload: %s", payload)
 
-	req, err := http.NewRequest("POST", consumerPluginURL, bytes.NewBuffer([]byte(payload)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ks.sendRequest("POST", consumerPluginURL, payload)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return errors.New("consumer not found")
+		return newKongNotFoundError("consumer", resp)
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return errors.New("fail sending add consumer Rate Limiting command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending add consumer Rate Limiting command to Kong", resp)
 	}
 
 	//	parse response payload
@@ -581,14 +573,22 @@ func (ks *KongServerDomain) AddConsumerRequestSizeLimiting(id string, newKongReq
 
 	var consumerPluginURL string = fmt.Sprintf("%s/%s/%s/%s", ks.ServerURL(), consumersResource, id, pluginsResource)
 
+	pluginConfig := &KongRequestSizeLimitingRequestConfig{
+		AllowedPayloadSize:   newKongRequestSizeLimitingPlugin.config.allowedPayloadSize,
+		SizeUnit:             newKongRequestSizeLimitingPlugin.config.sizeUnit,
+		RequireContentLength: newKongRequestSizeLimitingPlugin.config.requireContentLength,
+	}
+
+	//	validate the plugin config before sending it to Kong
+	_, err := ks.validatePluginConfig(RequestSizeLimitingPlugins, pluginConfig, false, options)
+	if err != nil {
+		return err
+	}
+
 	payload, err := json.Marshal(KongRequestSizeLimitingRequest{
 		Name:         RequestSizeLimitingPlugins,
 		InstanceName: newKongRequestSizeLimitingPlugin.name,
-		Config: &KongRequestSizeLimitingRequestConfig{
-			AllowedPayloadSize:   newKongRequestSizeLimitingPlugin.config.allowedPayloadSize,
-			SizeUnit:             newKongRequestSizeLimitingPlugin.config.sizeUnit,
-			RequireContentLength: newKongRequestSizeLimitingPlugin.config.requireContentLength,
-		},
+		Config:       pluginConfig,
 	})
 	if err != nil {
 		return err
@@ -597,25 +597,18 @@ func (ks *KongServerDomain) AddConsumerRequestSizeLimiting(id string, newKongReq
 	//log.Printf("[debug] URL: %s", consumerPluginURL)
 	//log.Printf("[debug] post payload: %s", payload)
 
-	req, err := http.NewRequest("POST", consumerPluginURL, bytes.NewBuffer([]byte(payload)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ks.sendRequest("POST", consumerPluginURL, payload)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return errors.New("consumer not found")
+		return newKongNotFoundError("consumer", resp)
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return errors.New("fail sending add consumer Request Size Limiting command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending add consumer Request Size Limiting command to Kong", resp)
 	}
 
 	//	parse response payload
@@ -692,12 +685,20 @@ func (ks *KongServerDomain) AddConsumerSyslog(id string, newKongSyslogPlugin *Ko
 
 	var consumerPluginURL string = fmt.Sprintf("%s/%s/%s/%s", ks.ServerURL(), consumersResource, id, pluginsResource)
 
+	pluginConfig := &KongSyslogConfigRequest{
+		LogLevel: newKongSyslogPlugin.config.logLevel,
+	}
+
+	//	validate the plugin config before sending it to Kong
+	_, err := ks.validatePluginConfig(SyslogPlugins, pluginConfig, false, options)
+	if err != nil {
+		return err
+	}
+
 	payload, err := json.Marshal(KongSyslogRequest{
 		Name:         SyslogPlugins,
 		InstanceName: newKongSyslogPlugin.name,
-		Config: &KongSyslogConfigRequest{
-			LogLevel: newKongSyslogPlugin.config.logLevel,
-		},
+		Config:       pluginConfig,
 	})
 	if err != nil {
 		return err
@@ -706,25 +707,18 @@ func (ks *KongServerDomain) AddConsumerSyslog(id string, newKongSyslogPlugin *Ko
 	//log.Printf("[debug] URL: %s", consumerPluginURL)
 	//log.Printf("[debug] post payload: %s", payload)
 
-	req, err := http.NewRequest("POST", consumerPluginURL, bytes.NewBuffer([]byte(payload)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ks.sendRequest("POST", consumerPluginURL, payload)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return errors.New("consumer not found")
+		return newKongNotFoundError("consumer", resp)
 	}
 
 	if resp.StatusCode != http.StatusCreated {
-		return errors.New("fail sending add consumer Request Size Limiting command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending add consumer Request Size Limiting command to Kong", resp)
 	}
 
 	//	parse response payload
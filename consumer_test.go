@@ -181,7 +181,7 @@ func Test_ListConsumer(t *testing.T) {
 		}
 
 		want := errors.New("fail sending list consumers command to Kong: 500 Internal Server Error")
-		got := kongServer.ListConsumers(Options{
+		got := kongServer.ListConsumers(nil, Options{
 			verbose:    false,
 			jsonOutput: false,
 		})
@@ -218,7 +218,7 @@ func Test_ListConsumer(t *testing.T) {
 		}
 
 		var want error = nil
-		got := kongServer.ListConsumers(Options{
+		got := kongServer.ListConsumers(nil, Options{
 			verbose:    false,
 			jsonOutput: false,
 		})
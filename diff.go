@@ -0,0 +1,146 @@
+////////////////////////////////////////////////////////////////////////////////
+//	diff.go  -  Oct-17-2026  -  aldebap
+//
+//	Drift between a declarative state and Kong
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+)
+
+// error returned when Kong configuration differs from the state file
+var errStateDrift = errors.New("drift detected between state file and Kong")
+
+const (
+	driftExitStatus int = 2
+
+	colorRed    string = "\033[0;31m"
+	colorGreen  string = "\033[0;32m"
+	colorYellow string = "\033[0;33m"
+	colorNone   string = "\033[0m"
+)
+
+// kong diff result payload
+type KongDiffResponse struct {
+	Drift   bool              `json:"drift"`
+	Create  int               `json:"create"`
+	Update  int               `json:"update"`
+	Delete  int               `json:"delete"`
+	Changes []KongStateChange `json:"changes"`
+}
+
+// show the drift between a declarative state and Kong
+func (ks *KongServerDomain) DiffState(desiredState *KongState, options Options) error {
+
+	currentState, err := ks.fetchKongState()
+	if err != nil {
+		return err
+	}
+
+	changes, err := planKongState(desiredState, currentState)
+	if err != nil {
+		return err
+	}
+
+	diffResp := KongDiffResponse{
+		Drift:   len(changes) > 0,
+		Changes: changes,
+	}
+
+	for _, change := range changes {
+		switch change.Action {
+		case stateActionCreate:
+			diffResp.Create++
+
+		case stateActionUpdate:
+			diffResp.Update++
+
+		case stateActionDelete:
+			diffResp.Delete++
+		}
+	}
+
+	if options.jsonOutput {
+		payload, err := json.Marshal(diffResp)
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s\n", string(payload))
+	} else {
+		for _, change := range changes {
+			err = printStateChange(change, options)
+			if err != nil {
+				return err
+			}
+		}
+
+		if diffResp.Drift {
+			fmt.Printf("%d to create, %d to update, %d to delete\n", diffResp.Create, diffResp.Update, diffResp.Delete)
+		} else {
+			fmt.Printf("No changes\n")
+		}
+	}
+
+	if diffResp.Drift {
+		return errStateDrift
+	}
+
+	return nil
+}
+
+// print a state change as a unified diff of its attributes
+func printStateChange(change KongStateChange, options Options) error {
+
+	switch change.Action {
+	case stateActionCreate:
+		fields, err := stateFields(change.desired)
+		if err != nil {
+			return err
+		}
+
+		printDiffLine(colorGreen, fmt.Sprintf("+ %s %s", change.Entity, change.Key), options)
+		for _, field := range sortedFieldNames(fields) {
+			printDiffLine(colorGreen, fmt.Sprintf("+     %s: %s", field, formatStateValue(fields[field])), options)
+		}
+
+	case stateActionUpdate:
+		printDiffLine(colorYellow, fmt.Sprintf("~ %s %s", change.Entity, change.Key), options)
+		for _, field := range change.Fields {
+			if field.Current != nil {
+				printDiffLine(colorRed, fmt.Sprintf("-     %s: %s", field.Field, formatStateValue(field.Current)), options)
+			}
+			printDiffLine(colorGreen, fmt.Sprintf("+     %s: %s", field.Field, formatStateValue(field.Desired)), options)
+		}
+
+	case stateActionDelete:
+		printDiffLine(colorRed, fmt.Sprintf("- %s %s", change.Entity, change.Key), options)
+	}
+
+	return nil
+}
+
+// print a single diff line, colored when the output is a terminal
+func printDiffLine(color string, line string, options Options) {
+
+	if options.colorOutput {
+		fmt.Printf("%s%s%s\n", color, line, colorNone)
+	} else {
+		fmt.Printf("%s\n", line)
+	}
+}
+
+// format an attribute value for the diff output
+func formatStateValue(value interface{}) string {
+
+	payload, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Sprintf("%v", value)
+	}
+
+	return string(payload)
+}
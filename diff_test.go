@@ -0,0 +1,93 @@
+////////////////////////////////////////////////////////////////////////////////
+//	diff_test.go  -  Oct-17-2026  -  aldebap
+//
+//	Test cases for drift between a declarative state and Kong
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test_DiffState unit tests for DiffState() method
+func Test_DiffState(t *testing.T) {
+
+	//	mock for Kong Admin
+	var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != "GET" {
+			t.Errorf("diff must not change Kong: unexpected %s %s", r.Method, r.URL.Path)
+		}
+
+		w.WriteHeader(http.StatusOK)
+
+		switch r.URL.Path {
+		case "/routes":
+			w.Write([]byte(`{ "data": [
+				{ "id": "r1", "name": "Produtos", "protocols": [ "http" ], "paths": [ "/api/v1/produto" ], "service": null }
+			], "next": null }`))
+
+		case "/plugins":
+			w.Write([]byte(`{ "data": [
+				{ "id": "p1", "name": "rate-limiting", "route": { "id": "r1" }, "enabled": true, "config": { "minute": 10 } }
+			], "next": null }`))
+
+		default:
+			w.Write([]byte(`{ "data": [], "next": null }`))
+		}
+	}))
+	defer mockKongAdmin.Close()
+
+	t.Run(">>> DiffState: scenario 1 - no drift", func(t *testing.T) {
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		desiredState := &KongState{
+			Routes:  []KongStateRoute{{Name: "Produtos", Paths: []string{"/api/v1/produto"}}},
+			Plugins: []KongStatePlugin{{Name: "rate-limiting", Route: "Produtos", Config: map[string]interface{}{"minute": 10}}},
+		}
+
+		var want error = nil
+		got := kongServer.DiffState(desiredState, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if want != got {
+			t.Errorf("failed diffing state: success expected: result: %s", got.Error())
+		}
+	})
+
+	t.Run(">>> DiffState: scenario 2 - drift detected", func(t *testing.T) {
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		disabled := false
+		desiredState := &KongState{
+			Routes:  []KongStateRoute{{Name: "Produtos", Paths: []string{"/api/v2/produto"}}},
+			Plugins: []KongStatePlugin{{Name: "rate-limiting", Route: "Produtos", Enabled: &disabled}},
+		}
+
+		want := errStateDrift
+		got := kongServer.DiffState(desiredState, Options{
+			verbose:    false,
+			jsonOutput: true,
+		})
+
+		//	check the invocation result
+		if want != got {
+			t.Errorf("failed diffing state: error expected: %v result: %v", want, got)
+		}
+	})
+}
@@ -0,0 +1,61 @@
+////////////////////////////////////////////////////////////////////////////////
+//	dump.go  -  Oct-17-2026  -  aldebap
+//
+//	Dump Kong configuration to a declarative state
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// dump the current Kong configuration as a declarative state
+func (ks *KongServerDomain) DumpState(fileName string, options Options) error {
+
+	state, err := ks.fetchKongState()
+	if err != nil {
+		return err
+	}
+	state.sort()
+
+	for i := range state.Upstreams {
+		state.Upstreams[i].omitDefaults()
+	}
+
+	//	a dumped state is meant to be applied, so the attributes it can't reproduce are reported
+	for _, warning := range state.warnings {
+		fmt.Fprintf(os.Stderr, "[warning] %s\n", warning)
+	}
+
+	//	file extension decides the format, otherwise json output option is used
+	jsonFormat := options.jsonOutput
+	if len(fileName) > 0 {
+		jsonFormat = strings.HasSuffix(strings.ToLower(fileName), ".json")
+	}
+
+	payload, err := marshalKongState(state, jsonFormat)
+	if err != nil {
+		return err
+	}
+
+	if len(fileName) == 0 {
+		fmt.Printf("%s", string(payload))
+
+		return nil
+	}
+
+	err = os.WriteFile(fileName, payload, 0644)
+	if err != nil {
+		return err
+	}
+
+	if options.verbose {
+		fmt.Printf("state dumped to %s: %d services, %d routes, %d consumers, %d plugins, %d upstreams\n", fileName,
+			len(state.Services), len(state.Routes), len(state.Consumers), len(state.Plugins), len(state.Upstreams))
+	}
+
+	return nil
+}
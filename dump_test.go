@@ -0,0 +1,239 @@
+////////////////////////////////////////////////////////////////////////////////
+//	dump_test.go  -  Oct-17-2026  -  aldebap
+//
+//	Test cases for dumping Kong configuration to a declarative state
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// Test_DumpState unit tests for DumpState() method
+func Test_DumpState(t *testing.T) {
+
+	t.Run(">>> DumpState: scenario 1 - error fetching current state", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("fail fetching services from Kong: 500 Internal Server Error")
+		got := kongServer.DumpState("", Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed dumping state: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> DumpState: scenario 2 - state dumped with names resolved", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+
+			switch r.URL.Path {
+			case "/services":
+				w.Write([]byte(`{ "data": [
+					{ "id": "s2", "name": "Produtos", "protocol": "http", "host": "192.168.68.107", "port": 8080, "path": "/api/v1/produto", "enabled": true, "created_at": 1724293955,
+					  "retries": 3, "connect_timeout": 5000, "read_timeout": 60000, "write_timeout": 60000 },
+					{ "id": "s1", "name": "Clientes", "protocol": "http", "host": "192.168.68.107", "port": 8081, "path": null, "enabled": true, "created_at": 1724293955 }
+				], "next": null }`))
+
+			case "/routes":
+				w.Write([]byte(`{ "data": [
+					{ "id": "r1", "name": "Produtos", "protocols": [ "http" ], "paths": [ "/api/v1/produto" ], "service": { "id": "s2" },
+					  "hosts": [ "api.example.com" ], "headers": { "x-version": [ "v2" ] }, "snis": null, "sources": [ { "ip": "10.0.0.0/8" } ],
+					  "destinations": null, "strip_path": false, "preserve_host": true, "regex_priority": 0, "path_handling": "v0",
+					  "https_redirect_status_code": 426, "request_buffering": true, "response_buffering": true, "expression": null, "priority": 0 },
+					{ "id": "r2", "name": "Status", "protocols": [ "http" ], "service": { "id": "s1" },
+					  "expression": "http.path ^= \"/status\"", "priority": 100 }
+				], "next": null }`))
+
+			case "/consumers":
+				w.Write([]byte(`{ "data": [ { "id": "c1", "username": "guest", "tags": null } ], "next": null }`))
+
+			case "/basic-auths":
+				w.Write([]byte(`{ "data": [ { "id": "b1", "username": "guest", "password": "hash", "consumer": { "id": "c1" } } ], "next": null }`))
+
+			case "/acls":
+				w.Write([]byte(`{ "data": [ { "id": "a2", "group": "partners", "consumer": { "id": "c1" } }, { "id": "a1", "group": "admins", "consumer": { "id": "c1" } } ], "next": null }`))
+
+			case "/hmac-auths":
+				w.Write([]byte(`{ "data": [ { "id": "h1", "username": "guest-hmac", "secret": "s3cr3t", "consumer": { "id": "c1" } } ], "next": null }`))
+
+			case "/upstreams":
+				w.Write([]byte(`{ "data": [
+					{ "id": "u1", "name": "Pedidos", "algorithm": "consistent-hashing", "hash_on": "cookie", "hash_fallback": "none",
+					  "hash_on_cookie": "session", "hash_on_cookie_path": "/", "slots": 10000, "host_header": "pedidos.internal", "use_srv_name": false },
+					{ "id": "u2", "name": "Pagamentos", "algorithm": "consistent-hashing", "hash_on": "cookie", "hash_fallback": "none",
+					  "hash_on_cookie": "session", "hash_on_cookie_path": "/pagamentos", "slots": 20000, "host_header": null, "use_srv_name": true }
+				], "next": null }`))
+
+			case "/consumer_groups":
+				w.Write([]byte(`{ "data": [ { "id": "g1", "name": "gold" } ], "next": null }`))
+
+			case "/plugins":
+				w.Write([]byte(`{ "data": [
+					{ "id": "p1", "name": "rate-limiting", "route": { "id": "r1" }, "service": null, "consumer": null, "enabled": true,
+					  "config": { "minute": 10, "hour": null }, "created_at": 1724293955, "updated_at": 1724293955 },
+					{ "id": "p2", "name": "cors", "consumer_group": null, "enabled": true, "config": {} },
+					{ "id": "p3", "name": "cors", "consumer_group": { "id": "g1" }, "enabled": true, "config": {} }
+				], "next": null }`))
+
+			default:
+				w.Write([]byte(`{ "data": [], "next": null }`))
+			}
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		stateFile := filepath.Join(t.TempDir(), "state.yaml")
+
+		var want error = nil
+		got := kongServer.DumpState(stateFile, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if want != got {
+			t.Fatalf("failed dumping state: success expected: result: %s", got.Error())
+		}
+
+		//	the dumped state must be a valid state file
+		state, err := LoadKongState(stateFile)
+		if err != nil {
+			t.Fatalf("failed loading dumped state: success expected: result: %s", err.Error())
+		}
+
+		if len(state.Services) != 2 || state.Services[0].Name != "Clientes" || state.Services[1].Name != "Produtos" {
+			t.Errorf("failed dumping state: sorted services expected: result: %+v", state.Services)
+		}
+
+		if produtos := state.Services[1]; produtos.Retries == nil || *produtos.Retries != 3 || produtos.ConnectTimeout == nil || *produtos.ConnectTimeout != 5000 ||
+			produtos.ReadTimeout == nil || *produtos.ReadTimeout != 60000 || produtos.WriteTimeout == nil || *produtos.WriteTimeout != 60000 {
+			t.Errorf("failed dumping state: service retries and timeouts expected: result: %+v", produtos)
+		}
+
+		if state.Routes[0].Service != "Produtos" {
+			t.Errorf("failed dumping state: route service expected: Produtos result: %s", state.Routes[0].Service)
+		}
+
+		if route := state.Routes[0]; len(route.Hosts) != 1 || route.Headers["x-version"][0] != "v2" || len(route.Sources) != 1 || route.Sources[0].IP != "10.0.0.0/8" ||
+			route.StripPath == nil || *route.StripPath || route.PreserveHost == nil || !*route.PreserveHost || route.PathHandling != "v0" ||
+			route.HttpsRedirectStatusCode != 426 {
+			t.Errorf("failed dumping state: route matching attributes expected: result: %+v", route)
+		}
+
+		if state.Routes[0].Priority != nil {
+			t.Errorf("failed dumping state: no priority expected for a route without expression: result: %d", *state.Routes[0].Priority)
+		}
+
+		if route := state.Routes[1]; route.Expression != `http.path ^= "/status"` || route.Priority == nil || *route.Priority != 100 {
+			t.Errorf("failed dumping state: route expression and priority expected: result: %+v", route)
+		}
+
+		if len(state.Plugins) != 3 || state.Plugins[2].key() != "rate-limiting[route=Produtos]" {
+			t.Fatalf("failed dumping state: plugin key expected: rate-limiting[route=Produtos] result: %+v", state.Plugins)
+		}
+
+		if state.Plugins[0].key() != "cors" || state.Plugins[1].key() != "cors[consumer_group=gold]" {
+			t.Errorf("failed dumping state: global and consumer group plugins expected: result: %s %s", state.Plugins[0].key(), state.Plugins[1].key())
+		}
+
+		if _, ok := state.Plugins[2].Config["hour"]; ok {
+			t.Errorf("failed dumping state: null config attribute should be stripped")
+		}
+
+		if len(state.Consumers[0].BasicAuth) != 1 || state.Consumers[0].BasicAuth[0].UserName != "guest" || len(state.Consumers[0].BasicAuth[0].Password) != 0 {
+			t.Errorf("failed dumping state: consumer basic auth expected: result: %+v", state.Consumers[0].BasicAuth)
+		}
+
+		if len(state.Upstreams) != 2 {
+			t.Fatalf("failed dumping state: two upstreams expected: result: %+v", state.Upstreams)
+		}
+
+		//	attributes at the Kong defaults are left out
+		if upstream := state.Upstreams[1]; upstream.HashOn != "cookie" || upstream.HashOnCookie != "session" || len(upstream.HashFallback) != 0 ||
+			len(upstream.HashOnCookiePath) != 0 || upstream.Slots != nil || upstream.HostHeader != "pedidos.internal" || upstream.UseSrvName != nil {
+			t.Errorf("failed dumping state: upstream hashing attributes expected: result: %+v", upstream)
+		}
+
+		if upstream := state.Upstreams[0]; upstream.HashOnCookiePath != "/pagamentos" || upstream.Slots == nil || *upstream.Slots != 20000 ||
+			upstream.UseSrvName == nil || !*upstream.UseSrvName {
+			t.Errorf("failed dumping state: upstream attributes expected: result: %+v", upstream)
+		}
+
+		if acls := state.Consumers[0].ACL; len(acls) != 2 || acls[0].Group != "admins" || acls[1].Group != "partners" {
+			t.Errorf("failed dumping state: consumer ACL groups expected: result: %+v", acls)
+		}
+
+		if hmacAuths := state.Consumers[0].HMACAuth; len(hmacAuths) != 1 || hmacAuths[0].UserName != "guest-hmac" || hmacAuths[0].Secret != "s3cr3t" {
+			t.Errorf("failed dumping state: consumer HMAC auth expected: result: %+v", hmacAuths)
+		}
+	})
+
+	t.Run(">>> DumpState: scenario 3 - attributes a dumped state can't reproduce", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+
+			switch r.URL.Path {
+			case "/services":
+				w.Write([]byte(`{ "data": [ { "id": "s1", "name": null, "protocol": "http", "host": "192.168.68.107", "port": 8081 } ], "next": null }`))
+
+			case "/consumers":
+				w.Write([]byte(`{ "data": [ { "id": "c1", "username": "guest" } ], "next": null }`))
+
+			case "/basic-auths":
+				w.Write([]byte(`{ "data": [ { "id": "b1", "username": "guest", "password": "hash", "consumer": { "id": "c1" } } ], "next": null }`))
+
+			default:
+				w.Write([]byte(`{ "data": [], "next": null }`))
+			}
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0).(*KongServerDomain)
+
+		state, err := kongServer.fetchKongState()
+		if err != nil {
+			t.Fatalf("failed dumping state: success expected: result: %s", err.Error())
+		}
+
+		want := []string{
+			"service s1: it has no name, so its id is used as the name",
+			"consumer-basic-auth guest/guest: the password is not dumped, since Kong only stores its hash: set it in the state file before applying it",
+		}
+
+		if !reflect.DeepEqual(want, state.warnings) {
+			t.Errorf("failed dumping state: warnings expected: %v result: %v", want, state.warnings)
+		}
+	})
+}
@@ -0,0 +1,152 @@
+////////////////////////////////////////////////////////////////////////////////
+//	errors.go  -  Oct-17-2026  -  aldebap
+//
+//	Kong Admin API errors
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// kong error code for an entity not found
+const (
+	kongErrorNotFound int = 6
+)
+
+// kong Admin API error: the HTTP status together with Kong's error response payload
+type KongAPIError struct {
+	Operation  string                 `json:"operation,omitempty"`
+	Status     string                 `json:"status,omitempty"`
+	StatusCode int                    `json:"status_code,omitempty"`
+	Code       int                    `json:"code,omitempty"`
+	Name       string                 `json:"name,omitempty"`
+	Message    string                 `json:"message,omitempty"`
+	Fields     map[string]interface{} `json:"fields,omitempty"`
+	entity     string
+}
+
+// create a Kong API error from an unexpected response
+func newKongAPIError(operation string, resp *http.Response) *KongAPIError {
+
+	apiError := &KongAPIError{
+		Operation:  operation,
+		Status:     resp.Status,
+		StatusCode: resp.StatusCode,
+	}
+
+	//	the error payload is optional: when it's missing or isn't JSON only the status is reported
+	respPayload, err := io.ReadAll(resp.Body)
+	if err == nil && len(respPayload) > 0 {
+		json.Unmarshal(respPayload, apiError)
+	}
+
+	return apiError
+}
+
+// create a Kong API error for an entity not found
+func newKongNotFoundError(entity string, resp *http.Response) *KongAPIError {
+
+	apiError := newKongAPIError("", resp)
+	apiError.entity = entity
+
+	if apiError.Code == 0 {
+		apiError.Code = kongErrorNotFound
+	}
+
+	return apiError
+}
+
+// report a Kong API error for a missing resource as an entity not found
+func kongNotFoundAsEntity(entity string, err error) error {
+
+	var apiError *KongAPIError
+
+	if errors.As(err, &apiError) && apiError.StatusCode == http.StatusNotFound {
+		apiError.entity = entity
+
+		if apiError.Code == 0 {
+			apiError.Code = kongErrorNotFound
+		}
+	}
+
+	return err
+}
+
+// error message: the operation and status, followed by Kong's message and one line per field error
+func (e *KongAPIError) Error() string {
+
+	var message string
+
+	if len(e.entity) > 0 {
+		message = e.entity + " not found"
+	} else {
+		message = e.Operation + ": " + e.Status
+		if len(e.Message) > 0 {
+			message += ": " + e.Message
+		}
+	}
+
+	var fieldErrors []string
+
+	flattenFieldErrors("", e.Fields, &fieldErrors)
+	sort.Strings(fieldErrors)
+
+	for _, fieldError := range fieldErrors {
+		message += "\n  " + fieldError
+	}
+
+	return message
+}
+
+// flatten Kong's nested field errors into "field.path: message" lines
+func flattenFieldErrors(path string, fields interface{}, fieldErrors *[]string) {
+
+	switch value := fields.(type) {
+	case map[string]interface{}:
+		for field, fieldValue := range value {
+			fieldPath := field
+			if len(path) > 0 {
+				fieldPath = path + "." + field
+			}
+			flattenFieldErrors(fieldPath, fieldValue, fieldErrors)
+		}
+
+	case []interface{}:
+		for i, item := range value {
+			if item != nil {
+				flattenFieldErrors(fmt.Sprintf("%s[%d]", path, i), item, fieldErrors)
+			}
+		}
+
+	case nil:
+
+	default:
+		//	entity level checks are reported by Kong under "@entity"
+		*fieldErrors = append(*fieldErrors, fmt.Sprintf("%s: %v", strings.Replace(path, "@entity", "entity", 1), value))
+	}
+}
+
+// print an error as a JSON document
+func printJSONError(err error) {
+
+	var apiError *KongAPIError
+
+	if !errors.As(err, &apiError) {
+		apiError = &KongAPIError{Message: err.Error()}
+	}
+
+	payload, marshalErr := json.Marshal(apiError)
+	if marshalErr != nil {
+		payload = []byte("{}")
+	}
+
+	fmt.Printf("%s\n", string(payload))
+}
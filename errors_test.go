@@ -0,0 +1,148 @@
+////////////////////////////////////////////////////////////////////////////////
+//	errors_test.go  -  Oct-17-2026  -  aldebap
+//
+//	Test cases for Kong Admin API errors
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test_KongAPIError unit tests for KongAPIError returned by Kong server methods
+func Test_KongAPIError(t *testing.T) {
+
+	t.Run(">>> KongAPIError: scenario 1 - unique violation", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusConflict)
+			w.Write([]byte(`{ "code": 5, "name": "unique constraint violation",
+				"message": "UNIQUE violation detected on '{name=\"Produtos\"}'", "fields": { "name": "Produtos" } }`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kongServer.AddService(&KongService{name: "Produtos"}, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		var apiError *KongAPIError
+
+		if !errors.As(got, &apiError) {
+			t.Fatalf("failed adding service: KongAPIError expected: result: %v", got)
+		}
+
+		if apiError.StatusCode != http.StatusConflict || apiError.Code != 5 || apiError.Name != "unique constraint violation" {
+			t.Errorf("failed adding service: unique violation expected: result: %+v", apiError)
+		}
+
+		want := "fail sending add service command to Kong: 409 Conflict: UNIQUE violation detected on '{name=\"Produtos\"}'\n  name: Produtos"
+		if want != got.Error() {
+			t.Errorf("failed adding service: error expected: %s result: %s", want, got.Error())
+		}
+	})
+
+	t.Run(">>> KongAPIError: scenario 2 - schema violation with nested fields", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{ "code": 2, "name": "schema violation", "message": "2 schema violations",
+				"fields": { "config": { "minute": "expected a number" }, "@entity": [ "at least one of these fields must be non-empty" ] } }`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := "fail sending add plugin command to Kong: 400 Bad Request: 2 schema violations\n" +
+			"  config.minute: expected a number\n" +
+			"  entity[0]: at least one of these fields must be non-empty"
+		got := kongServer.AddPlugin(&KongPlugin{name: "rate-limiting"}, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got == nil || want != got.Error() {
+			t.Errorf("failed adding plugin: error expected: %s result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> KongAPIError: scenario 3 - entity not found", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{ "message": "Not found" }`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kongServer.QueryRoute("1234", Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		var apiError *KongAPIError
+
+		if !errors.As(got, &apiError) || apiError.Code != kongErrorNotFound || got.Error() != "route not found" {
+			t.Errorf("failed querying route: route not found expected: result: %v", got)
+		}
+	})
+
+	t.Run(">>> KongAPIError: scenario 4 - invalid list request", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{ "code": 7, "name": "invalid offset", "message": "'abc' is not a valid offset: bad base64 encoding" }`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kongServer.ListServices(&KongPagination{offset: "abc"}, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		var apiError *KongAPIError
+
+		if !errors.As(got, &apiError) {
+			t.Fatalf("failed listing services: KongAPIError expected: result: %v", got)
+		}
+
+		if apiError.StatusCode != http.StatusBadRequest || apiError.Code != 7 || apiError.Name != "invalid offset" {
+			t.Errorf("failed listing services: invalid offset expected: result: %+v", apiError)
+		}
+
+		want := "fail sending list service command to Kong: 400 Bad Request: 'abc' is not a valid offset: bad base64 encoding"
+		if want != got.Error() {
+			t.Errorf("failed listing services: error expected: %s result: %s", want, got.Error())
+		}
+	})
+}
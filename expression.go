@@ -0,0 +1,596 @@
+////////////////////////////////////////////////////////////////////////////////
+//	expression.go  -  Oct-17-2026  -  aldebap
+//
+//	Kong expressions router: local syntax checking and route conversion
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"errors"
+	"net"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// kinds of tokens in an ATC expression
+type atcTokenKind int
+
+const (
+	atcTokenEOF atcTokenKind = iota
+	atcTokenField
+	atcTokenString
+	atcTokenInt
+	atcTokenIP
+	atcTokenOperator
+	atcTokenAnd
+	atcTokenOr
+	atcTokenNot
+	atcTokenLeftParen
+	atcTokenRightParen
+)
+
+// ATC expression token
+type atcToken struct {
+	kind atcTokenKind
+	text string
+	pos  int
+}
+
+// types of the fields in an ATC expression
+type atcFieldType int
+
+const (
+	atcString atcFieldType = iota
+	atcInt
+	atcIP
+)
+
+// fields known by the expressions router
+var atcFields = map[string]atcFieldType{
+	"net.protocol": atcString,
+	"net.src.ip":   atcIP,
+	"net.src.port": atcInt,
+	"net.dst.ip":   atcIP,
+	"net.dst.port": atcInt,
+	"tls.sni":      atcString,
+	"http.method":  atcString,
+	"http.host":    atcString,
+	"http.path":    atcString,
+}
+
+// fields with a name suffix: http.headers.x_version, http.queries.page
+var atcFieldPrefixes = []string{"http.headers.", "http.queries."}
+
+// operators allowed for each field type
+var atcOperators = map[atcFieldType][]string{
+	atcString: {"==", "!=", "~", "^=", "=^", "contains"},
+	atcInt:    {"==", "!=", ">", ">=", "<", "<="},
+	atcIP:     {"==", "!=", "in", "not in"},
+}
+
+var atcFieldNameRegEx = regexp.MustCompile(`^[a-z_][a-z0-9_]*$`)
+
+// ATC expression lexer
+type atcLexer struct {
+	expression string
+	pos        int
+}
+
+// error at a position of the expression
+func atcError(message string, pos int) error {
+
+	return errors.New("invalid expression: " + message + " at position " + strconv.Itoa(pos+1))
+}
+
+// get the next token of the expression
+func (lexer *atcLexer) next() (atcToken, error) {
+
+	for lexer.pos < len(lexer.expression) && strings.ContainsRune(" \t\r\n", rune(lexer.expression[lexer.pos])) {
+		lexer.pos++
+	}
+
+	start := lexer.pos
+	rest := lexer.expression[lexer.pos:]
+
+	if len(rest) == 0 {
+		return atcToken{kind: atcTokenEOF, pos: start}, nil
+	}
+
+	//	punctuation and operators, longest first
+	for _, symbol := range []struct {
+		text string
+		kind atcTokenKind
+	}{
+		{"&&", atcTokenAnd}, {"||", atcTokenOr},
+		{"==", atcTokenOperator}, {"!=", atcTokenOperator}, {"^=", atcTokenOperator}, {"=^", atcTokenOperator},
+		{">=", atcTokenOperator}, {"<=", atcTokenOperator}, {">", atcTokenOperator}, {"<", atcTokenOperator},
+		{"~", atcTokenOperator}, {"!", atcTokenNot}, {"(", atcTokenLeftParen}, {")", atcTokenRightParen},
+	} {
+		if strings.HasPrefix(rest, symbol.text) {
+			lexer.pos += len(symbol.text)
+			return atcToken{kind: symbol.kind, text: symbol.text, pos: start}, nil
+		}
+	}
+
+	//	raw string: r#"..."#
+	if strings.HasPrefix(rest, `r#"`) {
+		end := strings.Index(rest[3:], `"#`)
+		if end < 0 {
+			return atcToken{}, atcError("unterminated raw string", start)
+		}
+		lexer.pos += 3 + end + 2
+
+		return atcToken{kind: atcTokenString, text: rest[3 : 3+end], pos: start}, nil
+	}
+
+	//	string with escapes
+	if rest[0] == '"' {
+		var value strings.Builder
+
+		for i := 1; i < len(rest); i++ {
+			switch rest[i] {
+			case '"':
+				lexer.pos += i + 1
+				return atcToken{kind: atcTokenString, text: value.String(), pos: start}, nil
+
+			case '\\':
+				i++
+				if i == len(rest) {
+					return atcToken{}, atcError("unterminated string", start)
+				}
+				switch rest[i] {
+				case 'n':
+					value.WriteByte('\n')
+				case 't':
+					value.WriteByte('\t')
+				case 'r':
+					value.WriteByte('\r')
+				default:
+					value.WriteByte(rest[i])
+				}
+
+			default:
+				value.WriteByte(rest[i])
+			}
+		}
+
+		return atcToken{}, atcError("unterminated string", start)
+	}
+
+	//	words: fields, keywords, integers and IP addresses
+	end := 0
+	for end < len(rest) && (isAlphanumeric(rest[end]) || strings.ContainsRune("_.:/", rune(rest[end]))) {
+		end++
+	}
+	if end == 0 {
+		return atcToken{}, atcError("unexpected character '"+rest[:1]+"'", start)
+	}
+	lexer.pos += end
+	word := rest[:end]
+
+	switch {
+	case word == "in" || word == "contains":
+		return atcToken{kind: atcTokenOperator, text: word, pos: start}, nil
+
+	case word == "not":
+		//	the only operator with two words: not in
+		following, err := lexer.next()
+		if err != nil {
+			return atcToken{}, err
+		}
+		if following.text != "in" {
+			return atcToken{}, atcError("expected 'in' after 'not'", following.pos)
+		}
+		return atcToken{kind: atcTokenOperator, text: "not in", pos: start}, nil
+	}
+
+	if _, err := strconv.ParseInt(word, 10, 64); err == nil {
+		return atcToken{kind: atcTokenInt, text: word, pos: start}, nil
+	}
+
+	if ip := net.ParseIP(word); ip != nil {
+		return atcToken{kind: atcTokenIP, text: word, pos: start}, nil
+	}
+	if _, _, err := net.ParseCIDR(word); err == nil {
+		return atcToken{kind: atcTokenIP, text: word, pos: start}, nil
+	}
+
+	return atcToken{kind: atcTokenField, text: word, pos: start}, nil
+}
+
+// check if a character is a letter or a digit
+func isAlphanumeric(c byte) bool {
+
+	return (c >= 'a' && c <= 'z') || (c >= 'A' && c <= 'Z') || (c >= '0' && c <= '9')
+}
+
+// ATC expression parser: || has lower precedence than &&
+type atcParser struct {
+	lexer *atcLexer
+	token atcToken
+}
+
+// move to the next token
+func (parser *atcParser) advance() error {
+
+	token, err := parser.lexer.next()
+	if err != nil {
+		return err
+	}
+	parser.token = token
+
+	return nil
+}
+
+// expression := and ( "||" and )*
+func (parser *atcParser) parseOr() error {
+
+	err := parser.parseAnd()
+	if err != nil {
+		return err
+	}
+
+	for parser.token.kind == atcTokenOr {
+		err = parser.advance()
+		if err != nil {
+			return err
+		}
+
+		err = parser.parseAnd()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// and := term ( "&&" term )*
+func (parser *atcParser) parseAnd() error {
+
+	err := parser.parseTerm()
+	if err != nil {
+		return err
+	}
+
+	for parser.token.kind == atcTokenAnd {
+		err = parser.advance()
+		if err != nil {
+			return err
+		}
+
+		err = parser.parseTerm()
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// term := "!"? "(" expression ")" | predicate
+func (parser *atcParser) parseTerm() error {
+
+	if parser.token.kind == atcTokenNot {
+		err := parser.advance()
+		if err != nil {
+			return err
+		}
+
+		if parser.token.kind != atcTokenLeftParen {
+			return atcError("expected '(' after '!'", parser.token.pos)
+		}
+	}
+
+	if parser.token.kind == atcTokenLeftParen {
+		err := parser.advance()
+		if err != nil {
+			return err
+		}
+
+		err = parser.parseOr()
+		if err != nil {
+			return err
+		}
+
+		if parser.token.kind != atcTokenRightParen {
+			return atcError("expected ')'", parser.token.pos)
+		}
+
+		return parser.advance()
+	}
+
+	return parser.parsePredicate()
+}
+
+// predicate := ( field | "lower(" field ")" ) operator value
+func (parser *atcParser) parsePredicate() error {
+
+	if parser.token.kind != atcTokenField {
+		return atcError("expected a field", parser.token.pos)
+	}
+
+	field := parser.token
+	lower := false
+
+	err := parser.advance()
+	if err != nil {
+		return err
+	}
+
+	//	transformation function
+	if field.text == "lower" && parser.token.kind == atcTokenLeftParen {
+		lower = true
+
+		err = parser.advance()
+		if err != nil {
+			return err
+		}
+
+		if parser.token.kind != atcTokenField {
+			return atcError("expected a field", parser.token.pos)
+		}
+		field = parser.token
+
+		err = parser.advance()
+		if err != nil {
+			return err
+		}
+
+		if parser.token.kind != atcTokenRightParen {
+			return atcError("expected ')'", parser.token.pos)
+		}
+
+		err = parser.advance()
+		if err != nil {
+			return err
+		}
+	}
+
+	fieldType, err := atcFieldTypeOf(field)
+	if err != nil {
+		return err
+	}
+
+	if lower && fieldType != atcString {
+		return atcError("lower() requires a string field: "+field.text, field.pos)
+	}
+
+	//	operator allowed for the field type
+	operator := parser.token
+	if operator.kind != atcTokenOperator {
+		return atcError("expected an operator after "+field.text, operator.pos)
+	}
+
+	allowed := false
+	for _, op := range atcOperators[fieldType] {
+		if op == operator.text {
+			allowed = true
+		}
+	}
+	if !allowed {
+		return atcError("operator "+operator.text+" not allowed for field "+field.text, operator.pos)
+	}
+
+	err = parser.advance()
+	if err != nil {
+		return err
+	}
+
+	//	value of the field type
+	value := parser.token
+
+	switch fieldType {
+	case atcString:
+		if value.kind != atcTokenString {
+			return atcError("expected a string value for field "+field.text, value.pos)
+		}
+		if operator.text == "~" {
+			if _, err := regexp.Compile(value.text); err != nil {
+				return atcError("invalid regex: "+value.text, value.pos)
+			}
+		}
+
+	case atcInt:
+		if value.kind != atcTokenInt {
+			return atcError("expected an integer value for field "+field.text, value.pos)
+		}
+
+	case atcIP:
+		if value.kind != atcTokenIP {
+			return atcError("expected an IP address or CIDR value for field "+field.text, value.pos)
+		}
+		if (operator.text == "in" || operator.text == "not in") != strings.Contains(value.text, "/") {
+			return atcError("operators in and not in require a CIDR, == and != an IP address", value.pos)
+		}
+	}
+
+	return parser.advance()
+}
+
+// the type of a known field
+func atcFieldTypeOf(field atcToken) (atcFieldType, error) {
+
+	if fieldType, ok := atcFields[field.text]; ok {
+		return fieldType, nil
+	}
+
+	for _, prefix := range atcFieldPrefixes {
+		if name, found := strings.CutPrefix(field.text, prefix); found && atcFieldNameRegEx.MatchString(name) {
+			return atcString, nil
+		}
+	}
+
+	return atcString, atcError("unknown field: "+field.text, field.pos)
+}
+
+// check the syntax of an ATC expression
+func validateExpression(expression string) error {
+
+	parser := &atcParser{lexer: &atcLexer{expression: expression}}
+
+	err := parser.advance()
+	if err != nil {
+		return err
+	}
+
+	if parser.token.kind == atcTokenEOF {
+		return errors.New("invalid expression: empty expression")
+	}
+
+	err = parser.parseOr()
+	if err != nil {
+		return err
+	}
+
+	if parser.token.kind != atcTokenEOF {
+		return atcError("unexpected '"+parser.token.text+"'", parser.token.pos)
+	}
+
+	return nil
+}
+
+// quote a string value for an ATC expression
+func atcQuote(value string) string {
+
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+// join the alternatives of a route attribute
+func atcAny(predicates []string) string {
+
+	if len(predicates) == 1 {
+		return predicates[0]
+	}
+
+	return "(" + strings.Join(predicates, " || ") + ")"
+}
+
+// translate the endpoints of a route in predicates of the given direction (src or dst)
+func atcEndpoints(direction string, endpoints []KongRouteEndpoint) string {
+
+	var predicates []string
+
+	for _, endpoint := range endpoints {
+		var conditions []string
+
+		if len(endpoint.IP) > 0 {
+			if strings.Contains(endpoint.IP, "/") {
+				conditions = append(conditions, "net."+direction+".ip in "+endpoint.IP)
+			} else {
+				conditions = append(conditions, "net."+direction+".ip == "+endpoint.IP)
+			}
+		}
+		if endpoint.Port > 0 {
+			conditions = append(conditions, "net."+direction+".port == "+strconv.Itoa(endpoint.Port))
+		}
+
+		predicate := strings.Join(conditions, " && ")
+		if len(conditions) > 1 && len(endpoints) > 1 {
+			predicate = "(" + predicate + ")"
+		}
+		predicates = append(predicates, predicate)
+	}
+
+	return atcAny(predicates)
+}
+
+// translate the matching attributes of a traditional route into an equivalent expression
+func routeExpression(route *KongRouteResponse) string {
+
+	var groups []string
+
+	//	the default protocols don't need to be matched
+	if len(route.Protocols) > 0 && strings.Join(route.Protocols, ",") != "http,https" {
+		var predicates []string
+
+		for _, protocol := range route.Protocols {
+			predicates = append(predicates, "net.protocol == "+atcQuote(protocol))
+		}
+		groups = append(groups, atcAny(predicates))
+	}
+
+	if len(route.Methods) > 0 {
+		var predicates []string
+
+		for _, method := range route.Methods {
+			predicates = append(predicates, "http.method == "+atcQuote(strings.ToUpper(method)))
+		}
+		groups = append(groups, atcAny(predicates))
+	}
+
+	if len(route.Hosts) > 0 {
+		var predicates []string
+
+		for _, host := range route.Hosts {
+			switch {
+			case strings.HasPrefix(host, "*"):
+				predicates = append(predicates, "http.host =^ "+atcQuote(host[1:]))
+
+			case strings.HasSuffix(host, "*"):
+				predicates = append(predicates, "http.host ^= "+atcQuote(host[:len(host)-1]))
+
+			default:
+				predicates = append(predicates, "http.host == "+atcQuote(host))
+			}
+		}
+		groups = append(groups, atcAny(predicates))
+	}
+
+	if len(route.Paths) > 0 {
+		var predicates []string
+
+		for _, path := range route.Paths {
+			if regex, found := strings.CutPrefix(path, "~"); found {
+				predicates = append(predicates, "http.path ~ "+atcQuote("^"+strings.TrimPrefix(regex, "^")))
+			} else {
+				predicates = append(predicates, "http.path ^= "+atcQuote(path))
+			}
+		}
+		groups = append(groups, atcAny(predicates))
+	}
+
+	//	headers in a stable order
+	var headers []string
+	for name := range route.Headers {
+		headers = append(headers, name)
+	}
+	sort.Strings(headers)
+
+	//	the traditional router matches header values ignoring case
+	for _, name := range headers {
+		var predicates []string
+		field := "http.headers." + strings.ReplaceAll(strings.ToLower(name), "-", "_")
+
+		for _, value := range route.Headers[name] {
+			if regex, found := strings.CutPrefix(value, "~*"); found {
+				predicates = append(predicates, field+" ~ "+atcQuote("(?i)"+regex))
+			} else {
+				predicates = append(predicates, "lower("+field+") == "+atcQuote(strings.ToLower(value)))
+			}
+		}
+		groups = append(groups, atcAny(predicates))
+	}
+
+	if len(route.Snis) > 0 {
+		var predicates []string
+
+		for _, sni := range route.Snis {
+			predicates = append(predicates, "tls.sni == "+atcQuote(sni))
+		}
+		groups = append(groups, atcAny(predicates))
+	}
+
+	if len(route.Sources) > 0 {
+		groups = append(groups, atcEndpoints("src", route.Sources))
+	}
+
+	if len(route.Destinations) > 0 {
+		groups = append(groups, atcEndpoints("dst", route.Destinations))
+	}
+
+	return strings.Join(groups, " && ")
+}
@@ -0,0 +1,84 @@
+////////////////////////////////////////////////////////////////////////////////
+//	expression_test.go  -  Oct-17-2026  -  aldebap
+//
+//	Test cases for Kong expressions router
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"testing"
+)
+
+// Test_validateExpression unit tests for validateExpression() function
+func Test_validateExpression(t *testing.T) {
+
+	t.Run(">>> validateExpression: scenario 1 - valid expressions", func(t *testing.T) {
+
+		for _, expression := range []string{
+			`http.path == "/produtos"`,
+			`http.path ^= "/produtos" && (http.method == "GET" || http.method == "POST")`,
+			`lower(http.host) =^ ".example.com" || !(http.headers.x_version == "v1")`,
+			`http.path ~ r#"^/produtos/\d+$"# && net.dst.port == 443`,
+			`net.src.ip in 10.0.0.0/8 && net.dst.ip == 192.168.0.1 && net.src.ip not in fe80::/10`,
+			`tls.sni == "api.example.com" && net.protocol == "https" && http.queries.page contains "1"`,
+		} {
+			err := validateExpression(expression)
+			if err != nil {
+				t.Errorf("failed validating expression: %s: success expected: result: %s", expression, err.Error())
+			}
+		}
+	})
+
+	t.Run(">>> validateExpression: scenario 2 - invalid expressions", func(t *testing.T) {
+
+		for expression, want := range map[string]string{
+			``:                        "invalid expression: empty expression",
+			`http.path == "/produtos`: "invalid expression: unterminated string at position 14",
+			`http.uri == "/produtos"`: "invalid expression: unknown field: http.uri at position 1",
+			`http.path = "/produtos"`: "invalid expression: unexpected character '=' at position 11",
+			`http.path == 80`:         "invalid expression: expected a string value for field http.path at position 14",
+			`(http.path == "/" && http.method == "GET"`: "invalid expression: expected ')' at position 42",
+			`http.path == "/" http.method == "GET"`:     "invalid expression: unexpected 'http.method' at position 18",
+			`net.src.ip in 10.0.0.1`:                    "invalid expression: operators in and not in require a CIDR, == and != an IP address at position 15",
+			`lower(net.dst.port) == 80`:                 "invalid expression: lower() requires a string field: net.dst.port at position 7",
+			`http.path ~ "[a-"`:                         "invalid expression: invalid regex: [a- at position 13",
+		} {
+			got := validateExpression(expression)
+			if got == nil || got.Error() != want {
+				t.Errorf("failed validating expression: %s: error expected: %s result: %v", expression, want, got)
+			}
+		}
+	})
+}
+
+// Test_routeExpression unit tests for routeExpression() function
+func Test_routeExpression(t *testing.T) {
+
+	t.Run(">>> routeExpression: scenario 1 - traditional route converted", func(t *testing.T) {
+
+		route := &KongRouteResponse{
+			Protocols: []string{"http", "https"},
+			Methods:   []string{"GET", "POST"},
+			Paths:     []string{"/produtos", "~/pedidos/\\d+$"},
+			Hosts:     []string{"*.example.com", "api.example.com"},
+			Headers:   map[string][]string{"X-Version": {"V2"}, "X-Tenant": {"~*^acme-\\d+$"}},
+			Sources:   []KongRouteEndpoint{{IP: "10.0.0.0/8"}},
+		}
+
+		want := `(http.method == "GET" || http.method == "POST") && (http.host =^ ".example.com" || http.host == "api.example.com") && ` +
+			`(http.path ^= "/produtos" || http.path ~ "^/pedidos/\\d+$") && http.headers.x_tenant ~ "(?i)^acme-\\d+$" && lower(http.headers.x_version) == "v2" && ` +
+			`net.src.ip in 10.0.0.0/8`
+		got := routeExpression(route)
+
+		//	check the invocation result
+		if want != got {
+			t.Errorf("failed converting route: expected: %s result: %s", want, got)
+		}
+
+		err := validateExpression(got)
+		if err != nil {
+			t.Errorf("failed converting route: valid expression expected: result: %s", err.Error())
+		}
+	})
+}
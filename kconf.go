@@ -8,40 +8,109 @@ package main
 
 import (
 	"errors"
+	"fmt"
 	"regexp"
 	"strconv"
 	"strings"
+	"time"
 )
 
 var (
-	nameRegEx                 *regexp.Regexp
-	urlRegEx                  *regexp.Regexp
-	enabledRegEx              *regexp.Regexp
-	protocolsRegEx            *regexp.Regexp
-	methodsRegEx              *regexp.Regexp
-	pathsRegEx                *regexp.Regexp
-	serviceIdRegEx            *regexp.Regexp
-	customIdRegEx             *regexp.Regexp
-	userNameRegEx             *regexp.Regexp
-	tagsRegEx                 *regexp.Regexp
-	routeIdRegEx              *regexp.Regexp
-	idRegEx                   *regexp.Regexp
-	passwordRegEx             *regexp.Regexp
-	algorithmRegEx            *regexp.Regexp
-	keyRegEx                  *regexp.Regexp
-	secretRegEx               *regexp.Regexp
-	ttlRegEx                  *regexp.Regexp
-	upstreamIdRegEx           *regexp.Regexp
-	targetRegEx               *regexp.Regexp
-	allowRegEx                *regexp.Regexp
-	denyRegEx                 *regexp.Regexp
-	secondRegEx               *regexp.Regexp
-	minutoRegEx               *regexp.Regexp
-	hourRegEx                 *regexp.Regexp
-	allowedPayloadSizeRegEx   *regexp.Regexp
-	sizeUnitRegEx             *regexp.Regexp
-	requireContentLengthRegEx *regexp.Regexp
-	logLevelRegEx             *regexp.Regexp
+	nameRegEx                    *regexp.Regexp
+	urlRegEx                     *regexp.Regexp
+	enabledRegEx                 *regexp.Regexp
+	protocolsRegEx               *regexp.Regexp
+	methodsRegEx                 *regexp.Regexp
+	pathsRegEx                   *regexp.Regexp
+	serviceIdRegEx               *regexp.Regexp
+	customIdRegEx                *regexp.Regexp
+	userNameRegEx                *regexp.Regexp
+	tagsRegEx                    *regexp.Regexp
+	routeIdRegEx                 *regexp.Regexp
+	idRegEx                      *regexp.Regexp
+	passwordRegEx                *regexp.Regexp
+	algorithmRegEx               *regexp.Regexp
+	keyRegEx                     *regexp.Regexp
+	secretRegEx                  *regexp.Regexp
+	ttlRegEx                     *regexp.Regexp
+	upstreamIdRegEx              *regexp.Regexp
+	targetRegEx                  *regexp.Regexp
+	allowRegEx                   *regexp.Regexp
+	denyRegEx                    *regexp.Regexp
+	secondRegEx                  *regexp.Regexp
+	minutoRegEx                  *regexp.Regexp
+	hourRegEx                    *regexp.Regexp
+	allowedPayloadSizeRegEx      *regexp.Regexp
+	sizeUnitRegEx                *regexp.Regexp
+	requireContentLengthRegEx    *regexp.Regexp
+	logLevelRegEx                *regexp.Regexp
+	fileRegEx                    *regexp.Regexp
+	outputRegEx                  *regexp.Regexp
+	pageSizeRegEx                *regexp.Regexp
+	limitRegEx                   *regexp.Regexp
+	offsetRegEx                  *regexp.Regexp
+	configRegEx                  *regexp.Regexp
+	configFileRegEx              *regexp.Regexp
+	contextOptionRegEx           *regexp.Regexp
+	certFileRegEx                *regexp.Regexp
+	keyFileRegEx                 *regexp.Regexp
+	certAltFileRegEx             *regexp.Regexp
+	keyAltFileRegEx              *regexp.Regexp
+	certificateIdRegEx           *regexp.Regexp
+	caCertificatesRegEx          *regexp.Regexp
+	clientCertificateRegEx       *regexp.Regexp
+	tlsVerifyRegEx               *regexp.Regexp
+	tlsVerifyDepthRegEx          *regexp.Regexp
+	withinRegEx                  *regexp.Regexp
+	cnRegEx                      *regexp.Regexp
+	sanRegEx                     *regexp.Regexp
+	daysRegEx                    *regexp.Regexp
+	caCertFileRegEx              *regexp.Regexp
+	caKeyFileRegEx               *regexp.Regexp
+	protocolRegEx                *regexp.Regexp
+	hostRegEx                    *regexp.Regexp
+	portRegEx                    *regexp.Regexp
+	pathRegEx                    *regexp.Regexp
+	retriesRegEx                 *regexp.Regexp
+	connectTimeoutRegEx          *regexp.Regexp
+	readTimeoutRegEx             *regexp.Regexp
+	writeTimeoutRegEx            *regexp.Regexp
+	hostsRegEx                   *regexp.Regexp
+	headersRegEx                 *regexp.Regexp
+	snisRegEx                    *regexp.Regexp
+	sourcesRegEx                 *regexp.Regexp
+	destinationsRegEx            *regexp.Regexp
+	stripPathRegEx               *regexp.Regexp
+	preserveHostRegEx            *regexp.Regexp
+	regexPriorityRegEx           *regexp.Regexp
+	pathHandlingRegEx            *regexp.Regexp
+	httpsRedirectStatusCodeRegEx *regexp.Regexp
+	requestBufferingRegEx        *regexp.Regexp
+	responseBufferingRegEx       *regexp.Regexp
+	expressionRegEx              *regexp.Regexp
+	credentialIdRegEx            *regexp.Regexp
+	groupRegEx                   *regexp.Regexp
+	clientIdRegEx                *regexp.Regexp
+	clientSecretRegEx            *regexp.Regexp
+	redirectUrisRegEx            *regexp.Regexp
+	subjectNameRegEx             *regexp.Regexp
+	caCertificateIdRegEx         *regexp.Regexp
+	consumerIdRegEx              *regexp.Regexp
+	consumerGroupIdRegEx         *regexp.Regexp
+	priorityRegEx                *regexp.Regexp
+	healthchecksFileRegEx        *regexp.Regexp
+	activeHTTPPathRegEx          *regexp.Regexp
+	healthcheckThresholdRegEx    *regexp.Regexp
+	weightRegEx                  *regexp.Regexp
+	targetHealthRegEx            *regexp.Regexp
+	fromRegEx                    *regexp.Regexp
+	toRegEx                      *regexp.Regexp
+	stepsRegEx                   *regexp.Regexp
+	intervalRegEx                *regexp.Regexp
+	hashRegEx                    *regexp.Regexp
+	slotsRegEx                   *regexp.Regexp
+	hostHeaderRegEx              *regexp.Regexp
+	useSrvNameRegEx              *regexp.Regexp
 )
 
 func compileRegExp() error {
@@ -189,421 +258,2170 @@ func compileRegExp() error {
 		return err
 	}
 
+	fileRegEx, err = regexp.Compile(`^--?(?:f|file)\s*=\s*(\S.*)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	outputRegEx, err = regexp.Compile(`^--?(?:o|output)\s*=\s*(\S.*)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	pageSizeRegEx, err = regexp.Compile(`^--page-size\s*=\s*(\S.*)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	limitRegEx, err = regexp.Compile(`^--limit\s*=\s*(\S.*)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	offsetRegEx, err = regexp.Compile(`^--offset\s*=\s*(\S.*)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	configRegEx, err = regexp.Compile(`^--config\.([\w.-]+)\s*=\s*(.*?)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	configFileRegEx, err = regexp.Compile(`^--config-file\s*=\s*(\S.*)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	contextOptionRegEx, err = regexp.Compile(`^--([\w-]+)\s*=\s*(.*?)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	certFileRegEx, err = regexp.Compile(`^--cert-file\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	keyFileRegEx, err = regexp.Compile(`^--key-file\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	certAltFileRegEx, err = regexp.Compile(`^--cert-alt-file\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	keyAltFileRegEx, err = regexp.Compile(`^--key-alt-file\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	certificateIdRegEx, err = regexp.Compile(`^--certificate-id\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	caCertificatesRegEx, err = regexp.Compile(`^--ca-certificates\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	clientCertificateRegEx, err = regexp.Compile(`^--client-certificate\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	tlsVerifyRegEx, err = regexp.Compile(`^--tls-verify\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	tlsVerifyDepthRegEx, err = regexp.Compile(`^--tls-verify-depth\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	withinRegEx, err = regexp.Compile(`^--within\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	cnRegEx, err = regexp.Compile(`^--cn\s*=\s*(\S.*?)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	sanRegEx, err = regexp.Compile(`^--san\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	daysRegEx, err = regexp.Compile(`^--days\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	caCertFileRegEx, err = regexp.Compile(`^--ca-cert-file\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	caKeyFileRegEx, err = regexp.Compile(`^--ca-key-file\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	protocolRegEx, err = regexp.Compile(`^--protocol\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	hostRegEx, err = regexp.Compile(`^--host\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	portRegEx, err = regexp.Compile(`^--port\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	pathRegEx, err = regexp.Compile(`^--path\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	retriesRegEx, err = regexp.Compile(`^--retries\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	connectTimeoutRegEx, err = regexp.Compile(`^--connect-timeout\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	readTimeoutRegEx, err = regexp.Compile(`^--read-timeout\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	writeTimeoutRegEx, err = regexp.Compile(`^--write-timeout\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	hostsRegEx, err = regexp.Compile(`^--hosts\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	headersRegEx, err = regexp.Compile(`^--headers\s*=\s*(\S.*?)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	snisRegEx, err = regexp.Compile(`^--snis\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	sourcesRegEx, err = regexp.Compile(`^--sources\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	destinationsRegEx, err = regexp.Compile(`^--destinations\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	stripPathRegEx, err = regexp.Compile(`^--strip-path\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	preserveHostRegEx, err = regexp.Compile(`^--preserve-host\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	regexPriorityRegEx, err = regexp.Compile(`^--regex-priority\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	pathHandlingRegEx, err = regexp.Compile(`^--path-handling\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	httpsRedirectStatusCodeRegEx, err = regexp.Compile(`^--https-redirect-status-code\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	requestBufferingRegEx, err = regexp.Compile(`^--request-buffering\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	responseBufferingRegEx, err = regexp.Compile(`^--response-buffering\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	expressionRegEx, err = regexp.Compile(`^--expression\s*=\s*(\S.*)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	priorityRegEx, err = regexp.Compile(`^--priority\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	credentialIdRegEx, err = regexp.Compile(`^--credential-id\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	groupRegEx, err = regexp.Compile(`^--group\s*=\s*(\S.*)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	clientIdRegEx, err = regexp.Compile(`^--client-id\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	clientSecretRegEx, err = regexp.Compile(`^--client-secret\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	redirectUrisRegEx, err = regexp.Compile(`^--redirect-uris\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	subjectNameRegEx, err = regexp.Compile(`^--subject-name\s*=\s*(\S.*)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	caCertificateIdRegEx, err = regexp.Compile(`^--ca-certificate-id\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	consumerIdRegEx, err = regexp.Compile(`^--consumer-id\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	consumerGroupIdRegEx, err = regexp.Compile(`^--consumer-group-id\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	healthchecksFileRegEx, err = regexp.Compile(`^--healthchecks-file\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	activeHTTPPathRegEx, err = regexp.Compile(`^--active-http-path\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	healthcheckThresholdRegEx, err = regexp.Compile(`^--(active|passive)-(healthy|unhealthy)-(interval|successes|http-failures|tcp-failures|timeouts)\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	weightRegEx, err = regexp.Compile(`^--weight\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	targetHealthRegEx, err = regexp.Compile(`^--(healthy|unhealthy)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	fromRegEx, err = regexp.Compile(`^--from\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	toRegEx, err = regexp.Compile(`^--to\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	stepsRegEx, err = regexp.Compile(`^--steps\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	intervalRegEx, err = regexp.Compile(`^--interval\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	hashRegEx, err = regexp.Compile(`^--(hash-on|hash-fallback)(-header|-cookie|-cookie-path|-query-arg|-uri-capture)?\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	slotsRegEx, err = regexp.Compile(`^--slots\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	hostHeaderRegEx, err = regexp.Compile(`^--host-header\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
+	useSrvNameRegEx, err = regexp.Compile(`^--use-srv-name\s*=\s*(\S+)\s*$`)
+	if err != nil {
+		return err
+	}
+
 	return nil
 }
 
-// kconf utility
-func kconf(myKongServer KongServer, command []string, options Options) error {
+// kconf utility
+func kconf(myKongServer KongServer, command []string, options Options) error {
+
+	if len(command) == 0 {
+		return errors.New("missing command: available commands: status, add, query, list, update, delete, apply, dump, diff, schema, context, certificate, route, consumer-group, health, upstream, upstream-target")
+	}
+
+	err := compileRegExp()
+	if err != nil {
+		return errors.New("internal error compiling regexp: " + err.Error())
+	}
+
+	//	command to get Kong status
+	switch command[0] {
+	case "status":
+		return myKongServer.CheckStatus(options)
+
+	case "add":
+		return commandAdd(myKongServer, command[1:], options)
+
+	case "query":
+		return commandQuery(myKongServer, command[1:], options)
+
+	case "list":
+		return commandList(myKongServer, command[1:], options)
+
+	case "update":
+		return commandUpdate(myKongServer, command[1:], options)
+
+	case "delete":
+		return commandDelete(myKongServer, command[1:], options)
+
+	case "apply":
+		return commandApply(myKongServer, command[1:], options)
+
+	case "dump":
+		return commandDump(myKongServer, command[1:], options)
+
+	case "diff":
+		return commandDiff(myKongServer, command[1:], options)
+
+	case "schema":
+		return commandSchema(myKongServer, command[1:], options)
+
+	case "context":
+		return commandContext(command[1:], options)
+
+	case "certificate":
+		return commandCertificate(myKongServer, command[1:], options)
+
+	case "route":
+		return commandRoute(myKongServer, command[1:], options)
+
+	case "consumer-group":
+		return commandConsumerGroup(myKongServer, command[1:], options)
+
+	case "health":
+		return commandHealth(myKongServer, command[1:], options)
+
+	case "upstream":
+		return commandUpstream(myKongServer, command[1:], options)
+
+	case "upstream-target":
+		return commandUpstreamTarget(myKongServer, command[1:], options)
+	}
+
+	return errors.New("invalid command: " + command[0])
+}
+
+// get a file name from the command options: either -x {file} or --xxx={file}
+func fileOption(command []string, shortOption string, longOption string, optionRegEx *regexp.Regexp) string {
+	var fileName string
+
+	for i := 0; i < len(command); i++ {
+		if (command[i] == shortOption || command[i] == longOption) && i+1 < len(command) {
+			i++
+			fileName = command[i]
+			continue
+		}
+
+		match := optionRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			fileName = match[0][1]
+		}
+	}
+
+	return fileName
+}
+
+// command apply
+func commandApply(myKongServer KongServer, command []string, options Options) error {
+
+	fileName := fileOption(command, "-f", "--file", fileRegEx)
+	if len(fileName) == 0 {
+		return errors.New("missing state file: option -f {file} required for this command")
+	}
+
+	desiredState, err := LoadKongState(fileName)
+	if err != nil {
+		return err
+	}
+
+	return myKongServer.ApplyState(desiredState, options)
+}
+
+// command add
+func commandAdd(myKongServer KongServer, command []string, options Options) error {
+
+	if len(command) == 0 {
+		return errors.New("missing entity for command add: available entities: service, route, consumer, plugin, upstream, certificate, sni, ca-certificate, consumer-group")
+	}
+
+	switch command[0] {
+	case "service":
+		newService, err := serviceOptions(command[1:])
+		if err != nil {
+			return err
+		}
+
+		return myKongServer.AddService(newService, options)
+
+	case "route":
+		newKongRoute, err := routeOptions(command[1:])
+		if err != nil {
+			return err
+		}
+
+		return myKongServer.AddRoute(newKongRoute, options)
+
+	case "consumer":
+		const valuesDelim = ","
+		var customId string
+		var userName string
+		var tags []string
+
+		for i := 1; i < len(command); i++ {
+			match := customIdRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				customId = match[0][1]
+			}
+
+			match = userNameRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				userName = match[0][1]
+			}
+
+			match = tagsRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				tags = strings.Split(match[0][1], valuesDelim)
+			}
+		}
+		newKongConsumer := NewKongConsumer(customId, userName, tags)
+
+		return myKongServer.AddConsumer(newKongConsumer, options)
+
+	case "consumer-basic-auth":
+		var id string
+		var userName string
+		var password string
+
+		for i := 1; i < len(command); i++ {
+			match := idRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				id = match[0][1]
+			}
+
+			match = userNameRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				userName = match[0][1]
+			}
+
+			match = passwordRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				password = match[0][1]
+			}
+		}
+		if len(id) == 0 {
+			return errors.New("missing consumer id: option --id={id} required for this command")
+		}
+
+		newKongBasicAuthConfig := NewKongBasicAuthConfig(userName, password)
+
+		return myKongServer.AddConsumerBasicAuth(id, newKongBasicAuthConfig, options)
+
+	case "consumer-key-auth":
+		var id string
+		var key string
+		var ttl int
+		var err error
+
+		for i := 1; i < len(command); i++ {
+			match := idRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				id = match[0][1]
+			}
+
+			match = keyRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				key = match[0][1]
+			}
+
+			match = ttlRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				ttl, err = strconv.Atoi(match[0][1])
+				if err != nil {
+					return errors.New("Value for option --ttl must be an integer: " + err.Error())
+				}
+			}
+		}
+		if len(id) == 0 {
+			return errors.New("missing consumer id: option --id={id} required for this command")
+		}
+
+		newKongKeyAuthConfig := NewKongKeyAuthConfig(key, int64(ttl))
+
+		return myKongServer.AddConsumerKeyAuth(id, newKongKeyAuthConfig, options)
+
+	case "consumer-jwt":
+		var id string
+		var algorithm string
+		var key string
+		var secret string
+
+		for i := 1; i < len(command); i++ {
+			match := idRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				id = match[0][1]
+			}
+
+			match = algorithmRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				algorithm = match[0][1]
+			}
+
+			match = keyRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				key = match[0][1]
+			}
+
+			match = secretRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				secret = match[0][1]
+			}
+		}
+		if len(id) == 0 {
+			return errors.New("missing consumer id: option --id={id} required for this command")
+		}
+
+		newKongJWTConfig := NewKongJWTConfig(algorithm, key, secret)
+
+		return myKongServer.AddConsumerJWT(id, newKongJWTConfig, options)
+
+	case "consumer-acl":
+		const valuesDelim = ","
+		var id string
+		var group string
+		var tags []string
+
+		for i := 1; i < len(command); i++ {
+			match := idRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				id = match[0][1]
+			}
+
+			match = groupRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				group = match[0][1]
+			}
+
+			match = tagsRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				tags = strings.Split(match[0][1], valuesDelim)
+			}
+		}
+		if len(id) == 0 {
+			return errors.New("missing consumer id: option --id={id} required for this command")
+		}
+		if len(group) == 0 {
+			return errors.New("missing ACL group: option --group={group} required for this command")
+		}
+
+		return myKongServer.AddConsumerACL(id, NewKongACLConfig(group, tags), options)
+
+	case "consumer-hmac-auth":
+		const valuesDelim = ","
+		var id string
+		var userName string
+		var secret string
+		var tags []string
+
+		for i := 1; i < len(command); i++ {
+			match := idRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				id = match[0][1]
+			}
+
+			match = userNameRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				userName = match[0][1]
+			}
+
+			match = secretRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				secret = match[0][1]
+			}
+
+			match = tagsRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				tags = strings.Split(match[0][1], valuesDelim)
+			}
+		}
+		if len(id) == 0 {
+			return errors.New("missing consumer id: option --id={id} required for this command")
+		}
+		if len(userName) == 0 {
+			return errors.New("missing user name: option --user-name={name} required for this command")
+		}
+
+		return myKongServer.AddConsumerHMACAuth(id, NewKongHMACAuthConfig(userName, secret, tags), options)
+
+	case "consumer-oauth2":
+		const valuesDelim = ","
+		var id string
+		var name string
+		var clientId string
+		var clientSecret string
+		var redirectURIs []string
+		var tags []string
+
+		for i := 1; i < len(command); i++ {
+			match := idRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				id = match[0][1]
+			}
+
+			match = nameRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				name = match[0][1]
+			}
+
+			match = clientIdRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				clientId = match[0][1]
+			}
+
+			match = clientSecretRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				clientSecret = match[0][1]
+			}
+
+			match = redirectUrisRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				redirectURIs = strings.Split(match[0][1], valuesDelim)
+			}
+
+			match = tagsRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				tags = strings.Split(match[0][1], valuesDelim)
+			}
+		}
+		if len(id) == 0 {
+			return errors.New("missing consumer id: option --id={id} required for this command")
+		}
+		if len(name) == 0 {
+			return errors.New("missing application name: option --name={name} required for this command")
+		}
+
+		return myKongServer.AddConsumerOAuth2(id, NewKongOAuth2Config(name, clientId, clientSecret, redirectURIs, tags), options)
+
+	case "consumer-mtls-auth":
+		const valuesDelim = ","
+		var id string
+		var subjectName string
+		var caCertificateId string
+		var tags []string
+
+		for i := 1; i < len(command); i++ {
+			match := idRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				id = match[0][1]
+			}
+
+			match = subjectNameRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				subjectName = match[0][1]
+			}
+
+			match = caCertificateIdRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				caCertificateId = match[0][1]
+			}
+
+			match = tagsRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				tags = strings.Split(match[0][1], valuesDelim)
+			}
+		}
+		if len(id) == 0 {
+			return errors.New("missing consumer id: option --id={id} required for this command")
+		}
+		if len(subjectName) == 0 {
+			return errors.New("missing subject name: option --subject-name={name} required for this command")
+		}
+
+		return myKongServer.AddConsumerMTLSAuth(id, NewKongMTLSAuthConfig(subjectName, caCertificateId, tags), options)
+
+	case "consumer-ip-restriction":
+		const valuesDelim = ","
+		var id string
+		var name string
+		var allow []string
+		var deny []string
+
+		for i := 1; i < len(command); i++ {
+			match := idRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				id = match[0][1]
+			}
+
+			match = nameRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				name = match[0][1]
+			}
+
+			match = allowRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				allow = strings.Split(match[0][1], valuesDelim)
+			}
+
+			match = denyRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				deny = strings.Split(match[0][1], valuesDelim)
+			}
+		}
+		if len(id) == 0 {
+			return errors.New("missing consumer id: option --id={id} required for this command")
+		}
+
+		newKongIPRestrictionConfig := NewKongIPRestrictionPlugin(name, allow, deny)
+
+		return myKongServer.AddConsumerIPRestriction(id, newKongIPRestrictionConfig, options)
+
+	case "consumer-rate-limiting":
+		var id string
+		var name string
+		var second int
+		var minute int
+		var hour int
+		var errorCode int = 429
+		var errorMessage string = "API rate limit exceeded"
+
+		for i := 1; i < len(command); i++ {
+			match := idRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				id = match[0][1]
+			}
+
+			match = nameRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				name = match[0][1]
+			}
+
+			match = secondRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				second, _ = strconv.Atoi(match[0][1])
+			}
+
+			match = minutoRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				minute, _ = strconv.Atoi(match[0][1])
+			}
+
+			match = hourRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				hour, _ = strconv.Atoi(match[0][1])
+			}
+		}
+		if len(id) == 0 {
+			return errors.New("missing consumer id: option --id={id} required for this command")
+		}
+
+		newKongRateLimitingPlugin := NewKongRateLimitingPlugin(name, int32(second), int32(minute), int32(hour), int32(errorCode), errorMessage)
+
+		return myKongServer.AddConsumerRateLimiting(id, newKongRateLimitingPlugin, options)
+
+	case "consumer-request-size-limiting":
+		var id string
+		var name string
+		var allowedPayloadSize int
+		var sizeUnit string
+		var requireContentLength bool
+
+		for i := 1; i < len(command); i++ {
+			match := idRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				id = match[0][1]
+			}
+
+			match = nameRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				name = match[0][1]
+			}
+
+			match = allowedPayloadSizeRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				allowedPayloadSize, _ = strconv.Atoi(match[0][1])
+			}
+
+			match = sizeUnitRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				sizeUnit = match[0][1]
+			}
+
+			match = requireContentLengthRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				switch match[0][1] {
+				case "false":
+					requireContentLength = false
+
+				case "true":
+					requireContentLength = true
+
+				default:
+					return errors.New("wrong value for option --require-content-length: " + match[0][1])
+				}
+			}
+		}
+		if len(id) == 0 {
+			return errors.New("missing consumer id: option --id={id} required for this command")
+		}
+
+		newKongRequestSizeLimitingPlugin := NewKongRequestSizeLimitingPlugin(name, int32(allowedPayloadSize), sizeUnit, requireContentLength)
+
+		return myKongServer.AddConsumerRequestSizeLimiting(id, newKongRequestSizeLimitingPlugin, options)
+
+	case "consumer-syslog":
+		var id string
+		var name string
+		var logLevel string
+
+		for i := 1; i < len(command); i++ {
+			match := idRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				id = match[0][1]
+			}
+
+			match = nameRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				name = match[0][1]
+			}
+
+			match = logLevelRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				logLevel = match[0][1]
+			}
+		}
+		if len(id) == 0 {
+			return errors.New("missing consumer id: option --id={id} required for this command")
+		}
+
+		newKongSyslogPlugin := NewKongSyslogPlugin(name, logLevel)
+
+		return myKongServer.AddConsumerSyslog(id, newKongSyslogPlugin, options)
+
+	case "plugin":
+		var name string
+		var serviceId string
+		var routeId string
+		var consumerGroupId string
+		var enabled bool = true
+		var config []KongPluginConfig
+		var configFile string
+
+		for i := 1; i < len(command); i++ {
+			match := nameRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				name = match[0][1]
+			}
+
+			match = configRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				config = append(config, KongPluginConfig{key: match[0][1], value: match[0][2]})
+			}
+
+			match = configFileRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				configFile = match[0][1]
+			}
+
+			match = serviceIdRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				serviceId = match[0][1]
+			}
+
+			match = routeIdRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				routeId = match[0][1]
+			}
+
+			match = consumerGroupIdRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				consumerGroupId = match[0][1]
+			}
+
+			match = enabledRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				switch match[0][1] {
+				case "false":
+					enabled = false
+
+				case "true":
+					enabled = true
+
+				default:
+					return errors.New("wrong value for option --enabled: " + match[0][1])
+				}
+			}
+		}
+		pluginConfig, err := NewKongPluginConfig(configFile, config)
+		if err != nil {
+			return err
+		}
+		newKongPlugin := NewKongPlugin(name, serviceId, routeId, pluginConfig, enabled)
+		newKongPlugin.setConsumerGroup(consumerGroupId)
+
+		return myKongServer.AddPlugin(newKongPlugin, options)
+
+	case "upstream":
+		newKongUpstream, err := upstreamOptions(command[1:])
+		if err != nil {
+			return err
+		}
+
+		return myKongServer.AddUpstream(newKongUpstream, options)
+
+	case "upstream-target":
+		var upstreamId string
+
+		for i := 1; i < len(command); i++ {
+			match := upstreamIdRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				upstreamId = match[0][1]
+			}
+		}
+
+		if len(upstreamId) == 0 {
+			return errors.New("missing upstream id: option --upstream-id={id} required for this command")
+		}
+
+		newKongUpstreamTarget, err := upstreamTargetOptions(command[1:])
+		if err != nil {
+			return err
+		}
+
+		return myKongServer.AddUpstreamTarget(upstreamId, newKongUpstreamTarget, options)
+
+	case "certificate":
+		newKongCertificate, err := certificateOptions(command[1:])
+		if err != nil {
+			return err
+		}
+
+		if len(newKongCertificate.cert) == 0 || len(newKongCertificate.key) == 0 {
+			return errors.New("missing certificate: options --cert-file={file} and --key-file={file} required for this command")
+		}
+
+		return myKongServer.AddCertificate(newKongCertificate, options)
+
+	case "sni":
+		newKongSNI := sniOptions(command[1:])
+
+		if len(newKongSNI.name) == 0 {
+			return errors.New("missing sni name: option --name={name} required for this command")
+		}
+
+		if len(newKongSNI.certificateId) == 0 {
+			return errors.New("missing certificate id: option --certificate-id={id} required for this command")
+		}
+
+		return myKongServer.AddSNI(newKongSNI, options)
+
+	case "ca-certificate":
+		newKongCACertificate, err := caCertificateOptions(command[1:])
+		if err != nil {
+			return err
+		}
+
+		if len(newKongCACertificate.cert) == 0 {
+			return errors.New("missing ca certificate: option --cert-file={file} required for this command")
+		}
+
+		return myKongServer.AddCACertificate(newKongCACertificate, options)
+
+	case "consumer-group":
+		newKongConsumerGroup := consumerGroupOptions(command[1:])
+
+		if len(newKongConsumerGroup.name) == 0 {
+			return errors.New("missing consumer group name: option --name={name} required for this command")
+		}
+
+		return myKongServer.AddConsumerGroup(newKongConsumerGroup, options)
+	}
+
+	return errors.New("invalid entity for command add: " + command[0])
+}
+
+// get the service attributes from the command options
+func serviceOptions(command []string) (*KongService, error) {
+	const valuesDelim = ","
+	var name string
+	var url string
+	var enabled bool = true
+	var protocol, host, path string
+	var port, retries, connectTimeout, readTimeout, writeTimeout *int
+	var tags []string
+	var caCertificates []string
+	var clientCertificate string
+	var tlsVerify *bool
+	var tlsVerifyDepth *int
+
+	for i := 0; i < len(command); i++ {
+		match := nameRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			name = match[0][1]
+		}
+
+		match = urlRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			url = match[0][1]
+		}
+
+		match = enabledRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			switch match[0][1] {
+			case "false":
+				enabled = false
+
+			case "true":
+				enabled = true
+
+			default:
+				return nil, errors.New("wrong value for option --enabled: " + match[0][1])
+			}
+		}
+
+		match = protocolRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			protocol = match[0][1]
+		}
+
+		match = hostRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			host = match[0][1]
+		}
+
+		match = portRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			value, err := intOption("port", match[0][1], 0, maxPort)
+			if err != nil {
+				return nil, err
+			}
+			port = value
+		}
+
+		match = pathRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			path = match[0][1]
+		}
+
+		match = retriesRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			value, err := intOption("retries", match[0][1], 0, maxServiceRetries)
+			if err != nil {
+				return nil, err
+			}
+			retries = value
+		}
+
+		match = connectTimeoutRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			value, err := intOption("connect-timeout", match[0][1], 1, maxServiceTimeout)
+			if err != nil {
+				return nil, err
+			}
+			connectTimeout = value
+		}
+
+		match = readTimeoutRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			value, err := intOption("read-timeout", match[0][1], 1, maxServiceTimeout)
+			if err != nil {
+				return nil, err
+			}
+			readTimeout = value
+		}
+
+		match = writeTimeoutRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			value, err := intOption("write-timeout", match[0][1], 1, maxServiceTimeout)
+			if err != nil {
+				return nil, err
+			}
+			writeTimeout = value
+		}
+
+		match = tagsRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			tags = strings.Split(match[0][1], valuesDelim)
+		}
+
+		match = caCertificatesRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			caCertificates = strings.Split(match[0][1], valuesDelim)
+		}
+
+		match = clientCertificateRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			clientCertificate = match[0][1]
+		}
+
+		match = tlsVerifyRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			value, err := boolOption("tls-verify", match[0][1])
+			if err != nil {
+				return nil, err
+			}
+			tlsVerify = value
+		}
+
+		match = tlsVerifyDepthRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			value, err := intOption("tls-verify-depth", match[0][1], 0, maxTLSVerifyDepth)
+			if err != nil {
+				return nil, err
+			}
+			tlsVerifyDepth = value
+		}
+	}
+
+	//	the URL is a shorthand for protocol, host, port and path
+	if len(url) > 0 && (len(protocol) > 0 || len(host) > 0 || port != nil || len(path) > 0) {
+		return nil, errors.New("option --url can't be used with options --protocol, --host, --port or --path")
+	}
+
+	service := NewKongService(name, url, enabled)
+	service.setAddress(protocol, host, port, path)
+	service.setTimeouts(retries, connectTimeout, readTimeout, writeTimeout)
+	service.setTags(tags)
+	service.setTLS(caCertificates, clientCertificate, tlsVerify, tlsVerifyDepth)
+
+	return service, nil
+}
+
+// get the route attributes from the command options
+func routeOptions(command []string) (*KongRoute, error) {
+	const valuesDelim = ","
+	var name string
+	var protocols []string
+	var methods []string
+	var paths []string
+	var serviceId string
+	var hosts, snis, tags []string
+	var headers map[string][]string
+	var sources, destinations []KongRouteEndpoint
+	var stripPath, preserveHost, requestBuffering, responseBuffering *bool
+	var regexPriority, httpsRedirectStatusCode *int
+	var pathHandling string
+	var expression string
+	var priority *int64
+	var err error
+
+	for i := 0; i < len(command); i++ {
+		match := nameRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			name = match[0][1]
+		}
+
+		match = protocolsRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			protocols = strings.Split(match[0][1], valuesDelim)
+		}
+
+		match = methodsRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			methods = strings.Split(match[0][1], valuesDelim)
+		}
+
+		match = pathsRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			paths = strings.Split(match[0][1], valuesDelim)
+		}
+
+		match = serviceIdRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			serviceId = match[0][1]
+		}
+
+		match = hostsRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			hosts = strings.Split(match[0][1], valuesDelim)
+		}
+
+		//	the option --headers can be repeated, one for each header
+		match = headersRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			header, values, err := parseRouteHeader(match[0][1])
+			if err != nil {
+				return nil, errors.New("wrong value for option --headers: " + err.Error())
+			}
+			if headers == nil {
+				headers = make(map[string][]string)
+			}
+			headers[header] = append(headers[header], values...)
+		}
+
+		match = snisRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			snis = strings.Split(match[0][1], valuesDelim)
+		}
+
+		match = sourcesRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			sources, err = parseRouteEndpoints(match[0][1])
+			if err != nil {
+				return nil, errors.New("wrong value for option --sources: " + err.Error())
+			}
+		}
+
+		match = destinationsRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			destinations, err = parseRouteEndpoints(match[0][1])
+			if err != nil {
+				return nil, errors.New("wrong value for option --destinations: " + err.Error())
+			}
+		}
+
+		match = stripPathRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			stripPath, err = boolOption("strip-path", match[0][1])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		match = preserveHostRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			preserveHost, err = boolOption("preserve-host", match[0][1])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		match = regexPriorityRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			regexPriority, err = intOption("regex-priority", match[0][1], -maxRegexPriority, maxRegexPriority)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		match = pathHandlingRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			pathHandling = match[0][1]
+			if pathHandling != "v0" && pathHandling != "v1" {
+				return nil, errors.New("wrong value for option --path-handling: " + pathHandling + ": must be v0 or v1")
+			}
+		}
+
+		match = httpsRedirectStatusCodeRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			switch match[0][1] {
+			case "426", "301", "302", "307", "308":
+				statusCode, _ := strconv.Atoi(match[0][1])
+				httpsRedirectStatusCode = &statusCode
+
+			default:
+				return nil, errors.New("wrong value for option --https-redirect-status-code: " + match[0][1] + ": must be 426, 301, 302, 307 or 308")
+			}
+		}
+
+		match = requestBufferingRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			requestBuffering, err = boolOption("request-buffering", match[0][1])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		match = responseBufferingRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			responseBuffering, err = boolOption("response-buffering", match[0][1])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		match = expressionRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			expression = strings.TrimSpace(match[0][1])
+
+			err = validateExpression(expression)
+			if err != nil {
+				return nil, errors.New("wrong value for option --expression: " + err.Error())
+			}
+		}
+
+		match = priorityRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			value, err := strconv.ParseInt(match[0][1], 10, 64)
+			if err != nil || value < 0 || value > maxRoutePriority {
+				return nil, errors.New("wrong value for option --priority: " + match[0][1] + ": must be between 0 and " + strconv.FormatInt(maxRoutePriority, 10))
+			}
+			priority = &value
+		}
+
+		match = tagsRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			tags = strings.Split(match[0][1], valuesDelim)
+		}
+	}
+
+	//	the expressions router ignores the traditional matching attributes
+	if len(expression) > 0 && (len(methods) > 0 || len(paths) > 0 || len(hosts) > 0 || len(headers) > 0 ||
+		len(snis) > 0 || len(sources) > 0 || len(destinations) > 0 || regexPriority != nil) {
+		return nil, errors.New("option --expression can't be used with options --methods, --paths, --hosts, --headers, --snis, --sources, --destinations or --regex-priority")
+	}
+
+	route := NewKongRoute(name, protocols, methods, paths, serviceId)
+	route.setMatching(hosts, headers, snis, sources, destinations)
+	route.setProxying(stripPath, preserveHost, regexPriority, pathHandling, httpsRedirectStatusCode, requestBuffering, responseBuffering)
+	route.setExpression(expression, priority)
+	route.setTags(tags)
+
+	return route, nil
+}
+
+// parse a boolean option: true or false
+func boolOption(option string, value string) (*bool, error) {
+
+	var result bool
+
+	switch value {
+	case "true":
+		result = true
+
+	case "false":
+		result = false
+
+	default:
+		return nil, errors.New("wrong value for option --" + option + ": " + value)
+	}
+
+	return &result, nil
+}
+
+// parse an integer option within a range
+func intOption(option string, value string, min int, max int) (*int, error) {
+
+	number, err := strconv.Atoi(value)
+	if err != nil || number < min || number > max {
+		return nil, errors.New("wrong value for option --" + option + ": " + value + ": must be between " + strconv.Itoa(min) + " and " + strconv.Itoa(max))
+	}
+
+	return &number, nil
+}
+
+// get the CA certificate attributes from the command options
+func caCertificateOptions(command []string) (*KongCACertificate, error) {
+	const valuesDelim = ","
+	var certFile string
+	var tags []string
+
+	for i := 0; i < len(command); i++ {
+		match := certFileRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			certFile = match[0][1]
+		}
+
+		match = tagsRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			tags = strings.Split(match[0][1], valuesDelim)
+		}
+	}
+
+	cert, err := readPEMFile(certFile, "CERTIFICATE")
+	if err != nil {
+		return nil, err
+	}
+
+	return NewKongCACertificate(cert, tags), nil
+}
+
+// get the upstream attributes from the command options
+func upstreamOptions(command []string) (*KongUpstream, error) {
+	const valuesDelim = ","
+	var name string
+	var algorithm string
+	var tags []string
+	var hashing KongUpstreamHashing
+	var slots *int
+	var hostHeader string
+	var useSrvName *bool
+	var healthchecksFile string
+
+	for i := 0; i < len(command); i++ {
+		match := healthchecksFileRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			healthchecksFile = match[0][1]
+		}
+	}
 
-	if len(command) == 0 {
-		return errors.New("missing command: available commands: status, add, query, list")
+	healthchecks, err := NewKongUpstreamHealthchecks(healthchecksFile)
+	if err != nil {
+		return nil, err
 	}
 
-	err := compileRegExp()
+	for i := 0; i < len(command); i++ {
+		match := nameRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			name = match[0][1]
+		}
+
+		match = algorithmRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			algorithm = match[0][1]
+		}
+
+		match = hashRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			switch match[0][1] + match[0][2] {
+			case "hash-on":
+				hashing.HashOn = match[0][3]
+
+			case "hash-fallback":
+				hashing.HashFallback = match[0][3]
+
+			case "hash-on-header":
+				hashing.HashOnHeader = match[0][3]
+
+			case "hash-fallback-header":
+				hashing.HashFallbackHeader = match[0][3]
+
+			case "hash-on-cookie":
+				hashing.HashOnCookie = match[0][3]
+
+			case "hash-on-cookie-path":
+				hashing.HashOnCookiePath = match[0][3]
+
+			case "hash-on-query-arg":
+				hashing.HashOnQueryArg = match[0][3]
+
+			case "hash-fallback-query-arg":
+				hashing.HashFallbackQueryArg = match[0][3]
+
+			case "hash-on-uri-capture":
+				hashing.HashOnURICapture = match[0][3]
+
+			case "hash-fallback-uri-capture":
+				hashing.HashFallbackURICapture = match[0][3]
+
+			default:
+				return nil, errors.New("invalid option: " + command[i])
+			}
+		}
+
+		match = slotsRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			slots, err = intOption("slots", match[0][1], minUpstreamSlots, maxUpstreamSlots)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		match = hostHeaderRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			hostHeader = match[0][1]
+		}
+
+		match = useSrvNameRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			useSrvName, err = boolOption("use-srv-name", match[0][1])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		//	health checks options have precedence over the health checks file
+		match = activeHTTPPathRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			healthchecks.setActiveHTTPPath(match[0][1])
+		}
+
+		match = healthcheckThresholdRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			err = healthchecks.setThreshold(match[0][1], match[0][2], match[0][3], match[0][4])
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		match = tagsRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			tags = strings.Split(match[0][1], valuesDelim)
+		}
+	}
+
+	upstream := NewKongUpstream(name, algorithm, tags)
+	upstream.setBalancing(hashing, slots, hostHeader, useSrvName)
+	upstream.setHealthchecks(healthchecks)
+
+	err = upstream.validate()
 	if err != nil {
-		return errors.New("internal error compiling regexp: " + err.Error())
+		return nil, err
 	}
 
-	//	command to get Kong status
-	switch command[0] {
-	case "status":
-		return myKongServer.CheckStatus(options)
+	return upstream, nil
+}
 
-	case "add":
-		return commandAdd(myKongServer, command[1:], options)
+// get the upstream target attributes from the command options
+func upstreamTargetOptions(command []string) (*KongUpstreamTarget, error) {
+	const valuesDelim = ","
+	var target string
+	var weight *int
+	var tags []string
+	var err error
 
-	case "query":
-		return commandQuery(myKongServer, command[1:], options)
+	for i := 0; i < len(command); i++ {
+		match := targetRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			target = match[0][1]
+		}
 
-	case "list":
-		return commandList(myKongServer, command[1:], options)
+		match = weightRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			weight, err = intOption("weight", match[0][1], 0, maxUpstreamTargetWeight)
+			if err != nil {
+				return nil, err
+			}
+		}
 
-	case "update":
-		return commandUpdate(myKongServer, command[1:], options)
+		match = tagsRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			tags = strings.Split(match[0][1], valuesDelim)
+		}
+	}
 
-	case "delete":
-		return commandDelete(myKongServer, command[1:], options)
+	return NewKongUpstreamTarget(target, weight, tags), nil
+}
+
+// get the consumer group attributes from the command options
+func consumerGroupOptions(command []string) *KongConsumerGroup {
+	const valuesDelim = ","
+	var name string
+	var tags []string
+
+	for i := 0; i < len(command); i++ {
+		match := nameRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			name = match[0][1]
+		}
+
+		match = tagsRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			tags = strings.Split(match[0][1], valuesDelim)
+		}
 	}
 
-	return errors.New("invalid command: " + command[0])
+	return NewKongConsumerGroup(name, tags)
 }
 
-// command add
-func commandAdd(myKongServer KongServer, command []string, options Options) error {
+// get the SNI attributes from the command options
+func sniOptions(command []string) *KongSNI {
+	const valuesDelim = ","
+	var name, certificateId string
+	var tags []string
+
+	for i := 0; i < len(command); i++ {
+		match := nameRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			name = match[0][1]
+		}
+
+		match = certificateIdRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			certificateId = match[0][1]
+		}
+
+		match = tagsRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			tags = strings.Split(match[0][1], valuesDelim)
+		}
+	}
+
+	return NewKongSNI(name, certificateId, tags)
+}
+
+// get the certificate attributes from the PEM files in the command options
+func certificateOptions(command []string) (*KongCertificate, error) {
+	const valuesDelim = ","
+	var certFile, keyFile, certAltFile, keyAltFile string
+	var tags []string
+
+	for i := 0; i < len(command); i++ {
+		match := certFileRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			certFile = match[0][1]
+		}
+
+		match = keyFileRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			keyFile = match[0][1]
+		}
+
+		match = certAltFileRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			certAltFile = match[0][1]
+		}
+
+		match = keyAltFileRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			keyAltFile = match[0][1]
+		}
+
+		match = tagsRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			tags = strings.Split(match[0][1], valuesDelim)
+		}
+	}
+
+	cert, err := readPEMFile(certFile, "CERTIFICATE")
+	if err != nil {
+		return nil, err
+	}
+
+	key, err := readPEMFile(keyFile, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	certAlt, err := readPEMFile(certAltFile, "CERTIFICATE")
+	if err != nil {
+		return nil, err
+	}
+
+	keyAlt, err := readPEMFile(keyAltFile, "PRIVATE KEY")
+	if err != nil {
+		return nil, err
+	}
+
+	if (len(certAlt) == 0) != (len(keyAlt) == 0) {
+		return nil, errors.New("missing alternate certificate: options --cert-alt-file={file} and --key-alt-file={file} must be used together")
+	}
+
+	return NewKongCertificate(cert, key, certAlt, keyAlt, tags), nil
+}
+
+// command query
+func commandQuery(myKongServer KongServer, command []string, options Options) error {
 
 	if len(command) == 0 {
-		return errors.New("missing entity for command add: available entities: service, route, consumer, plugin, upstream")
+		return errors.New("missing entity for command query: available entities: service, route")
 	}
 
 	switch command[0] {
 	case "service":
-		var name string
-		var url string
-		var enabled bool = true
+		var id string
 
 		for i := 1; i < len(command); i++ {
-			match := nameRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				name = match[0][1]
-			}
-
-			match = urlRegEx.FindAllStringSubmatch(command[i], -1)
+			match := idRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				url = match[0][1]
+				id = match[0][1]
 			}
+		}
 
-			match = enabledRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				switch match[0][1] {
-				case "false":
-					enabled = false
-
-				case "true":
-					enabled = true
-
-				default:
-					return errors.New("wrong value for option --enabled: " + match[0][1])
-				}
-			}
+		if len(id) == 0 {
+			return errors.New("missing service id: option --id={id} required for this command")
 		}
-		newService := NewKongService(name, url, enabled)
 
-		return myKongServer.AddService(newService, options)
+		return myKongServer.QueryService(id, options)
 
 	case "route":
-		const valuesDelim = ","
-		var name string
-		var protocols []string
-		var methods []string
-		var paths []string
-		var serviceId string
+		var id string
 
 		for i := 1; i < len(command); i++ {
-			match := nameRegEx.FindAllStringSubmatch(command[i], -1)
+			match := idRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				name = match[0][1]
+				id = match[0][1]
 			}
+		}
 
-			match = protocolsRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				protocols = strings.Split(match[0][1], valuesDelim)
-			}
+		if len(id) == 0 {
+			return errors.New("missing route id: option --id={id} required for this command")
+		}
 
-			match = methodsRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				methods = strings.Split(match[0][1], valuesDelim)
-			}
+		return myKongServer.QueryRoute(id, options)
 
-			match = pathsRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				paths = strings.Split(match[0][1], valuesDelim)
-			}
+	case "consumer":
+		var id string
 
-			match = serviceIdRegEx.FindAllStringSubmatch(command[i], -1)
+		for i := 1; i < len(command); i++ {
+			match := idRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				serviceId = match[0][1]
+				id = match[0][1]
 			}
 		}
-		newKongRoute := NewKongRoute(name, protocols, methods, paths, serviceId)
 
-		return myKongServer.AddRoute(newKongRoute, options)
+		if len(id) == 0 {
+			return errors.New("missing consumer id: option --id={id} required for this command")
+		}
 
-	case "consumer":
-		const valuesDelim = ","
-		var customId string
-		var userName string
-		var tags []string
+		return myKongServer.QueryConsumer(id, options)
+
+	case "plugin":
+		var id string
 
 		for i := 1; i < len(command); i++ {
-			match := customIdRegEx.FindAllStringSubmatch(command[i], -1)
+			match := idRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				customId = match[0][1]
+				id = match[0][1]
 			}
+		}
 
-			match = userNameRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				userName = match[0][1]
-			}
+		if len(id) == 0 {
+			return errors.New("missing plugin id: option --id={id} required for this command")
+		}
 
-			match = tagsRegEx.FindAllStringSubmatch(command[i], -1)
+		return myKongServer.QueryPlugin(id, options)
+
+	case "upstream":
+		var id string
+
+		for i := 1; i < len(command); i++ {
+			match := idRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				tags = strings.Split(match[0][1], valuesDelim)
+				id = match[0][1]
 			}
 		}
-		newKongConsumer := NewKongConsumer(customId, userName, tags)
 
-		return myKongServer.AddConsumer(newKongConsumer, options)
+		if len(id) == 0 {
+			return errors.New("missing upstream id: option --id={id} required for this command")
+		}
 
-	case "consumer-basic-auth":
+		return myKongServer.QueryUpstream(id, options)
+
+	case "certificate":
 		var id string
-		var userName string
-		var password string
 
 		for i := 1; i < len(command); i++ {
 			match := idRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
 				id = match[0][1]
 			}
+		}
 
-			match = userNameRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				userName = match[0][1]
-			}
+		if len(id) == 0 {
+			return errors.New("missing certificate id: option --id={id} required for this command")
+		}
 
-			match = passwordRegEx.FindAllStringSubmatch(command[i], -1)
+		return myKongServer.QueryCertificate(id, options)
+
+	case "sni":
+		var id string
+
+		for i := 1; i < len(command); i++ {
+			match := idRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				password = match[0][1]
+				id = match[0][1]
 			}
 		}
+
 		if len(id) == 0 {
-			return errors.New("missing consumer id: option --id={id} required for this command")
+			return errors.New("missing sni id: option --id={id} required for this command")
 		}
 
-		newKongBasicAuthConfig := NewKongBasicAuthConfig(userName, password)
-
-		return myKongServer.AddConsumerBasicAuth(id, newKongBasicAuthConfig, options)
+		return myKongServer.QuerySNI(id, options)
 
-	case "consumer-key-auth":
+	case "ca-certificate":
 		var id string
-		var key string
-		var ttl int
-		var err error
 
 		for i := 1; i < len(command); i++ {
 			match := idRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
 				id = match[0][1]
 			}
-
-			match = keyRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				key = match[0][1]
-			}
-
-			match = ttlRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				ttl, err = strconv.Atoi(match[0][1])
-				if err != nil {
-					return errors.New("Value for option --ttl must be an integer: " + err.Error())
-				}
-			}
 		}
+
 		if len(id) == 0 {
-			return errors.New("missing consumer id: option --id={id} required for this command")
+			return errors.New("missing ca certificate id: option --id={id} required for this command")
 		}
 
-		newKongKeyAuthConfig := NewKongKeyAuthConfig(key, int64(ttl))
-
-		return myKongServer.AddConsumerKeyAuth(id, newKongKeyAuthConfig, options)
+		return myKongServer.QueryCACertificate(id, options)
 
-	case "consumer-jwt":
+	case "consumer-group":
 		var id string
-		var algorithm string
-		var key string
-		var secret string
 
 		for i := 1; i < len(command); i++ {
 			match := idRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
 				id = match[0][1]
 			}
+		}
 
-			match = algorithmRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				algorithm = match[0][1]
-			}
+		if len(id) == 0 {
+			return errors.New("missing consumer group id: option --id={id} required for this command")
+		}
 
-			match = keyRegEx.FindAllStringSubmatch(command[i], -1)
+		return myKongServer.QueryConsumerGroup(id, options)
+
+	case "upstream-target":
+		var upstreamId string
+		var id string
+
+		for i := 1; i < len(command); i++ {
+
+			match := upstreamIdRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				key = match[0][1]
+				upstreamId = match[0][1]
 			}
 
-			match = secretRegEx.FindAllStringSubmatch(command[i], -1)
+			match = idRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				secret = match[0][1]
+				id = match[0][1]
 			}
 		}
+
+		if len(upstreamId) == 0 {
+			return errors.New("missing upstream id: option --upstream-id={id} required for this command")
+		}
+
 		if len(id) == 0 {
-			return errors.New("missing consumer id: option --id={id} required for this command")
+			return errors.New("missing upstream target id: option --id={id} required for this command")
 		}
 
-		newKongJWTConfig := NewKongJWTConfig(algorithm, key, secret)
+		return myKongServer.QueryUpstreamTarget(upstreamId, id, options)
+	}
 
-		return myKongServer.AddConsumerJWT(id, newKongJWTConfig, options)
+	return errors.New("invalid entity for command query: " + command[0])
+}
 
-	case "consumer-ip-restriction":
-		const valuesDelim = ","
-		var id string
-		var name string
-		var allow []string
-		var deny []string
+// command list
+func commandList(myKongServer KongServer, command []string, options Options) error {
+
+	if len(command) == 0 {
+		return errors.New("missing entity for command list: available entities: service, route")
+	}
+
+	pagination, err := paginationOptions(command[1:])
+	if err != nil {
+		return err
+	}
+
+	switch command[0] {
+	case "service":
+		return myKongServer.ListServices(pagination, options)
+
+	case "route":
+		var serviceId string
 
 		for i := 1; i < len(command); i++ {
-			match := idRegEx.FindAllStringSubmatch(command[i], -1)
+			match := serviceIdRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				id = match[0][1]
+				serviceId = match[0][1]
 			}
+		}
 
-			match = nameRegEx.FindAllStringSubmatch(command[i], -1)
+		return myKongServer.ListRoutes(serviceId, pagination, options)
+
+	case "consumer":
+		return myKongServer.ListConsumers(pagination, options)
+
+	case "plugin":
+		var serviceId, routeId, consumerId string
+
+		for i := 1; i < len(command); i++ {
+			match := serviceIdRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				name = match[0][1]
+				serviceId = match[0][1]
 			}
 
-			match = allowRegEx.FindAllStringSubmatch(command[i], -1)
+			match = routeIdRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				allow = strings.Split(match[0][1], valuesDelim)
+				routeId = match[0][1]
 			}
 
-			match = denyRegEx.FindAllStringSubmatch(command[i], -1)
+			match = consumerIdRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				deny = strings.Split(match[0][1], valuesDelim)
+				consumerId = match[0][1]
 			}
 		}
-		if len(id) == 0 {
-			return errors.New("missing consumer id: option --id={id} required for this command")
+
+		var scopes int
+
+		for _, scopeId := range []string{serviceId, routeId, consumerId} {
+			if len(scopeId) > 0 {
+				scopes++
+			}
 		}
 
-		newKongIPRestrictionConfig := NewKongIPRestrictionPlugin(name, allow, deny)
+		if scopes > 1 {
+			return errors.New("options --service-id, --route-id and --consumer-id can't be used together")
+		}
 
-		return myKongServer.AddConsumerIPRestriction(id, newKongIPRestrictionConfig, options)
+		return myKongServer.ListPlugins(serviceId, routeId, consumerId, pagination, options)
 
-	case "consumer-rate-limiting":
+	case "consumer-plugin":
 		var id string
-		var name string
-		var second int
-		var minute int
-		var hour int
-		var errorCode int = 429
-		var errorMessage string = "API rate limit exceeded"
 
 		for i := 1; i < len(command); i++ {
 			match := idRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
 				id = match[0][1]
 			}
+		}
 
-			match = nameRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				name = match[0][1]
-			}
+		if len(id) == 0 {
+			return errors.New("missing consumer id: option --id={id} required for this command")
+		}
 
-			match = secondRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				second, _ = strconv.Atoi(match[0][1])
-			}
+		return myKongServer.ListPlugins("", "", id, pagination, options)
 
-			match = minutoRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				minute, _ = strconv.Atoi(match[0][1])
-			}
+	case "upstream":
+		return myKongServer.ListUpstreams(pagination, options)
 
-			match = hourRegEx.FindAllStringSubmatch(command[i], -1)
+	case "certificate":
+		return myKongServer.ListCertificates(pagination, options)
+
+	case "sni":
+		var certificateId string
+
+		for i := 1; i < len(command); i++ {
+			match := certificateIdRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				hour, _ = strconv.Atoi(match[0][1])
+				certificateId = match[0][1]
 			}
 		}
-		if len(id) == 0 {
-			return errors.New("missing consumer id: option --id={id} required for this command")
-		}
 
-		newKongRateLimitingPlugin := NewKongRateLimitingPlugin(name, int32(second), int32(minute), int32(hour), int32(errorCode), errorMessage)
+		return myKongServer.ListSNIs(certificateId, pagination, options)
 
-		return myKongServer.AddConsumerRateLimiting(id, newKongRateLimitingPlugin, options)
+	case "ca-certificate":
+		return myKongServer.ListCACertificates(pagination, options)
 
-	case "consumer-request-size-limiting":
+	case "consumer-group":
+		return myKongServer.ListConsumerGroups(pagination, options)
+
+	case "consumer-basic-auth", "consumer-key-auth", "consumer-jwt", "consumer-acl", "consumer-hmac-auth", "consumer-oauth2", "consumer-mtls-auth":
 		var id string
-		var name string
-		var allowedPayloadSize int
-		var sizeUnit string
-		var requireContentLength bool
 
+		//	without a consumer id all credentials of the type are listed
 		for i := 1; i < len(command); i++ {
 			match := idRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
 				id = match[0][1]
 			}
+		}
 
-			match = nameRegEx.FindAllStringSubmatch(command[i], -1)
+		switch command[0] {
+		case "consumer-basic-auth":
+			return myKongServer.ListConsumerBasicAuths(id, pagination, options)
+
+		case "consumer-key-auth":
+			return myKongServer.ListConsumerKeyAuths(id, pagination, options)
+
+		case "consumer-acl":
+			return myKongServer.ListConsumerACLs(id, pagination, options)
+
+		case "consumer-hmac-auth":
+			return myKongServer.ListConsumerHMACAuths(id, pagination, options)
+
+		case "consumer-oauth2":
+			return myKongServer.ListConsumerOAuth2s(id, pagination, options)
+
+		case "consumer-mtls-auth":
+			return myKongServer.ListConsumerMTLSAuths(id, pagination, options)
+		}
+
+		return myKongServer.ListConsumerJWTs(id, pagination, options)
+
+	case "upstream-target":
+		var upstreamId string
+
+		for i := 1; i < len(command); i++ {
+
+			match := upstreamIdRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				name = match[0][1]
+				upstreamId = match[0][1]
 			}
+		}
 
-			match = allowedPayloadSizeRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				allowedPayloadSize, _ = strconv.Atoi(match[0][1])
+		//	without an upstream id the targets of all upstreams are listed
+		return myKongServer.ListUpstreamTargets(upstreamId, pagination, options)
+	}
+
+	return errors.New("invalid entity for command list: " + command[0])
+}
+
+// get the pagination options for command list
+func paginationOptions(command []string) (*KongPagination, error) {
+	var pageSize int
+	var limit int
+	var offset string
+	var err error
+
+	for i := 0; i < len(command); i++ {
+		match := pageSizeRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			pageSize, err = strconv.Atoi(match[0][1])
+			if err != nil || pageSize < 1 || pageSize > maxPageSize {
+				return nil, errors.New("wrong value for option --page-size: " + match[0][1] + ": must be between 1 and " + strconv.Itoa(maxPageSize))
 			}
+		}
 
-			match = sizeUnitRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				sizeUnit = match[0][1]
+		match = limitRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			limit, err = strconv.Atoi(match[0][1])
+			if err != nil || limit < 1 {
+				return nil, errors.New("wrong value for option --limit: " + match[0][1] + ": must be a positive integer")
 			}
+		}
 
-			match = requireContentLengthRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				switch match[0][1] {
-				case "false":
-					requireContentLength = false
+		match = offsetRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			offset = match[0][1]
+		}
+	}
 
-				case "true":
-					requireContentLength = true
+	return NewKongPagination(pageSize, limit, offset), nil
+}
 
-				default:
-					return errors.New("wrong value for option --require-content-length: " + match[0][1])
-				}
-			}
+// command update
+func commandUpdate(myKongServer KongServer, command []string, options Options) error {
+
+	if len(command) == 0 {
+		return errors.New("missing entity for command update: available entities: service, route")
+	}
+
+	var id string
+
+	for i := 1; i < len(command); i++ {
+		match := idRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			id = match[0][1]
 		}
+	}
+
+	switch command[0] {
+	case "service":
 		if len(id) == 0 {
-			return errors.New("missing consumer id: option --id={id} required for this command")
+			return errors.New("missing service id: option --id={id} required for this command")
 		}
 
-		newKongRequestSizeLimitingPlugin := NewKongRequestSizeLimitingPlugin(name, int32(allowedPayloadSize), sizeUnit, requireContentLength)
+		updatedService, err := serviceOptions(command[1:])
+		if err != nil {
+			return err
+		}
 
-		return myKongServer.AddConsumerRequestSizeLimiting(id, newKongRequestSizeLimitingPlugin, options)
+		return myKongServer.UpdateService(id, updatedService, options)
 
-	case "consumer-syslog":
-		var id string
-		var name string
-		var logLevel string
+	case "route":
+		if len(id) == 0 {
+			return errors.New("missing route id: option --id={id} required for this command")
+		}
+
+		updatedRoute, err := routeOptions(command[1:])
+		if err != nil {
+			return err
+		}
+
+		return myKongServer.UpdateRoute(id, updatedRoute, options)
+
+	case "consumer":
+		if len(id) == 0 {
+			return errors.New("missing consumer id: option --id={id} required for this command")
+		}
+
+		const valuesDelim = ","
+		var customId string
+		var userName string
+		var tags []string
 
 		for i := 1; i < len(command); i++ {
-			match := idRegEx.FindAllStringSubmatch(command[i], -1)
+			match := customIdRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				id = match[0][1]
+				customId = match[0][1]
 			}
 
-			match = nameRegEx.FindAllStringSubmatch(command[i], -1)
+			match = userNameRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				name = match[0][1]
+				userName = match[0][1]
 			}
 
-			match = logLevelRegEx.FindAllStringSubmatch(command[i], -1)
+			match = tagsRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				logLevel = match[0][1]
+				tags = strings.Split(match[0][1], valuesDelim)
 			}
 		}
-		if len(id) == 0 {
-			return errors.New("missing consumer id: option --id={id} required for this command")
-		}
-
-		newKongSyslogPlugin := NewKongSyslogPlugin(name, logLevel)
+		updatedKongConsumer := NewKongConsumer(customId, userName, tags)
 
-		return myKongServer.AddConsumerSyslog(id, newKongSyslogPlugin, options)
+		return myKongServer.UpdateConsumer(id, updatedKongConsumer, options)
 
 	case "plugin":
-		var name string
+		if len(id) == 0 {
+			return errors.New("missing plugin id: option --id={id} required for this command")
+		}
+
 		var serviceId string
 		var routeId string
+		var consumerGroupId string
 		var enabled bool = true
+		var config []KongPluginConfig
+		var configFile string
 
 		for i := 1; i < len(command); i++ {
-			match := nameRegEx.FindAllStringSubmatch(command[i], -1)
+
+			match := serviceIdRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				name = match[0][1]
+				serviceId = match[0][1]
 			}
 
-			match = serviceIdRegEx.FindAllStringSubmatch(command[i], -1)
+			match = configRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				serviceId = match[0][1]
+				config = append(config, KongPluginConfig{key: match[0][1], value: match[0][2]})
+			}
+
+			match = configFileRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				configFile = match[0][1]
 			}
 
 			match = routeIdRegEx.FindAllStringSubmatch(command[i], -1)
@@ -611,6 +2429,11 @@ func commandAdd(myKongServer KongServer, command []string, options Options) erro
 				routeId = match[0][1]
 			}
 
+			match = consumerGroupIdRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				consumerGroupId = match[0][1]
+			}
+
 			match = enabledRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
 				switch match[0][1] {
@@ -625,110 +2448,181 @@ func commandAdd(myKongServer KongServer, command []string, options Options) erro
 				}
 			}
 		}
-		newKongPlugin := NewKongPlugin(name, serviceId, routeId, []KongPluginConfig{}, enabled)
+		pluginConfig, err := NewKongPluginConfig(configFile, config)
+		if err != nil {
+			return err
+		}
+		updatedKongPlugin := NewKongPlugin("", serviceId, routeId, pluginConfig, enabled)
+		updatedKongPlugin.setConsumerGroup(consumerGroupId)
 
-		return myKongServer.AddPlugin(newKongPlugin, options)
+		return myKongServer.UpdatePlugin(id, updatedKongPlugin, options)
 
 	case "upstream":
-		const valuesDelim = ","
-		var name string
-		var algorithm string
-		var tags []string
-
-		for i := 1; i < len(command); i++ {
-			match := nameRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				name = match[0][1]
-			}
-
-			match = algorithmRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				algorithm = match[0][1]
-			}
+		if len(id) == 0 {
+			return errors.New("missing upstream id: option --id={id} required for this command")
+		}
 
-			match = tagsRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				tags = strings.Split(match[0][1], valuesDelim)
-			}
+		updatedKongUpstream, err := upstreamOptions(command[1:])
+		if err != nil {
+			return err
 		}
-		newKongUpstream := NewKongUpstream(name, algorithm, tags)
 
-		return myKongServer.AddUpstream(newKongUpstream, options)
+		return myKongServer.UpdateUpstream(id, updatedKongUpstream, options)
 
 	case "upstream-target":
 		var upstreamId string
-		var target string
 
 		for i := 1; i < len(command); i++ {
 			match := upstreamIdRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
 				upstreamId = match[0][1]
 			}
-
-			match = targetRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				target = match[0][1]
-			}
 		}
 
 		if len(upstreamId) == 0 {
 			return errors.New("missing upstream id: option --upstream-id={id} required for this command")
 		}
-		newKongUpstreamTarget := NewKongUpstreamTarget(target)
 
-		return myKongServer.AddUpstreamTarget(upstreamId, newKongUpstreamTarget, options)
+		if len(id) == 0 {
+			return errors.New("missing upstream target id: option --id={id} required for this command")
+		}
+
+		updatedKongUpstreamTarget, err := upstreamTargetOptions(command[1:])
+		if err != nil {
+			return err
+		}
+
+		return myKongServer.UpdateUpstreamTarget(upstreamId, id, updatedKongUpstreamTarget, options)
+
+	case "certificate":
+		if len(id) == 0 {
+			return errors.New("missing certificate id: option --id={id} required for this command")
+		}
+
+		updatedKongCertificate, err := certificateOptions(command[1:])
+		if err != nil {
+			return err
+		}
+
+		return myKongServer.UpdateCertificate(id, updatedKongCertificate, options)
+
+	case "sni":
+		if len(id) == 0 {
+			return errors.New("missing sni id: option --id={id} required for this command")
+		}
+
+		return myKongServer.UpdateSNI(id, sniOptions(command[1:]), options)
+
+	case "ca-certificate":
+		if len(id) == 0 {
+			return errors.New("missing ca certificate id: option --id={id} required for this command")
+		}
+
+		updatedKongCACertificate, err := caCertificateOptions(command[1:])
+		if err != nil {
+			return err
+		}
+
+		return myKongServer.UpdateCACertificate(id, updatedKongCACertificate, options)
+
+	case "consumer-group":
+		if len(id) == 0 {
+			return errors.New("missing consumer group id: option --id={id} required for this command")
+		}
+
+		return myKongServer.UpdateConsumerGroup(id, consumerGroupOptions(command[1:]), options)
 	}
 
-	return errors.New("invalid entity for command add: " + command[0])
+	return errors.New("invalid entity for command update: " + command[0])
 }
 
-// command query
-func commandQuery(myKongServer KongServer, command []string, options Options) error {
+// command delete
+func commandDelete(myKongServer KongServer, command []string, options Options) error {
 
 	if len(command) == 0 {
-		return errors.New("missing entity for command query: available entities: service, route")
+		return errors.New("missing entity for command delete: available entities: service, route")
 	}
 
-	switch command[0] {
-	case "service":
-		var id string
+	var id string
 
-		for i := 1; i < len(command); i++ {
-			match := idRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				id = match[0][1]
-			}
+	for i := 1; i < len(command); i++ {
+		match := idRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			id = match[0][1]
 		}
+	}
 
+	switch command[0] {
+	case "service":
 		if len(id) == 0 {
 			return errors.New("missing service id: option --id={id} required for this command")
 		}
 
-		return myKongServer.QueryService(id, options)
+		return myKongServer.DeleteService(id, options)
 
 	case "route":
-		var id string
+		if len(id) == 0 {
+			return errors.New("missing route id: option --id={id} required for this command")
+		}
 
-		for i := 1; i < len(command); i++ {
-			match := idRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				id = match[0][1]
-			}
+		return myKongServer.DeleteRoute(id, options)
+
+	case "consumer":
+		if len(id) == 0 {
+			return errors.New("missing consumer id: option --id={id} required for this command")
+		}
+
+		return myKongServer.DeleteConsumer(id, options)
+
+	case "plugin":
+		if len(id) == 0 {
+			return errors.New("missing plugin id: option --id={id} required for this command")
 		}
 
+		return myKongServer.DeletePlugin(id, options)
+
+	case "upstream":
 		if len(id) == 0 {
-			return errors.New("missing route id: option --id={id} required for this command")
+			return errors.New("missing upstream id: option --id={id} required for this command")
 		}
 
-		return myKongServer.QueryRoute(id, options)
+		return myKongServer.DeleteUpstream(id, options)
 
-	case "consumer":
-		var id string
+	case "certificate":
+		if len(id) == 0 {
+			return errors.New("missing certificate id: option --id={id} required for this command")
+		}
+
+		return myKongServer.DeleteCertificate(id, options)
+
+	case "sni":
+		if len(id) == 0 {
+			return errors.New("missing sni id: option --id={id} required for this command")
+		}
+
+		return myKongServer.DeleteSNI(id, options)
+
+	case "ca-certificate":
+		if len(id) == 0 {
+			return errors.New("missing ca certificate id: option --id={id} required for this command")
+		}
+
+		return myKongServer.DeleteCACertificate(id, options)
+
+	case "consumer-group":
+		if len(id) == 0 {
+			return errors.New("missing consumer group id: option --id={id} required for this command")
+		}
+
+		return myKongServer.DeleteConsumerGroup(id, options)
+
+	case "consumer-basic-auth", "consumer-key-auth", "consumer-jwt", "consumer-acl", "consumer-hmac-auth", "consumer-oauth2", "consumer-mtls-auth":
+		var credentialId string
 
 		for i := 1; i < len(command); i++ {
-			match := idRegEx.FindAllStringSubmatch(command[i], -1)
+			match := credentialIdRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				id = match[0][1]
+				credentialId = match[0][1]
 			}
 		}
 
@@ -736,388 +2630,525 @@ func commandQuery(myKongServer KongServer, command []string, options Options) er
 			return errors.New("missing consumer id: option --id={id} required for this command")
 		}
 
-		return myKongServer.QueryConsumer(id, options)
+		if len(credentialId) == 0 {
+			return errors.New("missing credential id: option --credential-id={id} required for this command")
+		}
 
-	case "plugin":
-		var id string
+		switch command[0] {
+		case "consumer-basic-auth":
+			return myKongServer.DeleteConsumerBasicAuth(id, credentialId, options)
+
+		case "consumer-key-auth":
+			return myKongServer.DeleteConsumerKeyAuth(id, credentialId, options)
+
+		case "consumer-acl":
+			return myKongServer.DeleteConsumerACL(id, credentialId, options)
+
+		case "consumer-hmac-auth":
+			return myKongServer.DeleteConsumerHMACAuth(id, credentialId, options)
+
+		case "consumer-oauth2":
+			return myKongServer.DeleteConsumerOAuth2(id, credentialId, options)
+
+		case "consumer-mtls-auth":
+			return myKongServer.DeleteConsumerMTLSAuth(id, credentialId, options)
+		}
+
+		return myKongServer.DeleteConsumerJWT(id, credentialId, options)
+
+	case "upstream-target":
+		var upstreamId string
 
 		for i := 1; i < len(command); i++ {
-			match := idRegEx.FindAllStringSubmatch(command[i], -1)
+
+			match := upstreamIdRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				id = match[0][1]
+				upstreamId = match[0][1]
 			}
 		}
 
+		if len(upstreamId) == 0 {
+			return errors.New("missing upstream id: option --upstream-id={id} required for this command")
+		}
+
 		if len(id) == 0 {
-			return errors.New("missing plugin id: option --id={id} required for this command")
+			return errors.New("missing upstream target id: option --id={id} required for this command")
 		}
 
-		return myKongServer.QueryPlugin(id, options)
+		return myKongServer.DeleteUpstreamTarget(upstreamId, id, options)
+	}
 
-	case "upstream":
-		var id string
+	return errors.New("invalid entity for command delete: " + command[0])
+}
+
+// command dump
+func commandDump(myKongServer KongServer, command []string, options Options) error {
+
+	fileName := fileOption(command, "-o", "--output", outputRegEx)
+
+	return myKongServer.DumpState(fileName, options)
+}
+
+// command diff
+func commandDiff(myKongServer KongServer, command []string, options Options) error {
+
+	fileName := fileOption(command, "-f", "--file", fileRegEx)
+	if len(fileName) == 0 {
+		return errors.New("missing state file: option -f {file} required for this command")
+	}
+
+	desiredState, err := LoadKongState(fileName)
+	if err != nil {
+		return err
+	}
+
+	return myKongServer.DiffState(desiredState, options)
+}
+
+// command schema
+func commandSchema(myKongServer KongServer, command []string, options Options) error {
+
+	if len(command) == 0 {
+		return errors.New("missing entity for command schema: available entities: plugin")
+	}
+
+	switch command[0] {
+	case "plugin":
+		if len(command) < 2 {
+			return errors.New("missing plugin name: kconf schema plugin {name}")
+		}
+
+		return myKongServer.QueryPluginSchema(command[1], options)
+	}
+
+	return errors.New("invalid entity for command schema: " + command[0])
+}
+
+// command certificate
+func commandCertificate(myKongServer KongServer, command []string, options Options) error {
+
+	if len(command) == 0 {
+		return errors.New("missing certificate command: available commands: expiring, generate")
+	}
+
+	switch command[0] {
+	case "expiring":
+		var within string = "30d"
 
 		for i := 1; i < len(command); i++ {
-			match := idRegEx.FindAllStringSubmatch(command[i], -1)
+			match := withinRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				id = match[0][1]
+				within = match[0][1]
 			}
 		}
 
-		if len(id) == 0 {
-			return errors.New("missing upstream id: option --id={id} required for this command")
+		period, err := parsePeriod(within)
+		if err != nil {
+			return errors.New("wrong value for option --within: " + within + ": must be a number of days (e.g. 30d) or a duration (e.g. 12h)")
 		}
 
-		return myKongServer.QueryUpstream(id, options)
+		return myKongServer.ListExpiringCertificates(period, options)
 
-	case "upstream-target":
-		var upstreamId string
-		var id string
+	case "generate":
+		const valuesDelim = ","
+		var commonName string
+		var sans []string
+		var validity time.Duration = defaultCertificateValidity
+		var caCertFile, caKeyFile string
+		var tags []string
 
 		for i := 1; i < len(command); i++ {
+			match := cnRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				commonName = match[0][1]
+			}
 
-			match := upstreamIdRegEx.FindAllStringSubmatch(command[i], -1)
+			match = sanRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				upstreamId = match[0][1]
+				sans = append(sans, strings.Split(match[0][1], valuesDelim)...)
 			}
 
-			match = idRegEx.FindAllStringSubmatch(command[i], -1)
+			match = daysRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				id = match[0][1]
+				days, err := strconv.Atoi(match[0][1])
+				if err != nil || days < 1 {
+					return errors.New("wrong value for option --days: " + match[0][1] + ": must be a positive integer")
+				}
+				validity = time.Duration(days) * 24 * time.Hour
 			}
+
+			match = caCertFileRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				caCertFile = match[0][1]
+			}
+
+			match = caKeyFileRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				caKeyFile = match[0][1]
+			}
+
+			match = tagsRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				tags = strings.Split(match[0][1], valuesDelim)
+			}
+		}
+
+		if len(commonName) == 0 {
+			return errors.New("missing common name: option --cn={name} required for this command")
+		}
+
+		if (len(caCertFile) == 0) != (len(caKeyFile) == 0) {
+			return errors.New("missing CA: options --ca-cert-file={file} and --ca-key-file={file} must be used together")
+		}
+
+		caCert, err := readPEMFile(caCertFile, "CERTIFICATE")
+		if err != nil {
+			return err
 		}
 
-		if len(upstreamId) == 0 {
-			return errors.New("missing upstream id: option --upstream-id={id} required for this command")
+		caKey, err := readPEMFile(caKeyFile, "PRIVATE KEY")
+		if err != nil {
+			return err
 		}
 
-		if len(id) == 0 {
-			return errors.New("missing upstream target id: option --id={id} required for this command")
+		cert, key, err := generateCertificate(commonName, sans, validity, caCert, caKey)
+		if err != nil {
+			return err
 		}
 
-		return myKongServer.QueryUpstreamTarget(upstreamId, id, options)
+		return myKongServer.AddCertificate(NewKongCertificate(cert, key, "", "", tags), options)
 	}
 
-	return errors.New("invalid entity for command query: " + command[0])
+	return errors.New("invalid certificate command: " + command[0])
 }
 
-// command list
-func commandList(myKongServer KongServer, command []string, options Options) error {
+// command route
+func commandRoute(myKongServer KongServer, command []string, options Options) error {
 
 	if len(command) == 0 {
-		return errors.New("missing entity for command list: available entities: service, route")
+		return errors.New("missing route command: available commands: convert-to-expression")
 	}
 
 	switch command[0] {
-	case "service":
-		return myKongServer.ListServices(options)
-
-	case "route":
-		return myKongServer.ListRoutes(options)
-
-	case "consumer":
-		return myKongServer.ListConsumers(options)
-
-	case "plugin":
-		return myKongServer.ListPlugins(options)
-
-	case "upstream":
-		return myKongServer.ListUpstreams(options)
-
-	case "upstream-target":
-		var upstreamId string
+	case "convert-to-expression":
+		var id string
 
 		for i := 1; i < len(command); i++ {
-
-			match := upstreamIdRegEx.FindAllStringSubmatch(command[i], -1)
+			match := idRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				upstreamId = match[0][1]
+				id = match[0][1]
 			}
 		}
 
-		if len(upstreamId) == 0 {
-			return errors.New("missing upstream id: option --upstream-id={id} required for this command")
+		if len(id) == 0 {
+			return errors.New("missing route id: option --id={id} required for this command")
 		}
 
-		return myKongServer.ListUpstreamTargets(upstreamId, options)
+		return myKongServer.ConvertRouteToExpression(id, options)
 	}
 
-	return errors.New("invalid entity for command list: " + command[0])
+	return errors.New("invalid route command: " + command[0])
 }
 
-// command update
-func commandUpdate(myKongServer KongServer, command []string, options Options) error {
+// command consumer-group
+func commandConsumerGroup(myKongServer KongServer, command []string, options Options) error {
 
 	if len(command) == 0 {
-		return errors.New("missing entity for command update: available entities: service, route")
+		return errors.New("missing consumer group command: available commands: add-member, remove-member, members")
 	}
 
 	var id string
+	var consumerId string
 
 	for i := 1; i < len(command); i++ {
 		match := idRegEx.FindAllStringSubmatch(command[i], -1)
 		if len(match) == 1 {
 			id = match[0][1]
 		}
+
+		match = consumerIdRegEx.FindAllStringSubmatch(command[i], -1)
+		if len(match) == 1 {
+			consumerId = match[0][1]
+		}
 	}
 
 	switch command[0] {
-	case "service":
+	case "add-member", "remove-member":
 		if len(id) == 0 {
-			return errors.New("missing service id: option --id={id} required for this command")
+			return errors.New("missing consumer group id: option --id={id} required for this command")
 		}
 
-		var name string
-		var url string
-		var enabled bool = true
+		if len(consumerId) == 0 {
+			return errors.New("missing consumer id: option --consumer-id={id} required for this command")
+		}
 
-		for i := 1; i < len(command); i++ {
-			match := nameRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				name = match[0][1]
-			}
+		if command[0] == "add-member" {
+			return myKongServer.AddConsumerGroupMember(id, consumerId, options)
+		}
 
-			match = urlRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				url = match[0][1]
-			}
+		return myKongServer.RemoveConsumerGroupMember(id, consumerId, options)
 
-			match = enabledRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				switch match[0][1] {
-				case "false":
-					enabled = false
+	case "members":
+		if len(id) == 0 {
+			return errors.New("missing consumer group id: option --id={id} required for this command")
+		}
 
-				case "true":
-					enabled = true
+		pagination, err := paginationOptions(command[1:])
+		if err != nil {
+			return err
+		}
 
-				default:
-					return errors.New("wrong value for option --enabled: " + match[0][1])
-				}
+		return myKongServer.ListConsumerGroupMembers(id, pagination, options)
+	}
+
+	return errors.New("invalid consumer group command: " + command[0])
+}
+
+// command health
+func commandHealth(myKongServer KongServer, command []string, options Options) error {
+
+	if len(command) == 0 {
+		return errors.New("missing entity for command health: available entities: upstream")
+	}
+
+	switch command[0] {
+	case "upstream":
+		var id string
+
+		for i := 1; i < len(command); i++ {
+			match := idRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) == 1 {
+				id = match[0][1]
 			}
 		}
-		updatedService := NewKongService(name, url, enabled)
-
-		return myKongServer.UpdateService(id, updatedService, options)
 
-	case "route":
 		if len(id) == 0 {
-			return errors.New("missing route id: option --id={id} required for this command")
+			return errors.New("missing upstream id: option --id={id} required for this command")
 		}
 
-		const valuesDelim = ","
-		var name string
-		var protocols []string
-		var methods []string
-		var paths []string
-		var serviceId string
+		return myKongServer.UpstreamHealth(id, options)
+	}
+
+	return errors.New("invalid entity for command health: " + command[0])
+}
+
+// command upstream
+func commandUpstream(myKongServer KongServer, command []string, options Options) error {
+
+	if len(command) == 0 {
+		return errors.New("missing upstream command: available commands: shift")
+	}
+
+	switch command[0] {
+	case "shift":
+		var upstreamId string
+		var from, to string
+		var steps int = defaultTrafficShiftSteps
+		var interval time.Duration = defaultTrafficShiftInterval
 
 		for i := 1; i < len(command); i++ {
-			match := nameRegEx.FindAllStringSubmatch(command[i], -1)
+			match := upstreamIdRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				name = match[0][1]
+				upstreamId = match[0][1]
 			}
 
-			match = protocolsRegEx.FindAllStringSubmatch(command[i], -1)
+			match = fromRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				protocols = strings.Split(match[0][1], valuesDelim)
+				from = match[0][1]
 			}
 
-			match = methodsRegEx.FindAllStringSubmatch(command[i], -1)
+			match = toRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				methods = strings.Split(match[0][1], valuesDelim)
+				to = match[0][1]
 			}
 
-			match = pathsRegEx.FindAllStringSubmatch(command[i], -1)
+			match = stepsRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				paths = strings.Split(match[0][1], valuesDelim)
+				number, err := intOption("steps", match[0][1], 1, 100)
+				if err != nil {
+					return err
+				}
+				steps = *number
 			}
 
-			match = serviceIdRegEx.FindAllStringSubmatch(command[i], -1)
+			match = intervalRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				serviceId = match[0][1]
+				duration, err := time.ParseDuration(match[0][1])
+				if err != nil || duration < 0 {
+					return errors.New("wrong value for option --interval: " + match[0][1] + ": must be a duration (e.g. 2m)")
+				}
+				interval = duration
 			}
 		}
-		updatedRoute := NewKongRoute(name, protocols, methods, paths, serviceId)
 
-		return myKongServer.UpdateRoute(id, updatedRoute, options)
-
-	case "consumer":
-		if len(id) == 0 {
-			return errors.New("missing consumer id: option --id={id} required for this command")
+		if len(upstreamId) == 0 {
+			return errors.New("missing upstream id: option --upstream-id={id} required for this command")
 		}
 
-		const valuesDelim = ","
-		var customId string
-		var userName string
-		var tags []string
+		if len(from) == 0 || len(to) == 0 {
+			return errors.New("missing upstream targets: options --from={target} and --to={target} required for this command")
+		}
 
-		for i := 1; i < len(command); i++ {
-			match := customIdRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				customId = match[0][1]
-			}
+		if from == to {
+			return errors.New("options --from and --to must be different upstream targets")
+		}
 
-			match = userNameRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				userName = match[0][1]
-			}
+		return myKongServer.ShiftUpstreamTraffic(upstreamId, NewKongTrafficShift(from, to, steps, interval), options)
+	}
 
-			match = tagsRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				tags = strings.Split(match[0][1], valuesDelim)
-			}
-		}
-		updatedKongConsumer := NewKongConsumer(customId, userName, tags)
+	return errors.New("invalid upstream command: " + command[0])
+}
 
-		return myKongServer.UpdateConsumer(id, updatedKongConsumer, options)
+// command upstream-target
+func commandUpstreamTarget(myKongServer KongServer, command []string, options Options) error {
 
-	case "plugin":
-		if len(id) == 0 {
-			return errors.New("missing plugin id: option --id={id} required for this command")
-		}
+	if len(command) == 0 {
+		return errors.New("missing upstream target command: available commands: set-health")
+	}
 
-		var serviceId string
-		var routeId string
-		var enabled bool = true
+	switch command[0] {
+	case "set-health":
+		var upstreamId string
+		var id string
+		var health string
 
 		for i := 1; i < len(command); i++ {
-
-			match := serviceIdRegEx.FindAllStringSubmatch(command[i], -1)
+			match := upstreamIdRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				serviceId = match[0][1]
+				upstreamId = match[0][1]
 			}
 
-			match = routeIdRegEx.FindAllStringSubmatch(command[i], -1)
+			match = idRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				routeId = match[0][1]
+				id = match[0][1]
 			}
 
-			match = enabledRegEx.FindAllStringSubmatch(command[i], -1)
+			match = targetHealthRegEx.FindAllStringSubmatch(command[i], -1)
 			if len(match) == 1 {
-				switch match[0][1] {
-				case "false":
-					enabled = false
-
-				case "true":
-					enabled = true
-
-				default:
-					return errors.New("wrong value for option --enabled: " + match[0][1])
+				if len(health) > 0 && health != match[0][1] {
+					return errors.New("options --healthy and --unhealthy can't be used together")
 				}
+				health = match[0][1]
 			}
 		}
-		updatedKongPlugin := NewKongPlugin("", serviceId, routeId, nil, enabled)
 
-		return myKongServer.UpdatePlugin(id, updatedKongPlugin, options)
+		if len(upstreamId) == 0 {
+			return errors.New("missing upstream id: option --upstream-id={id} required for this command")
+		}
 
-	case "upstream":
 		if len(id) == 0 {
-			return errors.New("missing upstream id: option --id={id} required for this command")
+			return errors.New("missing upstream target id: option --id={id} required for this command")
 		}
 
-		const valuesDelim = ","
-		var name string
-		var algorithm string
-		var tags []string
-
-		for i := 1; i < len(command); i++ {
-			match := nameRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				name = match[0][1]
-			}
-
-			match = algorithmRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				algorithm = match[0][1]
-			}
-
-			match = tagsRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				tags = strings.Split(match[0][1], valuesDelim)
-			}
+		if len(health) == 0 {
+			return errors.New("missing target health: option --healthy or --unhealthy required for this command")
 		}
-		updatedKongUpstream := NewKongUpstream(name, algorithm, tags)
 
-		return myKongServer.UpdateUpstream(id, updatedKongUpstream, options)
+		return myKongServer.SetUpstreamTargetHealth(upstreamId, id, health == "healthy", options)
 	}
 
-	return errors.New("invalid entity for command update: " + command[0])
+	return errors.New("invalid upstream target command: " + command[0])
 }
 
-// command delete
-func commandDelete(myKongServer KongServer, command []string, options Options) error {
+// command context
+func commandContext(command []string, options Options) error {
 
 	if len(command) == 0 {
-		return errors.New("missing entity for command delete: available entities: service, route")
+		return errors.New("missing subcommand for command context: available subcommands: list, use, show, set")
 	}
 
-	var id string
+	configFile, err := kconfConfigFile()
+	if err != nil {
+		return err
+	}
 
-	for i := 1; i < len(command); i++ {
-		match := idRegEx.FindAllStringSubmatch(command[i], -1)
-		if len(match) == 1 {
-			id = match[0][1]
-		}
+	config, err := LoadKconfConfig(configFile)
+	if err != nil {
+		return err
 	}
 
 	switch command[0] {
-	case "service":
-		if len(id) == 0 {
-			return errors.New("missing service id: option --id={id} required for this command")
-		}
+	case "list":
+		return config.listContexts(options)
 
-		return myKongServer.DeleteService(id, options)
+	case "use":
+		if len(command) < 2 {
+			return errors.New("missing context name: kconf context use {name}")
+		}
 
-	case "route":
-		if len(id) == 0 {
-			return errors.New("missing route id: option --id={id} required for this command")
+		if config.context(command[1]) == nil {
+			return errors.New("unknown context: " + command[1])
 		}
+		config.CurrentContext = command[1]
 
-		return myKongServer.DeleteRoute(id, options)
+		err = config.save(configFile)
+		if err != nil {
+			return err
+		}
 
-	case "consumer":
-		if len(id) == 0 {
-			return errors.New("missing consumer id: option --id={id} required for this command")
+		if options.verbose {
+			fmt.Printf("current context: %s\n", command[1])
 		}
+		return nil
 
-		return myKongServer.DeleteConsumer(id, options)
+	case "show":
+		var context *KconfContext
 
-	case "plugin":
-		if len(id) == 0 {
-			return errors.New("missing plugin id: option --id={id} required for this command")
+		if len(command) < 2 {
+			context, err = config.selectContext("")
+			if err != nil {
+				return err
+			}
+			if context == nil {
+				return errors.New("no current context: use kconf context use {name}")
+			}
+		} else {
+			context = config.context(command[1])
+			if context == nil {
+				return errors.New("unknown context: " + command[1])
+			}
 		}
 
-		return myKongServer.DeletePlugin(id, options)
+		return context.show(options)
 
-	case "upstream":
-		if len(id) == 0 {
-			return errors.New("missing upstream id: option --id={id} required for this command")
+	case "set":
+		if len(command) < 2 {
+			return errors.New("missing context name: kconf context set {name} --{attribute}={value}")
 		}
 
-		return myKongServer.DeleteUpstream(id, options)
+		context := config.context(command[1])
+		if context == nil {
+			config.Contexts = append(config.Contexts, KconfContext{Name: command[1]})
+			context = &config.Contexts[len(config.Contexts)-1]
 
-	case "upstream-target":
-		var upstreamId string
+			//	the first context becomes the current one
+			if len(config.CurrentContext) == 0 {
+				config.CurrentContext = command[1]
+			}
+		}
 
-		for i := 1; i < len(command); i++ {
+		for i := 2; i < len(command); i++ {
+			match := contextOptionRegEx.FindAllStringSubmatch(command[i], -1)
+			if len(match) != 1 {
+				return errors.New("invalid option for command context set: " + command[i])
+			}
 
-			match := upstreamIdRegEx.FindAllStringSubmatch(command[i], -1)
-			if len(match) == 1 {
-				upstreamId = match[0][1]
+			err = context.set(match[0][1], match[0][2])
+			if err != nil {
+				return err
 			}
 		}
 
-		if len(upstreamId) == 0 {
-			return errors.New("missing upstream id: option --upstream-id={id} required for this command")
+		err = config.save(configFile)
+		if err != nil {
+			return err
 		}
 
-		if len(id) == 0 {
-			return errors.New("missing upstream target id: option --id={id} required for this command")
+		if options.verbose {
+			fmt.Printf("context saved: %s\n", command[1])
 		}
-
-		return myKongServer.DeleteUpstreamTarget(upstreamId, id, options)
+		return nil
 	}
 
-	return errors.New("invalid entity for command delete: " + command[0])
+	return errors.New("invalid subcommand for command context: " + command[0])
 }
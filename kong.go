@@ -7,10 +7,14 @@
 package main
 
 import (
-	"errors"
+	"bytes"
+	"encoding/json"
 	"fmt"
 	"io"
 	"net/http"
+	"net/url"
+	"strconv"
+	"time"
 )
 
 // Kong server interface
@@ -20,25 +24,53 @@ type KongServer interface {
 
 	AddService(newKongService *KongService, options Options) error
 	QueryService(id string, options Options) error
-	ListServices(options Options) error
+	ListServices(pagination *KongPagination, options Options) error
 	UpdateService(id string, updatedKongService *KongService, options Options) error
 	DeleteService(id string, options Options) error
 
 	AddRoute(newKongRoute *KongRoute, options Options) error
 	QueryRoute(id string, options Options) error
-	ListRoutes(options Options) error
+	ListRoutes(serviceId string, pagination *KongPagination, options Options) error
 	UpdateRoute(id string, updatedKongRoute *KongRoute, options Options) error
 	DeleteRoute(id string, options Options) error
+	ConvertRouteToExpression(id string, options Options) error
 
 	AddConsumer(newKongConsumer *KongConsumer, options Options) error
 	QueryConsumer(id string, options Options) error
-	ListConsumers(options Options) error
+	ListConsumers(pagination *KongPagination, options Options) error
 	UpdateConsumer(id string, updatedKongConsumer *KongConsumer, options Options) error
 	DeleteConsumer(id string, options Options) error
 
+	AddConsumerGroup(newKongConsumerGroup *KongConsumerGroup, options Options) error
+	QueryConsumerGroup(id string, options Options) error
+	ListConsumerGroups(pagination *KongPagination, options Options) error
+	UpdateConsumerGroup(id string, updatedKongConsumerGroup *KongConsumerGroup, options Options) error
+	DeleteConsumerGroup(id string, options Options) error
+	AddConsumerGroupMember(id string, consumerId string, options Options) error
+	RemoveConsumerGroupMember(id string, consumerId string, options Options) error
+	ListConsumerGroupMembers(id string, pagination *KongPagination, options Options) error
+
 	AddConsumerBasicAuth(id string, newKongBasicAuthConfig *KongBasicAuthConfig, options Options) error
 	AddConsumerKeyAuth(id string, newKongKeyAuthConfig *KongKeyAuthConfig, options Options) error
 	AddConsumerJWT(id string, newKongJWTConfig *KongJWTConfig, options Options) error
+	ListConsumerBasicAuths(id string, pagination *KongPagination, options Options) error
+	ListConsumerKeyAuths(id string, pagination *KongPagination, options Options) error
+	ListConsumerJWTs(id string, pagination *KongPagination, options Options) error
+	DeleteConsumerBasicAuth(id string, credentialId string, options Options) error
+	DeleteConsumerKeyAuth(id string, credentialId string, options Options) error
+	DeleteConsumerJWT(id string, credentialId string, options Options) error
+	AddConsumerACL(id string, newKongACLConfig *KongACLConfig, options Options) error
+	ListConsumerACLs(id string, pagination *KongPagination, options Options) error
+	DeleteConsumerACL(id string, credentialId string, options Options) error
+	AddConsumerHMACAuth(id string, newKongHMACAuthConfig *KongHMACAuthConfig, options Options) error
+	ListConsumerHMACAuths(id string, pagination *KongPagination, options Options) error
+	DeleteConsumerHMACAuth(id string, credentialId string, options Options) error
+	AddConsumerOAuth2(id string, newKongOAuth2Config *KongOAuth2Config, options Options) error
+	ListConsumerOAuth2s(id string, pagination *KongPagination, options Options) error
+	DeleteConsumerOAuth2(id string, credentialId string, options Options) error
+	AddConsumerMTLSAuth(id string, newKongMTLSAuthConfig *KongMTLSAuthConfig, options Options) error
+	ListConsumerMTLSAuths(id string, pagination *KongPagination, options Options) error
+	DeleteConsumerMTLSAuth(id string, credentialId string, options Options) error
 	AddConsumerIPRestriction(id string, newKongIPRestrictionConfig *KongIPRestrictionPlugin, options Options) error
 	AddConsumerRateLimiting(id string, newKongRateLimitingPlugin *KongRateLimitingPlugin, options Options) error
 	AddConsumerRequestSizeLimiting(id string, newKongRequestSizeLimitingPlugin *KongRequestSizeLimitingPlugin, options Options) error
@@ -46,42 +78,167 @@ type KongServer interface {
 
 	AddPlugin(newKongPlugin *KongPlugin, options Options) error
 	QueryPlugin(id string, options Options) error
-	ListPlugins(options Options) error
+	ListPlugins(serviceId string, routeId string, consumerId string, pagination *KongPagination, options Options) error
 	UpdatePlugin(id string, updatedKongPlugin *KongPlugin, options Options) error
 	DeletePlugin(id string, options Options) error
+	QueryPluginSchema(name string, options Options) error
 
 	AddUpstream(newKongUpstream *KongUpstream, options Options) error
 	QueryUpstream(id string, options Options) error
-	ListUpstreams(options Options) error
+	ListUpstreams(pagination *KongPagination, options Options) error
 	UpdateUpstream(id string, updatedKongUpstream *KongUpstream, options Options) error
 	DeleteUpstream(id string, options Options) error
+	UpstreamHealth(id string, options Options) error
+	ShiftUpstreamTraffic(upstreamId string, shift *KongTrafficShift, options Options) error
 
 	AddUpstreamTarget(upstreamId string, newKongUpstreamTarget *KongUpstreamTarget, options Options) error
 	QueryUpstreamTarget(upstreamId string, id string, options Options) error
-	ListUpstreamTargets(upstreamId string, options Options) error
+	ListUpstreamTargets(upstreamId string, pagination *KongPagination, options Options) error
+	UpdateUpstreamTarget(upstreamId string, id string, updatedKongUpstreamTarget *KongUpstreamTarget, options Options) error
+	SetUpstreamTargetHealth(upstreamId string, id string, healthy bool, options Options) error
 	DeleteUpstreamTarget(upstreamId string, id string, options Options) error
+
+	AddCertificate(newKongCertificate *KongCertificate, options Options) error
+	QueryCertificate(id string, options Options) error
+	ListCertificates(pagination *KongPagination, options Options) error
+	UpdateCertificate(id string, updatedKongCertificate *KongCertificate, options Options) error
+	DeleteCertificate(id string, options Options) error
+	ListExpiringCertificates(within time.Duration, options Options) error
+
+	AddSNI(newKongSNI *KongSNI, options Options) error
+	QuerySNI(id string, options Options) error
+	ListSNIs(certificateId string, pagination *KongPagination, options Options) error
+	UpdateSNI(id string, updatedKongSNI *KongSNI, options Options) error
+	DeleteSNI(id string, options Options) error
+
+	AddCACertificate(newKongCACertificate *KongCACertificate, options Options) error
+	QueryCACertificate(id string, options Options) error
+	ListCACertificates(pagination *KongPagination, options Options) error
+	UpdateCACertificate(id string, updatedKongCACertificate *KongCACertificate, options Options) error
+	DeleteCACertificate(id string, options Options) error
+
+	ApplyState(desiredState *KongState, options Options) error
+	DumpState(fileName string, options Options) error
+	DiffState(desiredState *KongState, options Options) error
 }
 
 // Kong server attributes
 type KongServerDomain struct {
-	address string
-	port    int
+	address    string
+	port       int
+	connection *KongConnection
+	client     *http.Client
+	version    *string
+}
+
+// Kong Admin API connection attributes
+type KongConnection struct {
+	adminToken string
+	headers    http.Header
+	userName   string
+	password   string
+	tls        *KongTLS
+	workspace  string
 }
 
+// create a new Kong Admin API connection configuration: a nil TLS configuration means plain HTTP
+func NewKongConnection(adminToken string, headers http.Header, userName string, password string, tls *KongTLS, workspace string) *KongConnection {
+
+	return &KongConnection{
+		adminToken: adminToken,
+		headers:    headers,
+		userName:   userName,
+		password:   password,
+		tls:        tls,
+		workspace:  workspace,
+	}
+}
+
+const (
+	adminTokenHeader string = "Kong-Admin-Token"
+)
+
 // create a new Kong server configuration
 func NewKongServer(address string, port int) KongServer {
 
 	return &KongServerDomain{
-		address: address,
-		port:    port,
+		address:    address,
+		port:       port,
+		connection: &KongConnection{},
+		client:     &http.Client{},
+	}
+}
+
+// create a new Kong server configuration with Admin API connection attributes
+func NewKongServerConnection(address string, port int, connection *KongConnection) (KongServer, error) {
+
+	if connection == nil {
+		connection = &KongConnection{}
+	}
+
+	client, err := newKongHTTPClient(connection.tls)
+	if err != nil {
+		return nil, err
+	}
+
+	return &KongServerDomain{
+		address:    address,
+		port:       port,
+		connection: connection,
+		client:     client,
+	}, nil
+}
+
+// send a request to Kong Admin API with the connection authentication and custom headers
+func (ks *KongServerDomain) sendRequest(method string, requestURL string, payload []byte) (*http.Response, error) {
+
+	req, err := http.NewRequest(method, requestURL, bytes.NewBuffer(payload))
+	if err != nil {
+		return nil, err
 	}
+	if payload != nil {
+		req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	}
+
+	if ks.connection != nil {
+		for name, values := range ks.connection.headers {
+			for _, value := range values {
+				req.Header.Add(name, value)
+			}
+		}
+
+		if len(ks.connection.adminToken) > 0 {
+			req.Header.Set(adminTokenHeader, ks.connection.adminToken)
+		}
+
+		if len(ks.connection.userName) > 0 {
+			req.SetBasicAuth(ks.connection.userName, ks.connection.password)
+		}
+	}
+
+	return ks.client.Do(req)
 }
 
+// the Kong Admin API URL for workspace scoped entities
 func (ks *KongServerDomain) ServerURL() string {
+
+	if ks.connection != nil && len(ks.connection.workspace) > 0 {
+		return ks.adminURL() + "/" + url.PathEscape(ks.connection.workspace)
+	}
+
+	return ks.adminURL()
+}
+
+// the Kong Admin API URL
+func (ks *KongServerDomain) adminURL() string {
 	var kongUrl string = ks.address
 
 	if ks.port != 0 {
-		kongUrl = fmt.Sprintf("http://%s:%d", ks.address, ks.port)
+		scheme := "http"
+		if ks.connection != nil && ks.connection.tls != nil {
+			scheme = "https"
+		}
+		kongUrl = fmt.Sprintf("%s://%s:%d", scheme, ks.address, ks.port)
 	}
 
 	return kongUrl
@@ -90,16 +247,16 @@ func (ks *KongServerDomain) ServerURL() string {
 // check Kong status
 func (ks *KongServerDomain) CheckStatus(options Options) error {
 
-	var checkStatusURL string = ks.ServerURL()
+	var checkStatusURL string = ks.adminURL()
 
 	//	send a request to Kong to check it's status
-	resp, err := http.Get(checkStatusURL)
+	resp, err := ks.sendRequest("GET", checkStatusURL, nil)
 	if err != nil {
 		return err
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.New("error sending check status command to Kong: " + resp.Status)
+		return newKongAPIError("error sending check status command to Kong", resp)
 	}
 
 	if options.jsonOutput {
@@ -117,3 +274,145 @@ func (ks *KongServerDomain) CheckStatus(options Options) error {
 
 	return nil
 }
+
+// kong collection page payload
+type KongCollectionPage struct {
+	Data   []json.RawMessage `json:"data"`
+	Next   string            `json:"next,omitempty"`
+	Offset string            `json:"offset,omitempty"`
+	NodeId string            `json:"node_id,omitempty"`
+}
+
+// kong list pagination attributes
+type KongPagination struct {
+	pageSize int
+	limit    int
+	offset   string
+}
+
+// create a new Kong list pagination
+func NewKongPagination(pageSize int, limit int, offset string) *KongPagination {
+
+	return &KongPagination{
+		pageSize: pageSize,
+		limit:    limit,
+		offset:   offset,
+	}
+}
+
+const (
+	maxPageSize int = 1000
+)
+
+// fetch entities from a Kong collection following the next page cursor until exhausted or the limit is reached
+func (ks *KongServerDomain) fetchCollection(resourcePath string, pagination *KongPagination, failMessage string) (*KongCollectionPage, string, error) {
+
+	var (
+		collection KongCollectionPage
+		status     string
+		pageSize   int
+		limit      int
+		offset     string
+	)
+
+	if pagination != nil {
+		pageSize = pagination.pageSize
+		limit = pagination.limit
+		offset = pagination.offset
+	}
+
+	for {
+		query := url.Values{}
+
+		//	never ask for more than the limit, so the next cursor points to the first entity not listed
+		size := pageSize
+		if limit > 0 {
+			remaining := limit - len(collection.Data)
+			if size == 0 || remaining < size {
+				size = remaining
+			}
+		}
+		if size > maxPageSize {
+			size = maxPageSize
+		}
+		if size > 0 {
+			query.Set("size", strconv.Itoa(size))
+		}
+		if len(offset) > 0 {
+			query.Set("offset", offset)
+		}
+
+		pageURL := fmt.Sprintf("%s/%s", ks.ServerURL(), resourcePath)
+		if len(query) > 0 {
+			pageURL += "?" + query.Encode()
+		}
+
+		resp, err := ks.sendRequest("GET", pageURL, nil)
+		if err != nil {
+			return nil, "", err
+		}
+
+		//	the error is built before reading the body, so Kong's error payload is kept
+		if resp.StatusCode != http.StatusOK {
+			apiError := newKongAPIError(failMessage, resp)
+			resp.Body.Close()
+
+			return nil, "", apiError
+		}
+		status = resp.Status
+
+		respPayload, err := io.ReadAll(resp.Body)
+		resp.Body.Close()
+		if err != nil {
+			return nil, "", err
+		}
+
+		var page KongCollectionPage
+
+		err = json.Unmarshal(respPayload, &page)
+		if err != nil {
+			return nil, "", err
+		}
+
+		collection.Data = append(collection.Data, page.Data...)
+		if len(collection.NodeId) == 0 {
+			collection.NodeId = page.NodeId
+		}
+
+		offset = page.nextOffset()
+		if len(offset) == 0 {
+			break
+		}
+
+		if limit > 0 && len(collection.Data) >= limit {
+			collection.Next = page.Next
+			collection.Offset = offset
+			break
+		}
+	}
+
+	return &collection, status, nil
+}
+
+// get the cursor for the next page: Kong returns it both as offset and inside the next URL
+func (page *KongCollectionPage) nextOffset() string {
+
+	if len(page.Offset) > 0 {
+		return page.Offset
+	}
+
+	nextURL, err := url.Parse(page.Next)
+	if err != nil {
+		return ""
+	}
+
+	return nextURL.Query().Get("offset")
+}
+
+// show the offset to continue a list stopped by the limit
+func printNextOffset(collection *KongCollectionPage) {
+
+	if len(collection.Offset) > 0 {
+		fmt.Printf("next offset: %s\n", collection.Offset)
+	}
+}
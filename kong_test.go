@@ -68,3 +68,111 @@ func Test_CheckStatus(t *testing.T) {
 		}
 	})
 }
+
+// Test_fetchCollection unit tests for fetchCollection() method
+func Test_fetchCollection(t *testing.T) {
+
+	//	mock for Kong Admin with three pages of services
+	var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+
+		switch r.URL.Query().Get("offset") {
+		case "":
+			w.Write([]byte(`{ "data": [ { "id": "1" } ], "next": "/services?offset=page2", "offset": "page2" }`))
+		case "page2":
+			w.Write([]byte(`{ "data": [ { "id": "2" } ], "next": "/services?offset=page3", "offset": "page3" }`))
+		default:
+			w.Write([]byte(`{ "data": [ { "id": "3" } ], "next": null }`))
+		}
+	}))
+	defer mockKongAdmin.Close()
+
+	t.Run(">>> fetchCollection: scenario 1 - follow next cursor until the last page", func(t *testing.T) {
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0).(*KongServerDomain)
+
+		got, _, err := kongServer.fetchCollection(servicesResource, nil, "fail")
+		if err != nil {
+			t.Fatalf("failed fetching collection: success expected: result: %s", err.Error())
+		}
+
+		if len(got.Data) != 3 || len(got.Offset) != 0 {
+			t.Errorf("failed fetching collection: 3 entities and no next offset expected: result: %d %s", len(got.Data), got.Offset)
+		}
+	})
+
+	t.Run(">>> fetchCollection: scenario 2 - stop at the limit with next offset", func(t *testing.T) {
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0).(*KongServerDomain)
+
+		got, _, err := kongServer.fetchCollection(servicesResource, NewKongPagination(1, 2, ""), "fail")
+		if err != nil {
+			t.Fatalf("failed fetching collection: success expected: result: %s", err.Error())
+		}
+
+		if len(got.Data) != 2 || got.Offset != "page3" {
+			t.Errorf("failed fetching collection: 2 entities and next offset page3 expected: result: %d %s", len(got.Data), got.Offset)
+		}
+	})
+}
+
+// Test_sendRequest unit tests for sendRequest() method
+func Test_sendRequest(t *testing.T) {
+
+	t.Run(">>> sendRequest: scenario 1 - admin token, basic auth and custom headers", func(t *testing.T) {
+
+		var requests []*http.Request
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requests = append(requests, r)
+			w.WriteHeader(http.StatusOK)
+		}))
+		defer mockKongAdmin.Close()
+
+		headers := make(http.Header)
+		headers.Add("apikey", "admin-key")
+		headers.Add("X-Request-Source", "kconf")
+
+		//	connect to mock server
+		kongServer, err := NewKongServerConnection(mockKongAdmin.URL, 0, NewKongConnection("rbac-token", headers, "admin", "secret", nil, ""))
+		if err != nil {
+			t.Fatalf("fail connectring to mock Kong Admin: %s", err.Error())
+		}
+
+		kongServer.CheckStatus(Options{})
+		kongServer.DeleteService("1234", Options{})
+
+		if len(requests) != 2 {
+			t.Fatalf("failed sending requests: 2 requests expected: result: %d", len(requests))
+		}
+
+		for _, r := range requests {
+			userName, password, ok := r.BasicAuth()
+
+			if r.Header.Get("Kong-Admin-Token") != "rbac-token" || r.Header.Get("apikey") != "admin-key" || r.Header.Get("X-Request-Source") != "kconf" ||
+				!ok || userName != "admin" || password != "secret" {
+				t.Errorf("failed sending request: authentication headers expected: %s %s: %v", r.Method, r.URL.Path, r.Header)
+			}
+		}
+	})
+}
+
+// Test_ServerURL unit tests for ServerURL() method
+func Test_ServerURL(t *testing.T) {
+
+	t.Run(">>> ServerURL: scenario 1 - workspace scoped URL", func(t *testing.T) {
+
+		kongServer, err := NewKongServerConnection("kong.local", 8001, NewKongConnection("", nil, "", "", nil, "payments"))
+		if err != nil {
+			t.Fatalf("fail creating Kong server: %s", err.Error())
+		}
+
+		want := "http://kong.local:8001/payments"
+		if want != kongServer.ServerURL() {
+			t.Errorf("failed building server URL: expected: %s result: %s", want, kongServer.ServerURL())
+		}
+	})
+}
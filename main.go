@@ -7,9 +7,12 @@
 package main
 
 import (
+	"errors"
 	"flag"
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
 )
 
 const (
@@ -18,19 +21,70 @@ const (
 
 // execution options
 type Options struct {
-	jsonOutput bool
-	verbose    bool
+	jsonOutput  bool
+	verbose     bool
+	colorOutput bool
+}
+
+// repeatable -header "Name: value" option
+type headerOptions struct {
+	headers http.Header
+}
+
+func (h *headerOptions) String() string {
+	var headers []string
+
+	for name, values := range h.headers {
+		for _, value := range values {
+			headers = append(headers, name+": "+value)
+		}
+	}
+
+	return strings.Join(headers, ", ")
+}
+
+func (h *headerOptions) Set(header string) error {
+	name, value, found := strings.Cut(header, ":")
+	if !found || len(strings.TrimSpace(name)) == 0 {
+		return errors.New("header must be in the format \"Name: value\"")
+	}
+
+	if h.headers == nil {
+		h.headers = make(http.Header)
+	}
+	h.headers.Add(strings.TrimSpace(name), strings.TrimSpace(value))
+
+	return nil
 }
 
 // main entry point for kconf
 func main() {
 	var (
 		version bool
+		noColor bool
 
 		//	Kong server configuration
 		kongAddress string
 		kongPort    int
 
+		//	Kong Admin API authentication
+		adminToken        string
+		headers           headerOptions
+		basicAuthUser     string
+		basicAuthPassword string
+
+		//	Kong Admin API TLS
+		useTLS             bool
+		caCert             string
+		clientCert         string
+		clientKey          string
+		tlsServerName      string
+		insecureSkipVerify bool
+
+		//	named context and Kong workspace
+		contextName string
+		workspace   string
+
 		options Options
 	)
 
@@ -39,8 +93,21 @@ func main() {
 
 	flag.StringVar(&kongAddress, "kong-address", "localhost", "Kong configuration address")
 	flag.IntVar(&kongPort, "port", 8001, "Kong configuration port")
+	flag.StringVar(&adminToken, "admin-token", "", "Kong Admin API RBAC token (sent as Kong-Admin-Token header)")
+	flag.Var(&headers, "header", "custom header \"Name: value\" sent to Kong Admin API (repeatable)")
+	flag.StringVar(&basicAuthUser, "basic-auth-user", "", "user name for Kong Admin API basic authentication")
+	flag.StringVar(&basicAuthPassword, "basic-auth-password", "", "password for Kong Admin API basic authentication")
+	flag.BoolVar(&useTLS, "tls", false, "use HTTPS to connect to Kong Admin API")
+	flag.StringVar(&caCert, "ca-cert", "", "CA certificate file (PEM) to verify Kong Admin API certificate")
+	flag.StringVar(&clientCert, "client-cert", "", "client certificate file (PEM) for mutual TLS")
+	flag.StringVar(&clientKey, "client-key", "", "client key file (PEM) for mutual TLS")
+	flag.StringVar(&tlsServerName, "tls-server-name", "", "server name used to verify Kong Admin API certificate")
+	flag.BoolVar(&insecureSkipVerify, "insecure-skip-verify", false, "skip verification of Kong Admin API certificate")
+	flag.StringVar(&contextName, "context", "", "named context from kconf config file (overrides "+contextEnvironmentVariable+")")
+	flag.StringVar(&workspace, "workspace", "", "Kong workspace")
 	flag.BoolVar(&options.jsonOutput, "json-output", false, "use json output for every command")
 	flag.BoolVar(&options.verbose, "verbose", false, "run in verbose mode")
+	flag.BoolVar(&noColor, "no-color", false, "disable colored output")
 
 	flag.Parse()
 
@@ -50,16 +117,110 @@ func main() {
 		return
 	}
 
+	//	colors are only used when the output is a terminal
+	if !noColor {
+		stdoutInfo, err := os.Stdout.Stat()
+		if err == nil && stdoutInfo.Mode()&os.ModeCharDevice != 0 {
+			options.colorOutput = true
+		}
+	}
+
+	//	context commands only handle the config file
+	if flag.Arg(0) == "context" {
+		exitOnError(kconf(nil, flag.Args(), options), options)
+		return
+	}
+
+	//	context attributes are used for the options not set in the command line
+	context, err := loadContext(contextName)
+	exitOnError(err, options)
+
+	if context != nil {
+		setOptions := make(map[string]bool)
+		flag.Visit(func(option *flag.Flag) {
+			setOptions[option.Name] = true
+		})
+
+		if !setOptions["kong-address"] && len(context.KongAddress) > 0 {
+			kongAddress = context.KongAddress
+		}
+		if !setOptions["port"] && context.Port != 0 {
+			kongPort = context.Port
+		}
+		if !setOptions["admin-token"] {
+			adminToken = context.AdminToken
+		}
+		if !setOptions["header"] {
+			for _, header := range context.Headers {
+				err = headers.Set(header)
+				exitOnError(err, options)
+			}
+		}
+		if !setOptions["basic-auth-user"] {
+			basicAuthUser = context.BasicAuthUser
+		}
+		if !setOptions["basic-auth-password"] {
+			basicAuthPassword = context.BasicAuthPassword
+		}
+		if !setOptions["tls"] {
+			useTLS = context.TLS
+		}
+		if !setOptions["ca-cert"] {
+			caCert = context.CACert
+		}
+		if !setOptions["client-cert"] {
+			clientCert = context.ClientCert
+		}
+		if !setOptions["client-key"] {
+			clientKey = context.ClientKey
+		}
+		if !setOptions["tls-server-name"] {
+			tlsServerName = context.TLSServerName
+		}
+		if !setOptions["insecure-skip-verify"] {
+			insecureSkipVerify = context.InsecureSkipVerify
+		}
+		if !setOptions["workspace"] {
+			workspace = context.Workspace
+		}
+		if !setOptions["json-output"] {
+			options.jsonOutput = context.Output == contextOutputJSON
+		}
+	}
+
 	//	connect and send command
-	kongServer := NewKongServer(kongAddress, kongPort)
+	var kongTLS *KongTLS
+
+	//	any TLS option implies a TLS connection
+	if useTLS || len(caCert) > 0 || len(clientCert) > 0 || len(clientKey) > 0 || len(tlsServerName) > 0 || insecureSkipVerify {
+		kongTLS = NewKongTLS(caCert, clientCert, clientKey, tlsServerName, insecureSkipVerify)
+	}
+
+	kongServer, err := NewKongServerConnection(kongAddress, kongPort, NewKongConnection(adminToken, headers.headers, basicAuthUser, basicAuthPassword, kongTLS, workspace))
+	exitOnError(err, options)
 	if kongServer == nil {
 		fmt.Fprintf(os.Stderr, "[error] fail attempting to alocate Kong server\n")
 		os.Exit(-1)
 	}
 
-	err := kconf(kongServer, flag.Args(), options)
+	exitOnError(kconf(kongServer, flag.Args(), options), options)
+}
+
+// report an error and exit
+func exitOnError(err error, options Options) {
+
+	if errors.Is(err, errStateDrift) {
+		os.Exit(driftExitStatus)
+	}
+	if errors.Is(err, errCertificatesExpiring) {
+		os.Exit(expiringExitStatus)
+	}
 	if err != nil {
-		fmt.Fprintf(os.Stderr, "[error] %s\n", err.Error())
+		if options.jsonOutput {
+			printJSONError(err)
+		} else {
+			fmt.Fprintf(os.Stderr, "[error] %s\n", err.Error())
+		}
 		os.Exit(-1)
 	}
 }
@@ -7,12 +7,17 @@
 package main
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
 // kong plugin config attributes
@@ -23,19 +28,20 @@ type KongPluginConfig struct {
 
 // kong plugin attributes
 type KongPlugin struct {
-	instanceName string
-	name         string
-	serviceId    string
-	routeId      string
-	consumer     string
-	config       []KongPluginConfig
-	protocols    []string
-	enabled      bool
-	tags         []string
+	instanceName    string
+	name            string
+	serviceId       string
+	routeId         string
+	consumer        string
+	consumerGroupId string
+	config          map[string]interface{}
+	protocols       []string
+	enabled         bool
+	tags            []string
 }
 
 // create a new Kong plugin
-func NewKongPlugin(name string, serviceId string, routeId string, config []KongPluginConfig, enabled bool) *KongPlugin {
+func NewKongPlugin(name string, serviceId string, routeId string, config map[string]interface{}, enabled bool) *KongPlugin {
 
 	return &KongPlugin{
 		name:      name,
@@ -46,6 +52,153 @@ func NewKongPlugin(name string, serviceId string, routeId string, config []KongP
 	}
 }
 
+// scope the plugin to a consumer group
+func (plugin *KongPlugin) setConsumerGroup(consumerGroupId string) {
+
+	plugin.consumerGroupId = consumerGroupId
+}
+
+// create a Kong plugin config object from a config file and a list of config attributes
+func NewKongPluginConfig(configFile string, config []KongPluginConfig) (map[string]interface{}, error) {
+
+	var pluginConfig map[string]interface{}
+
+	if len(configFile) > 0 {
+		content, err := os.ReadFile(configFile)
+		if err != nil {
+			return nil, err
+		}
+
+		switch strings.ToLower(filepath.Ext(configFile)) {
+		case ".yaml", ".yml":
+			err = yaml.Unmarshal(content, &pluginConfig)
+
+		default:
+			err = json.Unmarshal(content, &pluginConfig)
+		}
+		if err != nil {
+			return nil, errors.New("invalid plugin config file " + configFile + ": " + err.Error())
+		}
+	}
+
+	if pluginConfig == nil && len(config) > 0 {
+		pluginConfig = make(map[string]interface{})
+	}
+
+	//	config attributes have precedence over the config file
+	for _, attribute := range config {
+		keys := strings.Split(attribute.key, ".")
+		node := pluginConfig
+
+		for i, key := range keys {
+			if len(key) == 0 {
+				return nil, errors.New("invalid plugin config attribute: config." + attribute.key)
+			}
+
+			if i == len(keys)-1 {
+				node[key] = pluginConfigValue(attribute.value)
+				break
+			}
+
+			child, exists := node[key]
+			if !exists || child == nil {
+				child = make(map[string]interface{})
+				node[key] = child
+			}
+
+			childNode, ok := child.(map[string]interface{})
+			if !ok {
+				return nil, errors.New("invalid plugin config attribute: config." + strings.Join(keys[:i+1], ".") + " is not an object")
+			}
+			node = childNode
+		}
+	}
+
+	return pluginConfig, nil
+}
+
+// a config attribute value as typed in the command line, kept until its type is known
+type pluginConfigLiteral string
+
+// parse a config attribute value: arrays are comma separated, optionally enclosed in brackets
+func pluginConfigValue(value string) interface{} {
+
+	const valuesDelim = ","
+
+	if strings.HasPrefix(value, "[") && strings.HasSuffix(value, "]") {
+		value = strings.TrimSpace(value[1 : len(value)-1])
+		if len(value) == 0 {
+			return []interface{}{}
+		}
+	} else if !strings.Contains(value, valuesDelim) {
+		if value == "null" {
+			return nil
+		}
+
+		return pluginConfigLiteral(value)
+	}
+
+	var array []interface{}
+
+	for _, item := range strings.Split(value, valuesDelim) {
+		array = append(array, pluginConfigLiteral(strings.TrimSpace(item)))
+	}
+
+	return array
+}
+
+// convert the command line literals of a plugin config to their inferred types
+func pluginConfigTyped(config map[string]interface{}) map[string]interface{} {
+
+	for key := range config {
+		config[key] = pluginConfigTypedValue(config[key])
+	}
+
+	return config
+}
+
+// convert the command line literals of a config value to their inferred types
+func pluginConfigTypedValue(value interface{}) interface{} {
+
+	switch typed := value.(type) {
+	case pluginConfigLiteral:
+		return pluginConfigScalar(string(typed))
+
+	case map[string]interface{}:
+		return pluginConfigTyped(typed)
+
+	case []interface{}:
+		for i := range typed {
+			typed[i] = pluginConfigTypedValue(typed[i])
+		}
+	}
+
+	return value
+}
+
+// infer the type of a single config attribute literal: numbers are only converted when they keep the literal
+// (e.g. 0123 and 1.50 remain strings)
+func pluginConfigScalar(value string) interface{} {
+
+	switch value {
+	case "true":
+		return true
+
+	case "false":
+		return false
+	}
+
+	if intValue, err := strconv.ParseInt(value, 10, 64); err == nil && strconv.FormatInt(intValue, 10) == value {
+		return intValue
+	}
+
+	if floatValue, err := strconv.ParseFloat(value, 64); err == nil && strconv.FormatFloat(floatValue, 'f', -1, 64) == value {
+		return floatValue
+	}
+
+	return value
+}
+
 // kong plugin Id payload
 type KongPluginEntityId struct {
 	Id string `json:"id,omitempty"`
@@ -53,26 +206,30 @@ type KongPluginEntityId struct {
 
 // kong plugin request payload
 type KongPluginRequest struct {
-	Name    string              `json:"name,omitempty"`
-	Service *KongPluginEntityId `json:"service,omitempty"`
-	Route   *KongPluginEntityId `json:"route,omitempty"`
-	Enabled bool                `json:"enabled"`
+	Name          string                 `json:"name,omitempty"`
+	Service       *KongPluginEntityId    `json:"service,omitempty"`
+	Route         *KongPluginEntityId    `json:"route,omitempty"`
+	ConsumerGroup *KongPluginEntityId    `json:"consumer_group,omitempty"`
+	Config        map[string]interface{} `json:"config,omitempty"`
+	Enabled       bool                   `json:"enabled"`
 }
 
 // kong plugin response payload
 type KongPluginResponse struct {
-	Id           string             `json:"id"`
-	Name         string             `json:"name"`
-	InstanceName string             `json:"instance_name"`
-	Protocols    []string           `json:"protocols"`
-	Service      KongPluginEntityId `json:"service,omitempty"`
-	Route        KongPluginEntityId `json:"route,omitempty"`
-	Consumer     KongPluginEntityId `json:"consumer,omitempty"`
-	Tags         string             `json:"tags"`
-	CreatedAt    uint64             `json:"created_at"`
-	UpdatedAt    uint64             `json:"updated_at"`
-	Ordering     string             `json:"ordering"`
-	Enabled      bool               `json:"enabled"`
+	Id            string                 `json:"id"`
+	Name          string                 `json:"name"`
+	InstanceName  string                 `json:"instance_name"`
+	Protocols     []string               `json:"protocols"`
+	Service       KongPluginEntityId     `json:"service,omitempty"`
+	Route         KongPluginEntityId     `json:"route,omitempty"`
+	Consumer      KongPluginEntityId     `json:"consumer,omitempty"`
+	ConsumerGroup KongPluginEntityId     `json:"consumer_group,omitempty"`
+	Config        map[string]interface{} `json:"config"`
+	Tags          []string               `json:"tags"`
+	CreatedAt     uint64                 `json:"created_at"`
+	UpdatedAt     uint64                 `json:"updated_at"`
+	Ordering      string                 `json:"ordering"`
+	Enabled       bool                   `json:"enabled"`
 }
 
 //    "config": {
@@ -110,8 +267,20 @@ func (ks *KongServerDomain) AddPlugin(newKongPlugin *KongPlugin, options Options
 
 	var pluginURL string = fmt.Sprintf("%s/%s", ks.ServerURL(), pluginsResource)
 
+	//	plugins scoped to a consumer group are added through the group
+	if len(newKongPlugin.consumerGroupId) > 0 {
+		pluginURL = fmt.Sprintf("%s/%s/%s/%s", ks.ServerURL(), consumerGroupsResource, newKongPlugin.consumerGroupId, pluginsResource)
+	}
+
+	//	validate the plugin config before sending it to Kong
+	pluginConfig, err := ks.validatePluginConfig(newKongPlugin.name, newKongPlugin.config, false, options)
+	if err != nil {
+		return err
+	}
+
 	pluginReq := KongPluginRequest{
 		Name:    newKongPlugin.name,
+		Config:  pluginConfig,
 		Enabled: newKongPlugin.enabled,
 	}
 
@@ -136,21 +305,18 @@ func (ks *KongServerDomain) AddPlugin(newKongPlugin *KongPlugin, options Options
 
 	//log.Printf("[debug] post payload: %s\n", payload)
 
-	req, err := http.NewRequest("POST", pluginURL, bytes.NewBuffer([]byte(payload)))
+	resp, err := ks.sendRequest("POST", pluginURL, payload)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	defer resp.Body.Close()
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return err
+	if resp.StatusCode == http.StatusNotFound && len(newKongPlugin.consumerGroupId) > 0 {
+		return newKongNotFoundError("consumer group", resp)
 	}
-	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		return errors.New("fail sending add plugin command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending add plugin command to Kong", resp)
 	}
 
 	//	parse response payload
@@ -187,17 +353,17 @@ func (ks *KongServerDomain) QueryPlugin(id string, options Options) error {
 	var pluginURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), pluginsResource, id)
 
 	//	send a request to Kong to query the plugin by id
-	resp, err := http.Get(pluginURL)
+	resp, err := ks.sendRequest("GET", pluginURL, nil)
 	if err != nil {
 		return err
 	}
 
 	if resp.StatusCode == http.StatusNotFound {
-		return errors.New("plugin not found")
+		return newKongNotFoundError("plugin", resp)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.New("fail sending query plugin command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending query plugin command to Kong", resp)
 	}
 
 	var respPayload []byte
@@ -217,42 +383,57 @@ func (ks *KongServerDomain) QueryPlugin(id string, options Options) error {
 			return err
 		}
 
+		pluginConfig, err := json.Marshal(pluginResp.Config)
+		if err != nil {
+			return err
+		}
+
 		if options.verbose {
-			fmt.Printf("http response status code: %s\n%s: %s - %s: serviceId: %s ; routeId: %s ; consumerId: %s\n", resp.Status,
-				pluginResp.Id, pluginResp.Name, pluginResp.Protocols, pluginResp.Service.Id, pluginResp.Route.Id, pluginResp.Consumer.Id)
+			fmt.Printf("http response status code: %s\n%s: %s - %s: serviceId: %s ; routeId: %s ; consumerId: %s\nconfig: %s\n", resp.Status,
+				pluginResp.Id, pluginResp.Name, pluginResp.Protocols, pluginResp.Service.Id, pluginResp.Route.Id, pluginResp.Consumer.Id, pluginConfig)
 		} else {
-			fmt.Printf("%s: %s - %s: serviceId: %s ; routeId: %s ; consumerId: %s\n",
-				pluginResp.Id, pluginResp.Name, pluginResp.Protocols, pluginResp.Service.Id, pluginResp.Route.Id, pluginResp.Consumer.Id)
+			fmt.Printf("%s: %s - %s: serviceId: %s ; routeId: %s ; consumerId: %s\nconfig: %s\n",
+				pluginResp.Id, pluginResp.Name, pluginResp.Protocols, pluginResp.Service.Id, pluginResp.Route.Id, pluginResp.Consumer.Id, pluginConfig)
 		}
 	}
 
 	return nil
 }
 
-// query a plugin by Id
-func (ks *KongServerDomain) ListPlugins(options Options) error {
+// the plugins collection: the one nested in a service, route or consumer, or the global one
+func pluginsCollectionResource(serviceId string, routeId string, consumerId string) string {
 
-	var pluginURL string = fmt.Sprintf("%s/%s", ks.ServerURL(), pluginsResource)
+	switch {
+	case len(serviceId) > 0:
+		return fmt.Sprintf("%s/%s/%s", servicesResource, serviceId, pluginsResource)
 
-	//	send a request to Kong to get a list of all plugins
-	resp, err := http.Get(pluginURL)
-	if err != nil {
-		return err
-	}
+	case len(routeId) > 0:
+		return fmt.Sprintf("%s/%s/%s", routesResource, routeId, pluginsResource)
 
-	if resp.StatusCode != http.StatusOK {
-		return errors.New("fail sending list plugins command to Kong: " + resp.Status)
+	case len(consumerId) > 0:
+		return fmt.Sprintf("%s/%s/%s", consumersResource, consumerId, pluginsResource)
 	}
 
-	var respPayload []byte
+	return pluginsResource
+}
 
-	respPayload, err = io.ReadAll(resp.Body)
+// query a plugin by Id
+func (ks *KongServerDomain) ListPlugins(serviceId string, routeId string, consumerId string, pagination *KongPagination, options Options) error {
+
+	//	send a request to Kong to get a list of all plugins, or the plugins of a service, route or consumer
+	collection, status, err := ks.fetchCollection(pluginsCollectionResource(serviceId, routeId, consumerId), pagination,
+		"fail sending list plugins command to Kong")
+	if err != nil {
+		return err
+	}
+
+	respPayload, err := json.Marshal(collection)
 	if err != nil {
 		return err
 	}
 
 	if options.jsonOutput {
-		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+		fmt.Printf("%s\n%s\n", status, string(respPayload))
 	} else {
 		var pluginListResp KongPluginListResponse
 
@@ -263,7 +444,7 @@ func (ks *KongServerDomain) ListPlugins(options Options) error {
 
 		if len(pluginListResp.Data) == 0 {
 			if options.verbose {
-				fmt.Printf("%s\nNo plugins\n", resp.Status)
+				fmt.Printf("%s\nNo plugins\n", status)
 			} else {
 				fmt.Printf("No plugins\n")
 			}
@@ -272,25 +453,79 @@ func (ks *KongServerDomain) ListPlugins(options Options) error {
 		}
 
 		if options.verbose {
-			fmt.Printf("http response status code: %s\nplugin list\n", resp.Status)
+			fmt.Printf("http response status code: %s\nplugin list\n", status)
 		}
 
 		for _, plugin := range pluginListResp.Data {
 			fmt.Printf("plugin: %s: %s - %s: serviceId: %s ; routeId: %s ; consumerId: %s\n",
 				plugin.Id, plugin.Name, plugin.Protocols, plugin.Service.Id, plugin.Route.Id, plugin.Consumer.Id)
 		}
+		printNextOffset(collection)
 	}
 
 	return nil
 }
 
+// get the name of a plugin by Id, unless it's already known
+func (ks *KongServerDomain) pluginName(id string, name string) (string, error) {
+
+	if len(name) > 0 {
+		return name, nil
+	}
+
+	var pluginURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), pluginsResource, id)
+
+	resp, err := ks.sendRequest("GET", pluginURL, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", newKongNotFoundError("plugin", resp)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", newKongAPIError("fail sending query plugin command to Kong", resp)
+	}
+
+	respPayload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var pluginResp KongPluginResponse
+
+	err = json.Unmarshal(respPayload, &pluginResp)
+	if err != nil {
+		return "", err
+	}
+
+	return pluginResp.Name, nil
+}
+
 // update a plugin in Kong
 func (ks *KongServerDomain) UpdatePlugin(id string, updatedKongPlugin *KongPlugin, options Options) error {
 
 	var pluginURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), pluginsResource, id)
 
+	//	validate the updated plugin config attributes before sending them to Kong
+	pluginConfig := updatedKongPlugin.config
+	if len(pluginConfig) > 0 {
+		pluginName, err := ks.pluginName(id, updatedKongPlugin.name)
+		if err != nil {
+			return err
+		}
+
+		pluginConfig, err = ks.validatePluginConfig(pluginName, pluginConfig, true, options)
+		if err != nil {
+			return err
+		}
+	}
+
 	pluginReq := KongPluginRequest{
 		Name:    updatedKongPlugin.name,
+		Config:  pluginConfig,
 		Enabled: updatedKongPlugin.enabled,
 	}
 
@@ -308,6 +543,13 @@ func (ks *KongServerDomain) UpdatePlugin(id string, updatedKongPlugin *KongPlugi
 		}
 	}
 
+	if len(updatedKongPlugin.consumerGroupId) > 0 {
+
+		pluginReq.ConsumerGroup = &KongPluginEntityId{
+			Id: updatedKongPlugin.consumerGroupId,
+		}
+	}
+
 	payload, err := json.Marshal(pluginReq)
 	if err != nil {
 		return err
@@ -315,25 +557,18 @@ func (ks *KongServerDomain) UpdatePlugin(id string, updatedKongPlugin *KongPlugi
 
 	//	log.Printf("[debug] patch payload: %s", payload)
 
-	req, err := http.NewRequest("PATCH", pluginURL, bytes.NewBuffer([]byte(payload)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ks.sendRequest("PATCH", pluginURL, payload)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return errors.New("plugin not found")
+		return newKongNotFoundError("plugin", resp)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.New("fail sending patch plugin command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending patch plugin command to Kong", resp)
 	}
 
 	//	parse response payload
@@ -372,21 +607,14 @@ func (ks *KongServerDomain) DeletePlugin(id string, options Options) error {
 	var pluginURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), pluginsResource, id)
 
 	//	send a request to Kong to delete the plugin by id
-	req, err := http.NewRequest("DELETE", pluginURL, bytes.NewBuffer([]byte("")))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ks.sendRequest("DELETE", pluginURL, nil)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		return errors.New("fail sending delete plugin command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending delete plugin command to Kong", resp)
 	}
 
 	if options.jsonOutput {
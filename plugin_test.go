@@ -7,9 +7,14 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
+	"io"
 	"net/http"
 	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
 	"testing"
 )
 
@@ -76,6 +81,179 @@ func Test_AddPlugin(t *testing.T) {
 			t.Errorf("failed checking kong status: success expected: result: %s", got.Error())
 		}
 	})
+
+	t.Run(">>> AddPlugin: scenario 3 - plugin created with config", func(t *testing.T) {
+
+		var pluginReq KongPluginRequest
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			payload, _ := io.ReadAll(r.Body)
+			json.Unmarshal(payload, &pluginReq)
+
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{ "id": "1234", "name": "rate-limiting" }`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		config, err := NewKongPluginConfig("", []KongPluginConfig{{key: "minute", value: "10"}})
+		if err != nil {
+			t.Fatalf("failed creating plugin config: success expected: result: %s", err.Error())
+		}
+
+		var want error = nil
+		got := kongServer.AddPlugin(NewKongPlugin("rate-limiting", "", "", config, true), Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if want != got {
+			t.Fatalf("failed adding plugin: success expected: result: %s", got.Error())
+		}
+
+		if pluginReq.Config["minute"] != float64(10) {
+			t.Errorf("failed adding plugin: config.minute expected: 10 result: %v", pluginReq.Config["minute"])
+		}
+	})
+	t.Run(">>> AddPlugin: scenario 4 - plugin scoped to a consumer group", func(t *testing.T) {
+
+		var pluginPath string
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "POST" {
+				pluginPath = r.URL.Path
+			}
+
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{ "id": "1234", "name": "rate-limiting" }`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"add", "plugin", "--name=rate-limiting", "--config.minute=100", "--consumer-group-id=gold"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed adding plugin: success expected: result: %s", got.Error())
+		}
+
+		if pluginPath != "/consumer_groups/gold/plugins" {
+			t.Errorf("failed adding plugin: consumer group resource expected: result: %s", pluginPath)
+		}
+	})
+}
+
+// Test_NewKongPluginConfig unit tests for NewKongPluginConfig() function
+func Test_NewKongPluginConfig(t *testing.T) {
+
+	t.Run(">>> NewKongPluginConfig: scenario 1 - nested attributes, arrays and literals", func(t *testing.T) {
+
+		got, err := NewKongPluginConfig("", []KongPluginConfig{
+			{key: "minute", value: "10"},
+			{key: "fault_tolerant", value: "false"},
+			{key: "redis.host", value: "redis"},
+			{key: "redis.port", value: "6379"},
+			{key: "key_names", value: "apikey,x-api-key"},
+			{key: "allow", value: "[192.168.68.0/24]"},
+		})
+		if err != nil {
+			t.Fatalf("failed creating plugin config: success expected: result: %s", err.Error())
+		}
+
+		want := map[string]interface{}{
+			"minute":         pluginConfigLiteral("10"),
+			"fault_tolerant": pluginConfigLiteral("false"),
+			"redis":          map[string]interface{}{"host": pluginConfigLiteral("redis"), "port": pluginConfigLiteral("6379")},
+			"key_names":      []interface{}{pluginConfigLiteral("apikey"), pluginConfigLiteral("x-api-key")},
+			"allow":          []interface{}{pluginConfigLiteral("192.168.68.0/24")},
+		}
+
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("failed creating plugin config: expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> NewKongPluginConfig: scenario 2 - config file overridden by attributes", func(t *testing.T) {
+
+		configFile := filepath.Join(t.TempDir(), "plugin.json")
+		os.WriteFile(configFile, []byte(`{ "minute": 10, "policy": "local", "redis": { "host": "localhost" } }`), 0644)
+
+		got, err := NewKongPluginConfig(configFile, []KongPluginConfig{{key: "redis.host", value: "redis"}})
+		if err != nil {
+			t.Fatalf("failed creating plugin config: success expected: result: %s", err.Error())
+		}
+
+		want := map[string]interface{}{
+			"minute": float64(10),
+			"policy": "local",
+			"redis":  map[string]interface{}{"host": pluginConfigLiteral("redis")},
+		}
+
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("failed creating plugin config: expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> NewKongPluginConfig: scenario 3 - attribute nested in a scalar", func(t *testing.T) {
+
+		want := errors.New("invalid plugin config attribute: config.policy is not an object")
+		_, got := NewKongPluginConfig("", []KongPluginConfig{{key: "policy", value: "local"}, {key: "policy.name", value: "x"}})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed creating plugin config: error expected: %v result: %v", want, got)
+		}
+	})
+}
+
+// Test_pluginConfigTyped unit tests for pluginConfigTyped() function
+func Test_pluginConfigTyped(t *testing.T) {
+
+	t.Run(">>> pluginConfigTyped: scenario 1 - literals converted only when they are kept", func(t *testing.T) {
+
+		config, err := NewKongPluginConfig("", []KongPluginConfig{
+			{key: "minute", value: "10"},
+			{key: "ratio", value: "1.5"},
+			{key: "fault_tolerant", value: "false"},
+			{key: "key", value: "0123"},
+			{key: "price", value: "1.50"},
+			{key: "secret", value: "1e21"},
+			{key: "redis.timeout", value: "null"},
+			{key: "key_names", value: "apikey,007"},
+		})
+		if err != nil {
+			t.Fatalf("failed creating plugin config: success expected: result: %s", err.Error())
+		}
+
+		want := map[string]interface{}{
+			"minute":         int64(10),
+			"ratio":          1.5,
+			"fault_tolerant": false,
+			"key":            "0123",
+			"price":          "1.50",
+			"secret":         "1e21",
+			"redis":          map[string]interface{}{"timeout": nil},
+			"key_names":      []interface{}{"apikey", "007"},
+		}
+		got := pluginConfigTyped(config)
+
+		if !reflect.DeepEqual(want, got) {
+			t.Errorf("failed typing plugin config: expected: %v result: %v", want, got)
+		}
+	})
 }
 
 // Test_QueryPlugin unit tests for QueryPlugin() method
@@ -187,7 +365,7 @@ func Test_ListPlugin(t *testing.T) {
 		}
 
 		want := errors.New("fail sending list plugins command to Kong: 500 Internal Server Error")
-		got := kongServer.ListPlugins(Options{
+		got := kongServer.ListPlugins("", "", "", nil, Options{
 			verbose:    false,
 			jsonOutput: false,
 		})
@@ -227,7 +405,7 @@ func Test_ListPlugin(t *testing.T) {
 		}
 
 		var want error = nil
-		got := kongServer.ListPlugins(Options{
+		got := kongServer.ListPlugins("", "", "", nil, Options{
 			verbose:    false,
 			jsonOutput: false,
 		})
@@ -237,6 +415,71 @@ func Test_ListPlugin(t *testing.T) {
 			t.Errorf("failed checking kong status: success expected: result: %s", got.Error())
 		}
 	})
+
+	t.Run(">>> ListPlugin: scenario 3 - plugins of a service, route or consumer", func(t *testing.T) {
+
+		var requestPath string
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestPath = r.URL.Path
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data": [], "next": null}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		var testScenarios = []struct {
+			command []string
+			want    string
+		}{
+			{command: []string{"list", "plugin"}, want: "/plugins"},
+			{command: []string{"list", "plugin", "--service-id=Produtos"}, want: "/services/Produtos/plugins"},
+			{command: []string{"list", "plugin", "--route-id=produtos-v1"}, want: "/routes/produtos-v1/plugins"},
+			{command: []string{"list", "plugin", "--consumer-id=partner"}, want: "/consumers/partner/plugins"},
+			{command: []string{"list", "consumer-plugin", "--id=partner"}, want: "/consumers/partner/plugins"},
+		}
+
+		for _, test := range testScenarios {
+			got := kconf(kongServer, test.command, Options{})
+
+			//	check the invocation result
+			if got != nil {
+				t.Fatalf("failed listing plugins: success expected: result: %s", got.Error())
+			}
+
+			if requestPath != test.want {
+				t.Errorf("failed listing plugins %v: request path expected: %s result: %s", test.command, test.want, requestPath)
+			}
+		}
+	})
+
+	t.Run(">>> ListPlugin: scenario 4 - invalid options", func(t *testing.T) {
+
+		var testScenarios = []struct {
+			command []string
+			want    string
+		}{
+			{command: []string{"list", "plugin", "--service-id=Produtos", "--route-id=produtos-v1"},
+				want: "options --service-id, --route-id and --consumer-id can't be used together"},
+			{command: []string{"list", "consumer-plugin"},
+				want: "missing consumer id: option --id={id} required for this command"},
+		}
+
+		for _, test := range testScenarios {
+			got := kconf(NewKongServer("http://localhost:8001", 0), test.command, Options{})
+
+			//	check the invocation result
+			if got == nil || got.Error() != test.want {
+				t.Errorf("failed listing plugins %v: error expected: %s result: %v", test.command, test.want, got)
+			}
+		}
+	})
 }
 
 // Test_UpdatePlugin unit tests for UpdatePlugin() method
@@ -7,12 +7,14 @@
 package main
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"sort"
+	"strconv"
+	"strings"
 )
 
 // kong route attributes
@@ -22,6 +24,24 @@ type KongRoute struct {
 	methods   []string
 	paths     []string
 	serviceId string
+
+	hosts        []string
+	headers      map[string][]string
+	snis         []string
+	sources      []KongRouteEndpoint
+	destinations []KongRouteEndpoint
+
+	stripPath               *bool
+	preserveHost            *bool
+	regexPriority           *int
+	pathHandling            string
+	httpsRedirectStatusCode *int
+	requestBuffering        *bool
+	responseBuffering       *bool
+	tags                    []string
+
+	expression string
+	priority   *int64
 }
 
 // create a new Kong route
@@ -36,27 +56,178 @@ func NewKongRoute(name string, protocols []string, methods []string, paths []str
 	}
 }
 
+// set the route matching attributes other than protocols, methods and paths
+func (route *KongRoute) setMatching(hosts []string, headers map[string][]string, snis []string, sources []KongRouteEndpoint, destinations []KongRouteEndpoint) {
+
+	route.hosts = hosts
+	route.headers = headers
+	route.snis = snis
+	route.sources = sources
+	route.destinations = destinations
+}
+
+// set the attributes that change how Kong proxies a matched request
+func (route *KongRoute) setProxying(stripPath *bool, preserveHost *bool, regexPriority *int, pathHandling string,
+	httpsRedirectStatusCode *int, requestBuffering *bool, responseBuffering *bool) {
+
+	route.stripPath = stripPath
+	route.preserveHost = preserveHost
+	route.regexPriority = regexPriority
+	route.pathHandling = pathHandling
+	route.httpsRedirectStatusCode = httpsRedirectStatusCode
+	route.requestBuffering = requestBuffering
+	route.responseBuffering = responseBuffering
+}
+
+// set the expressions router attributes
+func (route *KongRoute) setExpression(expression string, priority *int64) {
+
+	route.expression = expression
+	route.priority = priority
+}
+
+// set the route tags
+func (route *KongRoute) setTags(tags []string) {
+
+	route.tags = tags
+}
+
+// kong route source or destination: an IP (or CIDR range) and/or a port
+type KongRouteEndpoint struct {
+	IP   string `json:"ip,omitempty" yaml:"ip,omitempty"`
+	Port int    `json:"port,omitempty" yaml:"port,omitempty"`
+}
+
+// format an endpoint as ip:port, using [ip]:port for IPv6
+func (endpoint KongRouteEndpoint) String() string {
+
+	ip := endpoint.IP
+	if strings.Contains(ip, ":") {
+		ip = "[" + ip + "]"
+	}
+
+	switch {
+	case endpoint.Port == 0:
+		return ip
+
+	case len(ip) == 0:
+		return ":" + strconv.Itoa(endpoint.Port)
+	}
+
+	return ip + ":" + strconv.Itoa(endpoint.Port)
+}
+
+// parse a comma separated list of endpoints: ip, ip:port, :port or [ipv6]:port
+func parseRouteEndpoints(value string) ([]KongRouteEndpoint, error) {
+
+	const valuesDelim = ","
+	var endpoints []KongRouteEndpoint
+
+	for _, item := range strings.Split(value, valuesDelim) {
+		var endpoint KongRouteEndpoint
+		var port string
+
+		switch {
+		case strings.HasPrefix(item, "["):
+			ip, rest, found := strings.Cut(item[1:], "]")
+			if !found || (len(rest) > 0 && !strings.HasPrefix(rest, ":")) {
+				return nil, errors.New("invalid endpoint: " + item)
+			}
+			endpoint.IP = ip
+			port = strings.TrimPrefix(rest, ":")
+
+		case strings.Count(item, ":") > 1:
+			endpoint.IP = item
+
+		default:
+			endpoint.IP, port, _ = strings.Cut(item, ":")
+		}
+
+		if len(port) > 0 {
+			number, err := strconv.Atoi(port)
+			if err != nil || number < 1 || number > maxPort {
+				return nil, errors.New("invalid endpoint port: " + item)
+			}
+			endpoint.Port = number
+		}
+
+		if len(endpoint.IP) == 0 && endpoint.Port == 0 {
+			return nil, errors.New("invalid endpoint: " + item)
+		}
+
+		endpoints = append(endpoints, endpoint)
+	}
+
+	return endpoints, nil
+}
+
+// parse a header option: {header}:{comma separated values}
+func parseRouteHeader(value string) (string, []string, error) {
+
+	const valuesDelim = ","
+
+	name, values, found := strings.Cut(value, ":")
+	name = strings.TrimSpace(name)
+	values = strings.TrimSpace(values)
+
+	if !found || len(name) == 0 || len(values) == 0 {
+		return "", nil, errors.New("invalid header: " + value + ": must be {header}:{values}")
+	}
+
+	return name, strings.Split(values, valuesDelim), nil
+}
+
 // kong route request payload
 type serviceId struct {
 	Id string `json:"id"`
 }
 
 type KongRouteRequest struct {
-	Name      string     `json:"name,omitempty"`
-	Protocols []string   `json:"protocols,omitempty"`
-	Methods   []string   `json:"methods,omitempty"`
-	Paths     []string   `json:"paths,omitempty"`
-	Service   *serviceId `json:"service,omitempty"`
+	Name                    string              `json:"name,omitempty"`
+	Protocols               []string            `json:"protocols,omitempty"`
+	Methods                 []string            `json:"methods,omitempty"`
+	Paths                   []string            `json:"paths,omitempty"`
+	Hosts                   []string            `json:"hosts,omitempty"`
+	Headers                 map[string][]string `json:"headers,omitempty"`
+	Snis                    []string            `json:"snis,omitempty"`
+	Sources                 []KongRouteEndpoint `json:"sources,omitempty"`
+	Destinations            []KongRouteEndpoint `json:"destinations,omitempty"`
+	StripPath               *bool               `json:"strip_path,omitempty"`
+	PreserveHost            *bool               `json:"preserve_host,omitempty"`
+	RegexPriority           *int                `json:"regex_priority,omitempty"`
+	PathHandling            string              `json:"path_handling,omitempty"`
+	HttpsRedirectStatusCode *int                `json:"https_redirect_status_code,omitempty"`
+	RequestBuffering        *bool               `json:"request_buffering,omitempty"`
+	ResponseBuffering       *bool               `json:"response_buffering,omitempty"`
+	Tags                    []string            `json:"tags,omitempty"`
+	Expression              string              `json:"expression,omitempty"`
+	Priority                *int64              `json:"priority,omitempty"`
+	Service                 *serviceId          `json:"service,omitempty"`
 }
 
 // kong route response payload
 type KongRouteResponse struct {
-	Id        string    `json:"id"`
-	Name      string    `json:"name"`
-	Protocols []string  `json:"protocols"`
-	Methods   []string  `json:"methods"`
-	Paths     []string  `json:"paths"`
-	Service   serviceId `json:"service"`
+	Id                      string              `json:"id"`
+	Name                    string              `json:"name"`
+	Protocols               []string            `json:"protocols"`
+	Methods                 []string            `json:"methods"`
+	Paths                   []string            `json:"paths"`
+	Hosts                   []string            `json:"hosts"`
+	Headers                 map[string][]string `json:"headers"`
+	Snis                    []string            `json:"snis"`
+	Sources                 []KongRouteEndpoint `json:"sources"`
+	Destinations            []KongRouteEndpoint `json:"destinations"`
+	StripPath               bool                `json:"strip_path"`
+	PreserveHost            bool                `json:"preserve_host"`
+	RegexPriority           int                 `json:"regex_priority"`
+	PathHandling            string              `json:"path_handling"`
+	HttpsRedirectStatusCode int                 `json:"https_redirect_status_code"`
+	RequestBuffering        bool                `json:"request_buffering"`
+	ResponseBuffering       bool                `json:"response_buffering"`
+	Expression              string              `json:"expression"`
+	Priority                int64               `json:"priority"`
+	Service                 serviceId           `json:"service"`
+	Tags                    []string            `json:"tags"`
 }
 
 // kong route list response payload
@@ -67,41 +238,102 @@ type KongRouteListResponse struct {
 
 const (
 	routesResource string = "routes"
+
+	maxRegexPriority int   = 2147483647
+	maxRoutePriority int64 = 70368744177663
 )
 
+// build the route request payload: the service is only sent when set
+func routeRequest(kongRoute *KongRoute) *KongRouteRequest {
+
+	request := &KongRouteRequest{
+		Name:                    kongRoute.name,
+		Protocols:               kongRoute.protocols,
+		Methods:                 kongRoute.methods,
+		Paths:                   kongRoute.paths,
+		Hosts:                   kongRoute.hosts,
+		Headers:                 kongRoute.headers,
+		Snis:                    kongRoute.snis,
+		Sources:                 kongRoute.sources,
+		Destinations:            kongRoute.destinations,
+		StripPath:               kongRoute.stripPath,
+		PreserveHost:            kongRoute.preserveHost,
+		RegexPriority:           kongRoute.regexPriority,
+		PathHandling:            kongRoute.pathHandling,
+		HttpsRedirectStatusCode: kongRoute.httpsRedirectStatusCode,
+		RequestBuffering:        kongRoute.requestBuffering,
+		ResponseBuffering:       kongRoute.responseBuffering,
+		Tags:                    kongRoute.tags,
+		Expression:              kongRoute.expression,
+		Priority:                kongRoute.priority,
+	}
+
+	if len(kongRoute.serviceId) > 0 {
+		request.Service = &serviceId{
+			Id: kongRoute.serviceId,
+		}
+	}
+
+	return request
+}
+
+// print the route matching and proxying attributes, skipping the empty matching ones
+func printRouteDetails(route *KongRouteResponse) {
+
+	var matching []string
+
+	if len(route.Expression) > 0 {
+		matching = append(matching, fmt.Sprintf("expression: %s ; priority: %d", route.Expression, route.Priority))
+	}
+	if len(route.Hosts) > 0 {
+		matching = append(matching, fmt.Sprintf("hosts: %s", route.Hosts))
+	}
+	if len(route.Headers) > 0 {
+		var headers []string
+
+		for name, values := range route.Headers {
+			headers = append(headers, name+":"+strings.Join(values, ","))
+		}
+		sort.Strings(headers)
+
+		matching = append(matching, fmt.Sprintf("headers: %s", headers))
+	}
+	if len(route.Snis) > 0 {
+		matching = append(matching, fmt.Sprintf("snis: %s", route.Snis))
+	}
+	if len(route.Sources) > 0 {
+		matching = append(matching, fmt.Sprintf("sources: %s", route.Sources))
+	}
+	if len(route.Destinations) > 0 {
+		matching = append(matching, fmt.Sprintf("destinations: %s", route.Destinations))
+	}
+	if len(matching) > 0 {
+		fmt.Printf("    %s\n", strings.Join(matching, " ; "))
+	}
+
+	fmt.Printf("    strip path: %t ; preserve host: %t ; regex priority: %d ; path handling: %s ; https redirect: %d ; buffering (request/response): %t/%t ; tags: %s\n",
+		route.StripPath, route.PreserveHost, route.RegexPriority, route.PathHandling, route.HttpsRedirectStatusCode,
+		route.RequestBuffering, route.ResponseBuffering, route.Tags)
+}
+
 // add a new route to Kong
 func (ks *KongServerDomain) AddRoute(newKongRoute *KongRoute, options Options) error {
 
 	var routeURL string = fmt.Sprintf("%s/%s", ks.ServerURL(), routesResource)
 
-	payload, err := json.Marshal(KongRouteRequest{
-		Name:      newKongRoute.name,
-		Protocols: newKongRoute.protocols,
-		Methods:   newKongRoute.methods,
-		Paths:     newKongRoute.paths,
-		Service: &serviceId{
-			Id: newKongRoute.serviceId,
-		},
-	})
-	if err != nil {
-		return err
-	}
-
-	req, err := http.NewRequest("POST", routeURL, bytes.NewBuffer([]byte(payload)))
+	payload, err := json.Marshal(routeRequest(newKongRoute))
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ks.sendRequest("POST", routeURL, payload)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		return errors.New("fail sending add route command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending add route command to Kong", resp)
 	}
 
 	//	parse response payload
@@ -138,17 +370,17 @@ func (ks *KongServerDomain) QueryRoute(id string, options Options) error {
 	var routeURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), routesResource, id)
 
 	//	send a request to Kong to query the route by id
-	resp, err := http.Get(routeURL)
+	resp, err := ks.sendRequest("GET", routeURL, nil)
 	if err != nil {
 		return err
 	}
 
 	if resp.StatusCode == http.StatusNotFound {
-		return errors.New("route not found")
+		return newKongNotFoundError("route", resp)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.New("fail sending query route command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending query route command to Kong", resp)
 	}
 
 	var respPayload []byte
@@ -175,35 +407,34 @@ func (ks *KongServerDomain) QueryRoute(id string, options Options) error {
 			fmt.Printf("route: %s - %s %s:%s --> Service Id: %s\n",
 				routeResp.Name, routeResp.Methods, routeResp.Protocols, routeResp.Paths, routeResp.Service.Id)
 		}
+		printRouteDetails(&routeResp)
 	}
 
 	return nil
 }
 
 // list all routes
-func (ks *KongServerDomain) ListRoutes(options Options) error {
+func (ks *KongServerDomain) ListRoutes(serviceId string, pagination *KongPagination, options Options) error {
 
-	var routeURL string = fmt.Sprintf("%s/%s/", ks.ServerURL(), routesResource)
+	var resource string = routesResource
 
-	//	send a request to Kong to get a list of all routes
-	resp, err := http.Get(routeURL)
-	if err != nil {
-		return err
+	if len(serviceId) > 0 {
+		resource = fmt.Sprintf("%s/%s/%s", servicesResource, serviceId, routesResource)
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return errors.New("fail sending list route command to Kong: " + resp.Status)
+	//	send a request to Kong to get a list of all routes, or the routes of a service
+	collection, status, err := ks.fetchCollection(resource, pagination, "fail sending list route command to Kong")
+	if err != nil {
+		return err
 	}
 
-	var respPayload []byte
-
-	respPayload, err = io.ReadAll(resp.Body)
+	respPayload, err := json.Marshal(collection)
 	if err != nil {
 		return err
 	}
 
 	if options.jsonOutput {
-		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+		fmt.Printf("%s\n%s\n", status, string(respPayload))
 	} else {
 		var routeListResp KongRouteListResponse
 
@@ -214,7 +445,7 @@ func (ks *KongServerDomain) ListRoutes(options Options) error {
 
 		if len(routeListResp.Data) == 0 {
 			if options.verbose {
-				fmt.Printf("%s\nNo routes\n", resp.Status)
+				fmt.Printf("%s\nNo routes\n", status)
 			} else {
 				fmt.Printf("No routes\n")
 			}
@@ -223,13 +454,15 @@ func (ks *KongServerDomain) ListRoutes(options Options) error {
 		}
 
 		if options.verbose {
-			fmt.Printf("http response status code: %s\nroute list\n", resp.Status)
+			fmt.Printf("http response status code: %s\nroute list\n", status)
 		}
 
 		for _, route := range routeListResp.Data {
 			fmt.Printf("%s: %s - %s %s:%s --> Service Id: %s\n", route.Id,
 				route.Name, route.Methods, route.Protocols, route.Paths, route.Service.Id)
+			printRouteDetails(&route)
 		}
+		printNextOffset(collection)
 	}
 
 	return nil
@@ -237,57 +470,27 @@ func (ks *KongServerDomain) ListRoutes(options Options) error {
 
 func (ks *KongServerDomain) UpdateRoute(id string, updatedKongRoute *KongRoute, options Options) error {
 
-	var (
-		routeURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), routesResource, id)
-		payload  []byte
-		err      error
-	)
-
-	if len(updatedKongRoute.serviceId) == 0 {
-
-		payload, err = json.Marshal(KongRouteRequest{
-			Name:      updatedKongRoute.name,
-			Protocols: updatedKongRoute.protocols,
-			Methods:   updatedKongRoute.methods,
-			Paths:     updatedKongRoute.paths,
-		})
-	} else {
+	var routeURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), routesResource, id)
 
-		payload, err = json.Marshal(KongRouteRequest{
-			Name:      updatedKongRoute.name,
-			Protocols: updatedKongRoute.protocols,
-			Methods:   updatedKongRoute.methods,
-			Paths:     updatedKongRoute.paths,
-			Service: &serviceId{
-				Id: updatedKongRoute.serviceId,
-			},
-		})
-	}
+	payload, err := json.Marshal(routeRequest(updatedKongRoute))
 	if err != nil {
 		return err
 	}
 
 	//	log.Printf("[debug] patch payload: %s", payload)
 
-	req, err := http.NewRequest("PATCH", routeURL, bytes.NewBuffer([]byte(payload)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ks.sendRequest("PATCH", routeURL, payload)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return errors.New("route not found")
+		return newKongNotFoundError("route", resp)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.New("fail sending patch route command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending patch route command to Kong", resp)
 	}
 
 	//	parse response payload
@@ -320,6 +523,7 @@ func (ks *KongServerDomain) UpdateRoute(id string, updatedKongRoute *KongRoute,
 			fmt.Printf("route: %s - %s %s:%s --> Service Id: %s\n",
 				routeResp.Name, routeResp.Methods, routeResp.Protocols, routeResp.Paths, routeResp.Service.Id)
 		}
+		printRouteDetails(&routeResp)
 	}
 
 	return nil
@@ -331,29 +535,91 @@ func (ks *KongServerDomain) DeleteRoute(id string, options Options) error {
 	var routeURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), routesResource, id)
 
 	//	send a request to Kong to delete the route by id
-	req, err := http.NewRequest("DELETE", routeURL, bytes.NewBuffer([]byte("")))
+	resp, err := ks.sendRequest("DELETE", routeURL, nil)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newKongAPIError("fail sending delete route command to Kong", resp)
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n{}\n", resp.Status)
+	} else {
+		if options.verbose {
+			fmt.Printf("http response status code: %s\n", resp.Status)
+		}
+	}
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	return nil
+}
+
+// translate the paths, methods, hosts and other matching attributes of a traditional route into an expression
+func (ks *KongServerDomain) ConvertRouteToExpression(id string, options Options) error {
+
+	var routeURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), routesResource, id)
+
+	//	send a request to Kong to query the route by id
+	resp, err := ks.sendRequest("GET", routeURL, nil)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusNoContent {
-		return errors.New("fail sending delete route command to Kong: " + resp.Status)
+	if resp.StatusCode == http.StatusNotFound {
+		return newKongNotFoundError("route", resp)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return newKongAPIError("fail sending query route command to Kong", resp)
+	}
+
+	respPayload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var routeResp KongRouteResponse
+
+	err = json.Unmarshal(respPayload, &routeResp)
+	if err != nil {
+		return err
+	}
+
+	//	a route of the expressions router doesn't need to be converted
+	expression := routeResp.Expression
+	if len(expression) == 0 {
+		expression = routeExpression(&routeResp)
+		if len(expression) == 0 {
+			return errors.New("route " + id + " has no matching attributes to convert")
+		}
+
+		err = validateExpression(expression)
+		if err != nil {
+			return err
+		}
 	}
 
 	if options.jsonOutput {
-		fmt.Printf("%s\n{}\n", resp.Status)
+		payload, err := json.Marshal(struct {
+			Id         string `json:"id"`
+			Expression string `json:"expression"`
+		}{
+			Id:         routeResp.Id,
+			Expression: expression,
+		})
+		if err != nil {
+			return err
+		}
+
+		fmt.Printf("%s\n%s\n", resp.Status, string(payload))
 	} else {
 		if options.verbose {
-			fmt.Printf("http response status code: %s\n", resp.Status)
+			fmt.Printf("http response status code: %s\nroute %s expression:\n", resp.Status, routeResp.Id)
 		}
+		fmt.Printf("%s\n", expression)
 	}
 
 	return nil
@@ -7,7 +7,9 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
+	"fmt"
 	"net/http"
 	"net/http/httptest"
 	"testing"
@@ -99,6 +101,133 @@ func Test_AddRoute(t *testing.T) {
 			t.Errorf("failed checking kong status: success expected: result: %s", got.Error())
 		}
 	})
+
+	t.Run(">>> AddRoute: scenario 3 - route with matching and proxying attributes", func(t *testing.T) {
+
+		var request KongRouteRequest
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&request)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "0ee7a361-0ac0-4468-b7b9-fc041d9c8ed7"}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"add", "route", "--name=produtos-v2", "--hosts=api.example.com", "--headers=X-Version:v2",
+			"--headers=X-Tenant:a,b", "--snis=api.example.com", "--sources=10.0.0.0/8,192.168.0.1:8000", "--destinations=:443",
+			"--strip-path=false", "--preserve-host=true", "--regex-priority=10", "--path-handling=v1", "--https-redirect-status-code=308",
+			"--request-buffering=false", "--response-buffering=true", "--tags=v2"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed adding route: success expected: result: %s", got.Error())
+		}
+
+		if len(request.Hosts) != 1 || request.Headers["X-Version"][0] != "v2" || len(request.Headers["X-Tenant"]) != 2 || len(request.Snis) != 1 ||
+			request.Sources[1].IP != "192.168.0.1" || request.Sources[1].Port != 8000 || request.Destinations[0].Port != 443 ||
+			*request.StripPath || !*request.PreserveHost || *request.RegexPriority != 10 || request.PathHandling != "v1" ||
+			*request.HttpsRedirectStatusCode != 308 || *request.RequestBuffering || !*request.ResponseBuffering ||
+			len(request.Tags) != 1 || request.Service != nil {
+			t.Errorf("failed adding route: unexpected attributes in the request: %+v", request)
+		}
+	})
+
+	t.Run(">>> AddRoute: scenario 4 - invalid header", func(t *testing.T) {
+
+		want := errors.New("wrong value for option --headers: invalid header: X-Version: must be {header}:{values}")
+		got := kconf(NewKongServer("http://localhost:8001", 0), []string{"add", "route", "--headers=X-Version"}, Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed adding route: error expected: %v result: %v", want, got)
+		}
+	})
+	t.Run(">>> AddRoute: scenario 5 - route with an expression", func(t *testing.T) {
+
+		var request KongRouteRequest
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&request)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "0ee7a361-0ac0-4468-b7b9-fc041d9c8ed7"}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"add", "route", "--name=produtos-v2",
+			`--expression=http.path ^= "/produtos" && (http.method == "GET" || http.method == "POST")`, "--priority=100"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed adding route: success expected: result: %s", got.Error())
+		}
+
+		if request.Expression != `http.path ^= "/produtos" && (http.method == "GET" || http.method == "POST")` || *request.Priority != 100 {
+			t.Errorf("failed adding route: unexpected attributes in the request: %+v", request)
+		}
+	})
+
+	t.Run(">>> AddRoute: scenario 6 - invalid expression", func(t *testing.T) {
+
+		want := errors.New("wrong value for option --expression: invalid expression: operator ^= not allowed for field net.dst.port at position 14")
+		got := kconf(NewKongServer("http://localhost:8001", 0), []string{"add", "route", "--expression=net.dst.port ^= 80"}, Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed adding route: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> AddRoute: scenario 7 - expression with traditional matching attributes", func(t *testing.T) {
+
+		want := errors.New("option --expression can't be used with options --methods, --paths, --hosts, --headers, --snis, --sources, --destinations or --regex-priority")
+		got := kconf(NewKongServer("http://localhost:8001", 0), []string{"add", "route", `--expression=http.path == "/"`, "--paths=/"}, Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed adding route: error expected: %v result: %v", want, got)
+		}
+	})
+}
+
+// Test_parseRouteEndpoints unit tests for parseRouteEndpoints() function
+func Test_parseRouteEndpoints(t *testing.T) {
+
+	t.Run(">>> parseRouteEndpoints: scenario 1 - IPv4, IPv6 and ports", func(t *testing.T) {
+
+		endpoints, err := parseRouteEndpoints("10.0.0.1,10.0.0.0/24:80,:8443,[::1]:9000,fe80::/10")
+		if err != nil {
+			t.Fatalf("failed parsing endpoints: success expected: result: %s", err.Error())
+		}
+
+		want := "[10.0.0.1 10.0.0.0/24:80 :8443 [::1]:9000 [fe80::/10]]"
+		got := fmt.Sprintf("%s", endpoints)
+
+		if want != got {
+			t.Errorf("failed parsing endpoints: expected: %s result: %s", want, got)
+		}
+	})
+
+	t.Run(">>> parseRouteEndpoints: scenario 2 - invalid port", func(t *testing.T) {
+
+		_, err := parseRouteEndpoints("10.0.0.1:http")
+		if err == nil {
+			t.Errorf("failed parsing endpoints: error expected")
+		}
+	})
 }
 
 // Test_QueryRoute unit tests for QueryRoute() method
@@ -233,7 +362,7 @@ func Test_ListRoute(t *testing.T) {
 		}
 
 		want := errors.New("fail sending list route command to Kong: 500 Internal Server Error")
-		got := kongServer.ListRoutes(Options{
+		got := kongServer.ListRoutes("", nil, Options{
 			verbose:    false,
 			jsonOutput: false,
 		})
@@ -296,7 +425,7 @@ func Test_ListRoute(t *testing.T) {
 		}
 
 		var want error = nil
-		got := kongServer.ListRoutes(Options{
+		got := kongServer.ListRoutes("", nil, Options{
 			verbose:    false,
 			jsonOutput: false,
 		})
@@ -306,6 +435,36 @@ func Test_ListRoute(t *testing.T) {
 			t.Errorf("failed checking kong status: success expected: result: %s", got.Error())
 		}
 	})
+
+	t.Run(">>> ListRoute: scenario 3 - routes of a service", func(t *testing.T) {
+
+		var requestPath string
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestPath = r.URL.Path
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"data": [], "next": null}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"list", "route", "--service-id=Produtos"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed listing routes: success expected: result: %s", got.Error())
+		}
+
+		if requestPath != "/services/Produtos/routes" {
+			t.Errorf("failed listing routes: unexpected request path: %s", requestPath)
+		}
+	})
 }
 
 // Test_UpdateRoute unit tests for UpdateRoute() method
@@ -483,3 +642,62 @@ func Test_DeleteRoute(t *testing.T) {
 		}
 	})
 }
+
+// Test_ConvertRouteToExpression unit tests for ConvertRouteToExpression() method
+func Test_ConvertRouteToExpression(t *testing.T) {
+
+	t.Run(">>> ConvertRouteToExpression: scenario 1 - route not found", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("route not found")
+		got := kconf(kongServer, []string{"route", "convert-to-expression", "--id=1234"}, Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed converting route: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> ConvertRouteToExpression: scenario 2 - route converted successfuly", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"id": "0ee7a361-0ac0-4468-b7b9-fc041d9c8ed7",
+				"protocols": ["http", "https"],
+				"methods": ["GET"],
+				"paths": ["/produtos"],
+				"hosts": ["api.example.com"]
+			}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kongServer.ConvertRouteToExpression("0ee7a361-0ac0-4468-b7b9-fc041d9c8ed7", Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got != nil {
+			t.Errorf("failed converting route: success expected: result: %s", got.Error())
+		}
+	})
+}
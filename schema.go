@@ -0,0 +1,445 @@
+////////////////////////////////////////////////////////////////////////////////
+//	schema.go  -  Oct-17-2026  -  aldebap
+//
+//	Kong plugin schema and client-side plugin config validation
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"math"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// kong schema field attributes
+type KongSchemaField struct {
+	Type     string                       `json:"type"`
+	Required bool                         `json:"required"`
+	Default  interface{}                  `json:"default"`
+	OneOf    []interface{}                `json:"one_of"`
+	Between  []float64                    `json:"between"`
+	Gt       *float64                     `json:"gt"`
+	LenMin   *int                         `json:"len_min"`
+	LenMax   *int                         `json:"len_max"`
+	Elements *KongSchemaField             `json:"elements"`
+	Keys     *KongSchemaField             `json:"keys"`
+	Values   *KongSchemaField             `json:"values"`
+	Fields   []map[string]KongSchemaField `json:"fields"`
+}
+
+// kong schema response payload
+type KongSchema struct {
+	Fields []map[string]KongSchemaField `json:"fields"`
+}
+
+// kong node information response payload
+type KongNodeInfo struct {
+	Version string `json:"version"`
+}
+
+const (
+	pluginSchemasResource string = "schemas/plugins"
+)
+
+// error for a plugin Kong doesn't know about
+var errUnknownPlugin = errors.New("unknown plugin")
+
+// error for a plugin name that isn't a valid Kong plugin name
+var errInvalidPluginName = errors.New("invalid plugin name")
+
+// valid plugin names: the name is part of the schema URL and of the cache file path
+var pluginNameRegEx = regexp.MustCompile(`^[a-z0-9-]+$`)
+
+// characters not allowed in a cache directory name
+var schemaCacheNameRegEx = regexp.MustCompile(`[^\w.-]`)
+
+// the directory where plugin schemas are cached for a Kong version
+func pluginSchemaCacheDir(version string) (string, error) {
+
+	if len(version) == 0 {
+		return "", errors.New("unknown Kong version")
+	}
+
+	cacheDir, err := os.UserCacheDir()
+	if err != nil {
+		return "", err
+	}
+
+	return filepath.Join(cacheDir, "kconf", "schemas", schemaCacheNameRegEx.ReplaceAllString(version, "_"), "plugins"), nil
+}
+
+// get the version of Kong, fetched only once for each Kong server
+func (ks *KongServerDomain) kongVersion() string {
+
+	if ks.version == nil {
+		version := ks.fetchKongVersion()
+		ks.version = &version
+	}
+
+	return *ks.version
+}
+
+// fetch the version of Kong from the node information
+func (ks *KongServerDomain) fetchKongVersion() string {
+
+	resp, err := ks.sendRequest("GET", ks.adminURL(), nil)
+	if err != nil {
+		return ""
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return ""
+	}
+
+	respPayload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return ""
+	}
+
+	var nodeInfo KongNodeInfo
+
+	err = json.Unmarshal(respPayload, &nodeInfo)
+	if err != nil {
+		return ""
+	}
+
+	return nodeInfo.Version
+}
+
+// get a plugin schema from the disk cache or from Kong
+func (ks *KongServerDomain) fetchPluginSchema(name string) ([]byte, error) {
+
+	if !pluginNameRegEx.MatchString(name) {
+		return nil, fmt.Errorf("%w: %s", errInvalidPluginName, name)
+	}
+
+	cacheDir, cacheErr := pluginSchemaCacheDir(ks.kongVersion())
+	cacheFile := filepath.Join(cacheDir, name+".json")
+
+	if cacheErr == nil {
+		schemaPayload, err := os.ReadFile(cacheFile)
+		if err == nil {
+			return schemaPayload, nil
+		}
+	}
+
+	var schemaURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), pluginSchemasResource, url.PathEscape(name))
+
+	resp, err := ks.sendRequest("GET", schemaURL, nil)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return nil, fmt.Errorf("%w: %s", errUnknownPlugin, name)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, newKongAPIError("fail sending plugin schema command to Kong", resp)
+	}
+
+	schemaPayload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	//	the cache is best effort: a failure writing it only means the schema is fetched again next time
+	if cacheErr == nil && os.MkdirAll(cacheDir, 0755) == nil {
+		os.WriteFile(cacheFile, schemaPayload, 0644)
+	}
+
+	return schemaPayload, nil
+}
+
+// query a plugin schema by name
+func (ks *KongServerDomain) QueryPluginSchema(name string, options Options) error {
+
+	schemaPayload, err := ks.fetchPluginSchema(name)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n", string(schemaPayload))
+		return nil
+	}
+
+	var prettySchema bytes.Buffer
+
+	err = json.Indent(&prettySchema, schemaPayload, "", "    ")
+	if err != nil {
+		return err
+	}
+
+	fmt.Printf("%s\n", prettySchema.String())
+
+	return nil
+}
+
+// validate a plugin config against the plugin schema, returning the config with values converted to the schema types;
+// when the schema is not available validation is skipped and Kong remains responsible for validating the config
+func (ks *KongServerDomain) validatePluginConfig(name string, config interface{}, partial bool, options Options) (map[string]interface{}, error) {
+
+	//	typed plugin configs are converted to a generic config object; command line configs keep their literals
+	configMap, ok := config.(map[string]interface{})
+	if !ok {
+		payload, err := json.Marshal(config)
+		if err != nil {
+			return nil, err
+		}
+
+		err = json.Unmarshal(payload, &configMap)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	//	a missing plugin name is left for Kong to report
+	if len(name) == 0 {
+		return pluginConfigTyped(configMap), nil
+	}
+
+	schemaPayload, err := ks.fetchPluginSchema(name)
+	if err != nil {
+		if errors.Is(err, errUnknownPlugin) || errors.Is(err, errInvalidPluginName) {
+			return nil, err
+		}
+		if options.verbose {
+			fmt.Printf("plugin schema not available, skipping config validation: %s\n", err.Error())
+		}
+
+		return pluginConfigTyped(configMap), nil
+	}
+
+	var schema KongSchema
+
+	err = json.Unmarshal(schemaPayload, &schema)
+	if err != nil {
+		return nil, errors.New("invalid schema for plugin " + name + ": " + err.Error())
+	}
+
+	for _, field := range schema.Fields {
+		configField, exists := field["config"]
+		if !exists {
+			continue
+		}
+
+		var violations []string
+
+		if configMap == nil {
+			configMap = make(map[string]interface{})
+		}
+		validateSchemaRecord("config", configField.Fields, configMap, partial, &violations)
+
+		if len(violations) > 0 {
+			return nil, errors.New("invalid config for plugin " + name + ":\n  " + strings.Join(violations, "\n  "))
+		}
+	}
+
+	//	literals of fields without a known type are still inferred
+	return pluginConfigTyped(configMap), nil
+}
+
+// validate the attributes of a record against the schema fields, converting values to the schema types
+func validateSchemaRecord(path string, fields []map[string]KongSchemaField, record map[string]interface{}, partial bool, violations *[]string) {
+
+	known := make(map[string]bool)
+
+	for _, field := range fields {
+		for fieldName, fieldSchema := range field {
+			known[fieldName] = true
+			fieldPath := path + "." + fieldName
+
+			value, exists := record[fieldName]
+			if !exists || value == nil {
+				if partial || fieldSchema.Default != nil {
+					continue
+				}
+				if fieldSchema.Type == "record" && fieldSchema.Required && !exists {
+					//	Kong fills missing required records with their defaults, so only their required attributes matter
+					validateSchemaRecord(fieldPath, fieldSchema.Fields, map[string]interface{}{}, partial, violations)
+					continue
+				}
+				if fieldSchema.Required {
+					*violations = append(*violations, fieldPath+": required field missing")
+				}
+				continue
+			}
+
+			record[fieldName] = validateSchemaValue(fieldPath, fieldSchema, value, partial, violations)
+		}
+	}
+
+	for _, fieldName := range sortedFieldNames(record) {
+		if !known[fieldName] {
+			*violations = append(*violations, path+"."+fieldName+": unknown field")
+		}
+	}
+}
+
+// convert a command line literal to a schema type; a literal that doesn't convert is kept as a string, so the violation is reported
+func schemaLiteralValue(fieldType string, literal string) interface{} {
+
+	switch fieldType {
+	case "string", "record", "map":
+		return literal
+
+	case "number":
+		if number, err := strconv.ParseFloat(literal, 64); err == nil {
+			return number
+		}
+		return literal
+
+	case "integer":
+		if number, err := strconv.ParseInt(literal, 10, 64); err == nil {
+			return number
+		}
+		return literal
+
+	case "boolean":
+		switch literal {
+		case "true":
+			return true
+
+		case "false":
+			return false
+		}
+		return literal
+
+	case "array", "set":
+		//	a single literal is an array with one element, converted to the elements type
+		return pluginConfigLiteral(literal)
+	}
+
+	return pluginConfigScalar(literal)
+}
+
+// validate a value against the schema field, returning the value converted to the schema type
+func validateSchemaValue(path string, field KongSchemaField, value interface{}, partial bool, violations *[]string) interface{} {
+
+	//	command line values are converted from the literal typed by the user
+	if literal, ok := value.(pluginConfigLiteral); ok {
+		value = schemaLiteralValue(field.Type, string(literal))
+	}
+
+	switch field.Type {
+	case "string":
+		stringValue, ok := value.(string)
+		if !ok {
+			*violations = append(*violations, path+": expected a string")
+			return value
+		}
+
+		if field.LenMin != nil && len(stringValue) < *field.LenMin {
+			*violations = append(*violations, fmt.Sprintf("%s: length must be at least %d", path, *field.LenMin))
+		}
+		if field.LenMax != nil && len(stringValue) > *field.LenMax {
+			*violations = append(*violations, fmt.Sprintf("%s: length must be at most %d", path, *field.LenMax))
+		}
+
+	case "number", "integer":
+		var numberValue float64
+
+		switch number := value.(type) {
+		case int64:
+			numberValue = float64(number)
+		case float64:
+			numberValue = number
+		default:
+			*violations = append(*violations, path+": expected a "+field.Type)
+			return value
+		}
+
+		if field.Type == "integer" && numberValue != math.Trunc(numberValue) {
+			*violations = append(*violations, path+": expected an integer")
+		}
+		if len(field.Between) == 2 && (numberValue < field.Between[0] || numberValue > field.Between[1]) {
+			*violations = append(*violations, fmt.Sprintf("%s: value must be between %v and %v", path, field.Between[0], field.Between[1]))
+		}
+		if field.Gt != nil && numberValue <= *field.Gt {
+			*violations = append(*violations, fmt.Sprintf("%s: value must be greater than %v", path, *field.Gt))
+		}
+
+	case "boolean":
+		if _, ok := value.(bool); !ok {
+			*violations = append(*violations, path+": expected a boolean")
+		}
+
+	case "array", "set":
+		//	a single command line value is accepted as an array with one element
+		arrayValue, ok := value.([]interface{})
+		if !ok {
+			arrayValue = []interface{}{value}
+		}
+
+		if field.LenMin != nil && len(arrayValue) < *field.LenMin {
+			*violations = append(*violations, fmt.Sprintf("%s: must have at least %d elements", path, *field.LenMin))
+		}
+		if field.LenMax != nil && len(arrayValue) > *field.LenMax {
+			*violations = append(*violations, fmt.Sprintf("%s: must have at most %d elements", path, *field.LenMax))
+		}
+
+		if field.Elements != nil {
+			for i := range arrayValue {
+				arrayValue[i] = validateSchemaValue(fmt.Sprintf("%s[%d]", path, i), *field.Elements, arrayValue[i], partial, violations)
+			}
+		}
+
+		return arrayValue
+
+	case "map":
+		mapValue, ok := value.(map[string]interface{})
+		if !ok {
+			*violations = append(*violations, path+": expected a map")
+			return value
+		}
+
+		if field.Values != nil {
+			for key := range mapValue {
+				mapValue[key] = validateSchemaValue(path+"."+key, *field.Values, mapValue[key], partial, violations)
+			}
+		}
+
+	case "record":
+		recordValue, ok := value.(map[string]interface{})
+		if !ok {
+			*violations = append(*violations, path+": expected a record")
+			return value
+		}
+
+		validateSchemaRecord(path, field.Fields, recordValue, partial, violations)
+	}
+
+	if len(field.OneOf) > 0 {
+		var accepted []string
+		var matches bool
+
+		for _, option := range field.OneOf {
+			accepted = append(accepted, fmt.Sprintf("%v", option))
+			if fmt.Sprintf("%v", option) == fmt.Sprintf("%v", value) {
+				matches = true
+			}
+		}
+
+		if !matches {
+			sort.Strings(accepted)
+			*violations = append(*violations, fmt.Sprintf("%s: expected one of: %s", path, strings.Join(accepted, ", ")))
+		}
+	}
+
+	return value
+}
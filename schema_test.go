@@ -0,0 +1,159 @@
+////////////////////////////////////////////////////////////////////////////////
+//	schema_test.go  -  Oct-17-2026  -  aldebap
+//
+//	Test cases for Kong plugin schema validation
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+// rate-limiting plugin schema for the test cases
+const rateLimitingSchema string = `{
+	"fields": [
+		{ "protocols": { "type": "set", "elements": { "type": "string" } } },
+		{ "config": { "type": "record", "required": true, "fields": [
+			{ "minute": { "type": "number", "gt": 0 } },
+			{ "policy": { "type": "string", "default": "local", "one_of": [ "local", "cluster", "redis" ] } },
+			{ "error_code": { "type": "number", "default": 429, "between": [ 100, 599 ] } },
+			{ "header_name": { "type": "string", "required": true } },
+			{ "redis": { "type": "record", "fields": [
+				{ "host": { "type": "string" } },
+				{ "port": { "type": "integer", "between": [ 0, 65535 ] } }
+			] } }
+		] } }
+	]
+}`
+
+// Test_validatePluginConfig unit tests for validatePluginConfig() method
+func Test_validatePluginConfig(t *testing.T) {
+
+	var schemaRequests, versionRequests int
+
+	//	mock for Kong Admin
+	var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/":
+			versionRequests++
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{ "version": "3.7.1" }`))
+
+		case "/schemas/plugins/rate-limiting":
+			schemaRequests++
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(rateLimitingSchema))
+
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer mockKongAdmin.Close()
+
+	t.Setenv("XDG_CACHE_HOME", t.TempDir())
+
+	//	connect to mock server
+	kongServer := NewKongServer(mockKongAdmin.URL, 0).(*KongServerDomain)
+
+	t.Run(">>> validatePluginConfig: scenario 1 - valid config with values converted to schema types", func(t *testing.T) {
+
+		got, err := kongServer.validatePluginConfig("rate-limiting", map[string]interface{}{
+			"minute":      pluginConfigLiteral("10"),
+			"header_name": pluginConfigLiteral("01234"),
+			"redis":       map[string]interface{}{"host": pluginConfigLiteral("1000000000000000000000"), "port": pluginConfigLiteral("6379")},
+		}, false, Options{})
+		if err != nil {
+			t.Fatalf("failed validating plugin config: success expected: result: %s", err.Error())
+		}
+
+		if got["minute"] != float64(10) {
+			t.Errorf("failed validating plugin config: minute expected: 10 result: %v", got["minute"])
+		}
+
+		if got["header_name"] != "01234" {
+			t.Errorf("failed validating plugin config: header_name expected: \"01234\" result: %v", got["header_name"])
+		}
+
+		redis := got["redis"].(map[string]interface{})
+		if redis["host"] != "1000000000000000000000" || redis["port"] != int64(6379) {
+			t.Errorf("failed validating plugin config: redis host and port expected: result: %v", redis)
+		}
+	})
+
+	t.Run(">>> validatePluginConfig: scenario 2 - field path errors", func(t *testing.T) {
+
+		want := errors.New("invalid config for plugin rate-limiting:\n" +
+			"  config.minute: value must be greater than 0\n" +
+			"  config.policy: expected one of: cluster, local, redis\n" +
+			"  config.header_name: required field missing\n" +
+			"  config.redis.port: expected an integer\n" +
+			"  config.redis.port: value must be between 0 and 65535\n" +
+			"  config.second: unknown field")
+		_, got := kongServer.validatePluginConfig("rate-limiting", map[string]interface{}{
+			"minute": int64(0),
+			"policy": "memory",
+			"second": int64(1),
+			"redis":  map[string]interface{}{"port": 70000.5},
+		}, false, Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed validating plugin config: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> validatePluginConfig: scenario 3 - partial config for update", func(t *testing.T) {
+
+		_, err := kongServer.validatePluginConfig("rate-limiting", map[string]interface{}{"minute": int64(20)}, true, Options{})
+		if err != nil {
+			t.Errorf("failed validating plugin config: success expected: result: %s", err.Error())
+		}
+	})
+
+	t.Run(">>> validatePluginConfig: scenario 4 - schema cached per Kong version", func(t *testing.T) {
+
+		if schemaRequests != 1 {
+			t.Errorf("failed validating plugin config: single schema request expected: result: %d", schemaRequests)
+		}
+
+		cacheDir, _ := os.UserCacheDir()
+		if _, err := os.Stat(filepath.Join(cacheDir, "kconf", "schemas", "3.7.1", "plugins", "rate-limiting.json")); err != nil {
+			t.Errorf("failed validating plugin config: cached schema expected: %s", err.Error())
+		}
+	})
+
+	t.Run(">>> validatePluginConfig: scenario 5 - unknown plugin", func(t *testing.T) {
+
+		want := errors.New("unknown plugin: rate-limitng")
+		_, got := kongServer.validatePluginConfig("rate-limitng", nil, false, Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed validating plugin config: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> validatePluginConfig: scenario 6 - invalid plugin name", func(t *testing.T) {
+
+		want := errors.New("invalid plugin name: ../../rate-limiting")
+		_, got := kongServer.validatePluginConfig("../../rate-limiting", nil, false, Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed validating plugin config: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> validatePluginConfig: scenario 7 - Kong version fetched once", func(t *testing.T) {
+
+		if versionRequests != 1 {
+			t.Errorf("failed validating plugin config: single Kong version request expected: result: %d", versionRequests)
+		}
+	})
+}
@@ -7,9 +7,7 @@
 package main
 
 import (
-	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -17,9 +15,22 @@ import (
 
 // kong service attributes
 type KongService struct {
-	name    string
-	url     string
-	enabled bool
+	name              string
+	url               string
+	enabled           bool
+	protocol          string
+	host              string
+	port              *int
+	path              string
+	retries           *int
+	connectTimeout    *int
+	readTimeout       *int
+	writeTimeout      *int
+	tags              []string
+	caCertificates    []string
+	clientCertificate string
+	tlsVerify         *bool
+	tlsVerifyDepth    *int
 }
 
 // create a new Kong service
@@ -32,25 +43,77 @@ func NewKongService(name string, url string, enabled bool) *KongService {
 	}
 }
 
+// set the service address as separate attributes, instead of an URL
+func (service *KongService) setAddress(protocol string, host string, port *int, path string) {
+
+	service.protocol = protocol
+	service.host = host
+	service.port = port
+	service.path = path
+}
+
+// set the number of retries and the timeouts (in milliseconds) Kong uses to proxy to the service
+func (service *KongService) setTimeouts(retries *int, connectTimeout *int, readTimeout *int, writeTimeout *int) {
+
+	service.retries = retries
+	service.connectTimeout = connectTimeout
+	service.readTimeout = readTimeout
+	service.writeTimeout = writeTimeout
+}
+
+// set the service tags
+func (service *KongService) setTags(tags []string) {
+
+	service.tags = tags
+}
+
+// set the TLS attributes Kong uses to connect to the service
+func (service *KongService) setTLS(caCertificates []string, clientCertificate string, tlsVerify *bool, tlsVerifyDepth *int) {
+
+	service.caCertificates = caCertificates
+	service.clientCertificate = clientCertificate
+	service.tlsVerify = tlsVerify
+	service.tlsVerifyDepth = tlsVerifyDepth
+}
+
 // kong service request payload
 type KongServiceRequest struct {
-	Name    string `json:"name,omitempty"`
-	Url     string `json:"url,omitempty"`
-	Enabled bool   `json:"enabled"`
+	Name              string         `json:"name,omitempty"`
+	Url               string         `json:"url,omitempty"`
+	Enabled           bool           `json:"enabled"`
+	Protocol          string         `json:"protocol,omitempty"`
+	Host              string         `json:"host,omitempty"`
+	Port              *int           `json:"port,omitempty"`
+	Path              string         `json:"path,omitempty"`
+	Retries           *int           `json:"retries,omitempty"`
+	ConnectTimeout    *int           `json:"connect_timeout,omitempty"`
+	ReadTimeout       *int           `json:"read_timeout,omitempty"`
+	WriteTimeout      *int           `json:"write_timeout,omitempty"`
+	Tags              []string       `json:"tags,omitempty"`
+	CACertificates    []string       `json:"ca_certificates,omitempty"`
+	ClientCertificate *certificateId `json:"client_certificate,omitempty"`
+	TLSVerify         *bool          `json:"tls_verify,omitempty"`
+	TLSVerifyDepth    *int           `json:"tls_verify_depth,omitempty"`
 }
 
 // kong service response payload
 type KongServiceResponse struct {
-	Id                 string `json:"id"`
-	Name               string `json:"name"`
-	Protocol           string `json:"protocol"`
-	Port               int    `json:"port"`
-	Host               string `json:"host"`
-	Path               string `json:"path"`
-	CACertificates     string `json:"ca_certificates"`
-	ClientCertificates string `json:"client_certificates"`
-	Tags               string `json:"tags"`
-	Enabled            bool   `json:"enabled"`
+	Id                string         `json:"id"`
+	Name              string         `json:"name"`
+	Protocol          string         `json:"protocol"`
+	Port              int            `json:"port"`
+	Host              string         `json:"host"`
+	Path              string         `json:"path"`
+	Retries           int            `json:"retries"`
+	ConnectTimeout    int            `json:"connect_timeout"`
+	ReadTimeout       int            `json:"read_timeout"`
+	WriteTimeout      int            `json:"write_timeout"`
+	CACertificates    []string       `json:"ca_certificates"`
+	ClientCertificate *certificateId `json:"client_certificate"`
+	TLSVerify         *bool          `json:"tls_verify"`
+	TLSVerifyDepth    *int           `json:"tls_verify_depth"`
+	Tags              []string       `json:"tags"`
+	Enabled           bool           `json:"enabled"`
 }
 
 // kong service list response payload
@@ -61,37 +124,100 @@ type KongServiceListResponse struct {
 
 const (
 	servicesResource string = "services"
+
+	maxPort           int = 65535
+	maxServiceRetries int = 32767
+	maxServiceTimeout int = 2147483646
+	maxTLSVerifyDepth int = 64
 )
 
+// build the service request payload, resolving the client certificate id
+func (ks *KongServerDomain) serviceRequest(kongService *KongService) (*KongServiceRequest, error) {
+
+	serviceRequest := &KongServiceRequest{
+		Name:           kongService.name,
+		Url:            kongService.url,
+		Enabled:        kongService.enabled,
+		Protocol:       kongService.protocol,
+		Host:           kongService.host,
+		Port:           kongService.port,
+		Path:           kongService.path,
+		Retries:        kongService.retries,
+		ConnectTimeout: kongService.connectTimeout,
+		ReadTimeout:    kongService.readTimeout,
+		WriteTimeout:   kongService.writeTimeout,
+		Tags:           kongService.tags,
+		CACertificates: kongService.caCertificates,
+		TLSVerify:      kongService.tlsVerify,
+		TLSVerifyDepth: kongService.tlsVerifyDepth,
+	}
+
+	if len(kongService.clientCertificate) > 0 {
+		certId, err := ks.resolveCertificateId(kongService.clientCertificate)
+		if err != nil {
+			return nil, err
+		}
+
+		serviceRequest.ClientCertificate = &certificateId{Id: certId}
+	}
+
+	return serviceRequest, nil
+}
+
+// print the service attributes other than its address
+func printServiceDetails(service *KongServiceResponse) {
+
+	fmt.Printf("    retries: %d ; timeouts (connect/read/write): %d/%d/%d ms ; enabled: %t ; tags: %s\n",
+		service.Retries, service.ConnectTimeout, service.ReadTimeout, service.WriteTimeout, service.Enabled, service.Tags)
+	printServiceTLS(service)
+}
+
+// print the TLS attributes of a service, when there are any
+func printServiceTLS(service *KongServiceResponse) {
+
+	if len(service.CACertificates) == 0 && service.ClientCertificate == nil && service.TLSVerify == nil && service.TLSVerifyDepth == nil {
+		return
+	}
+
+	var clientCertificate, tlsVerify, tlsVerifyDepth string = "-", "-", "-"
+
+	if service.ClientCertificate != nil {
+		clientCertificate = service.ClientCertificate.Id
+	}
+	if service.TLSVerify != nil {
+		tlsVerify = fmt.Sprintf("%t", *service.TLSVerify)
+	}
+	if service.TLSVerifyDepth != nil {
+		tlsVerifyDepth = fmt.Sprintf("%d", *service.TLSVerifyDepth)
+	}
+
+	fmt.Printf("    tls: verify: %s ; verify depth: %s ; ca certificates: %s ; client certificate: %s\n",
+		tlsVerify, tlsVerifyDepth, service.CACertificates, clientCertificate)
+}
+
 // add a new service to Kong
 func (ks *KongServerDomain) AddService(newKongService *KongService, options Options) error {
 
 	var serviceURL string = fmt.Sprintf("%s/%s", ks.ServerURL(), servicesResource)
 
-	payload, err := json.Marshal(KongServiceRequest{
-		Name:    newKongService.name,
-		Url:     newKongService.url,
-		Enabled: newKongService.enabled,
-	})
+	serviceRequest, err := ks.serviceRequest(newKongService)
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequest("POST", serviceURL, bytes.NewBuffer([]byte(payload)))
+	payload, err := json.Marshal(serviceRequest)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ks.sendRequest("POST", serviceURL, payload)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		return errors.New("fail sending add service command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending add service command to Kong", resp)
 	}
 
 	//	parse response payload
@@ -128,17 +254,17 @@ func (ks *KongServerDomain) QueryService(id string, options Options) error {
 	var serviceURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), servicesResource, id)
 
 	//	send a request to Kong to query the service by id
-	resp, err := http.Get(serviceURL)
+	resp, err := ks.sendRequest("GET", serviceURL, nil)
 	if err != nil {
 		return err
 	}
 
 	if resp.StatusCode == http.StatusNotFound {
-		return errors.New("service not found")
+		return newKongNotFoundError("service", resp)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.New("fail sending query service command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending query service command to Kong", resp)
 	}
 
 	var respPayload []byte
@@ -165,35 +291,28 @@ func (ks *KongServerDomain) QueryService(id string, options Options) error {
 			fmt.Printf("service: %s --> %s://%s:%d%s\n",
 				serviceResp.Name, serviceResp.Protocol, serviceResp.Host, serviceResp.Port, serviceResp.Path)
 		}
+		printServiceDetails(&serviceResp)
 	}
 
 	return nil
 }
 
 // list all services
-func (ks *KongServerDomain) ListServices(options Options) error {
-
-	var serviceURL string = fmt.Sprintf("%s/%s/", ks.ServerURL(), servicesResource)
+func (ks *KongServerDomain) ListServices(pagination *KongPagination, options Options) error {
 
 	//	send a request to Kong to get a list of all services
-	resp, err := http.Get(serviceURL)
+	collection, status, err := ks.fetchCollection(servicesResource, pagination, "fail sending list service command to Kong")
 	if err != nil {
 		return err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return errors.New("fail sending list service command to Kong: " + resp.Status)
-	}
-
-	var respPayload []byte
-
-	respPayload, err = io.ReadAll(resp.Body)
+	respPayload, err := json.Marshal(collection)
 	if err != nil {
 		return err
 	}
 
 	if options.jsonOutput {
-		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+		fmt.Printf("%s\n%s\n", status, string(respPayload))
 	} else {
 		var serviceListResp KongServiceListResponse
 
@@ -204,7 +323,7 @@ func (ks *KongServerDomain) ListServices(options Options) error {
 
 		if len(serviceListResp.Data) == 0 {
 			if options.verbose {
-				fmt.Printf("%s\nNo services\n", resp.Status)
+				fmt.Printf("%s\nNo services\n", status)
 			} else {
 				fmt.Printf("No services\n")
 			}
@@ -213,13 +332,14 @@ func (ks *KongServerDomain) ListServices(options Options) error {
 		}
 
 		if options.verbose {
-			fmt.Printf("http response status code: %s\nservice list\n", resp.Status)
+			fmt.Printf("http response status code: %s\nservice list\n", status)
 		}
 
 		for _, service := range serviceListResp.Data {
-			fmt.Printf("%s: %s --> %s://%s:%d%s\n", service.Id, service.Name,
-				service.Protocol, service.Host, service.Port, service.Path)
+			fmt.Printf("%s: %s --> %s://%s:%d%s (%s)\n", service.Id, service.Name,
+				service.Protocol, service.Host, service.Port, service.Path, service.Tags)
 		}
+		printNextOffset(collection)
 	}
 
 	return nil
@@ -230,36 +350,30 @@ func (ks *KongServerDomain) UpdateService(id string, updatedKongService *KongSer
 
 	var serviceURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), servicesResource, id)
 
-	payload, err := json.Marshal(KongServiceRequest{
-		Name:    updatedKongService.name,
-		Url:     updatedKongService.url,
-		Enabled: updatedKongService.enabled,
-	})
+	serviceRequest, err := ks.serviceRequest(updatedKongService)
 	if err != nil {
 		return err
 	}
 
-	//	log.Printf("[debug] patch payload: %s", payload)
-
-	req, err := http.NewRequest("PATCH", serviceURL, bytes.NewBuffer([]byte(payload)))
+	payload, err := json.Marshal(serviceRequest)
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	//	log.Printf("[debug] patch payload: %s", payload)
+
+	resp, err := ks.sendRequest("PATCH", serviceURL, payload)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return errors.New("service not found")
+		return newKongNotFoundError("service", resp)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.New("fail sending patch service command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending patch service command to Kong", resp)
 	}
 
 	//	parse response payload
@@ -292,6 +406,7 @@ func (ks *KongServerDomain) UpdateService(id string, updatedKongService *KongSer
 			fmt.Printf("service: %s --> %s://%s:%d%s\n",
 				serviceResp.Name, serviceResp.Protocol, serviceResp.Host, serviceResp.Port, serviceResp.Path)
 		}
+		printServiceDetails(&serviceResp)
 	}
 
 	return nil
@@ -303,21 +418,14 @@ func (ks *KongServerDomain) DeleteService(id string, options Options) error {
 	var serviceURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), servicesResource, id)
 
 	//	send a request to Kong to delete the service by id
-	req, err := http.NewRequest("DELETE", serviceURL, bytes.NewBuffer([]byte("")))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ks.sendRequest("DELETE", serviceURL, nil)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		return errors.New("fail sending delete service command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending delete service command to Kong", resp)
 	}
 
 	if options.jsonOutput {
@@ -7,6 +7,7 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -87,6 +88,100 @@ func Test_AddService(t *testing.T) {
 			t.Errorf("failed checking kong status: success expected: result: %s", got.Error())
 		}
 	})
+
+	t.Run(">>> AddService: scenario 3 - service with upstream TLS verification", func(t *testing.T) {
+
+		var request KongServiceRequest
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == "GET" {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"id": "7d3a8c2e-2f1b-4b1e-9a55-2a0c1a3d4f10"}`))
+				return
+			}
+
+			json.NewDecoder(r.Body).Decode(&request)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "1343894e-404a-4f9e-a982-9e5c0e9d1733", "name": "Produtos"}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"add", "service", "--name=Produtos", "--url=https://produtos.internal:8443",
+			"--ca-certificates=5b1c0f8e-6a4d-4e2b-9c3f-7d8e9f0a1b2c", "--client-certificate=client.example.com",
+			"--tls-verify=true", "--tls-verify-depth=2"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed adding service: success expected: result: %s", got.Error())
+		}
+
+		if len(request.CACertificates) != 1 || request.ClientCertificate == nil || request.ClientCertificate.Id != "7d3a8c2e-2f1b-4b1e-9a55-2a0c1a3d4f10" ||
+			request.TLSVerify == nil || !*request.TLSVerify || request.TLSVerifyDepth == nil || *request.TLSVerifyDepth != 2 {
+			t.Errorf("failed adding service: unexpected TLS attributes in the request: %+v", request)
+		}
+	})
+
+	t.Run(">>> AddService: scenario 4 - invalid TLS verify depth", func(t *testing.T) {
+
+		want := errors.New("wrong value for option --tls-verify-depth: 65: must be between 0 and 64")
+		got := kconf(NewKongServer("http://localhost:8001", 0), []string{"add", "service", "--name=Produtos", "--tls-verify-depth=65"}, Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed adding service: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> AddService: scenario 5 - service with address attributes, retries, timeouts and tags", func(t *testing.T) {
+
+		var request KongServiceRequest
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&request)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "1343894e-404a-4f9e-a982-9e5c0e9d1733", "name": "Produtos"}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"add", "service", "--name=Produtos", "--protocol=https", "--host=produtos.internal", "--port=8443",
+			"--path=/api/v1", "--retries=0", "--connect-timeout=5000", "--read-timeout=30000", "--write-timeout=30000", "--tags=catalog,v1"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed adding service: success expected: result: %s", got.Error())
+		}
+
+		if request.Protocol != "https" || request.Host != "produtos.internal" || request.Port == nil || *request.Port != 8443 || request.Path != "/api/v1" ||
+			request.Retries == nil || *request.Retries != 0 || *request.ConnectTimeout != 5000 || *request.ReadTimeout != 30000 ||
+			*request.WriteTimeout != 30000 || len(request.Tags) != 2 || len(request.Url) != 0 {
+			t.Errorf("failed adding service: unexpected attributes in the request: %+v", request)
+		}
+	})
+
+	t.Run(">>> AddService: scenario 6 - URL with address attributes", func(t *testing.T) {
+
+		want := errors.New("option --url can't be used with options --protocol, --host, --port or --path")
+		got := kconf(NewKongServer("http://localhost:8001", 0), []string{"add", "service", "--url=http://produtos:8080", "--port=8081"}, Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed adding service: error expected: %v result: %v", want, got)
+		}
+	})
 }
 
 // Test_QueryService unit tests for QueryService() method
@@ -209,7 +304,7 @@ func Test_ListService(t *testing.T) {
 		}
 
 		want := errors.New("fail sending list service command to Kong: 500 Internal Server Error")
-		got := kongServer.ListServices(Options{
+		got := kongServer.ListServices(nil, Options{
 			verbose:    false,
 			jsonOutput: false,
 		})
@@ -260,7 +355,7 @@ func Test_ListService(t *testing.T) {
 		}
 
 		var want error = nil
-		got := kongServer.ListServices(Options{
+		got := kongServer.ListServices(nil, Options{
 			verbose:    false,
 			jsonOutput: false,
 		})
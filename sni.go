@@ -0,0 +1,365 @@
+////////////////////////////////////////////////////////////////////////////////
+//	sni.go  -  Oct-17-2026  -  aldebap
+//
+//	Kong SNI configuration
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+)
+
+// kong SNI attributes
+type KongSNI struct {
+	name          string
+	certificateId string
+	tags          []string
+}
+
+// create a new Kong SNI
+func NewKongSNI(name string, certificateId string, tags []string) *KongSNI {
+
+	return &KongSNI{
+		name:          name,
+		certificateId: certificateId,
+		tags:          tags,
+	}
+}
+
+// kong reference to the certificate of a SNI
+type certificateId struct {
+	Id string `json:"id"`
+}
+
+// kong SNI request payload
+type KongSNIRequest struct {
+	Name        string         `json:"name,omitempty"`
+	Certificate *certificateId `json:"certificate,omitempty"`
+	Tags        []string       `json:"tags,omitempty"`
+}
+
+// kong SNI response payload
+type KongSNIResponse struct {
+	Id          string        `json:"id"`
+	Name        string        `json:"name"`
+	Certificate certificateId `json:"certificate"`
+	Tags        []string      `json:"tags"`
+	CreatedAt   uint64        `json:"created_at"`
+}
+
+// kong SNI list response payload
+type KongSNIListResponse struct {
+	Data []KongSNIResponse `json:"data"`
+	Next string            `json:"next"`
+}
+
+const (
+	snisResource string = "snis"
+)
+
+// resolve the certificate id: Kong also finds a certificate by the name of one of its SNIs
+func (ks *KongServerDomain) resolveCertificateId(idOrName string) (string, error) {
+
+	var certificateURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), certificatesResource, idOrName)
+
+	resp, err := ks.sendRequest("GET", certificateURL, nil)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return "", newKongNotFoundError("certificate", resp)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return "", newKongAPIError("fail sending query certificate command to Kong", resp)
+	}
+
+	respPayload, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+
+	var certificateResp KongCertificateResponse
+
+	err = json.Unmarshal(respPayload, &certificateResp)
+	if err != nil {
+		return "", err
+	}
+
+	return certificateResp.Id, nil
+}
+
+// add a new SNI to a certificate in Kong
+func (ks *KongServerDomain) AddSNI(newKongSNI *KongSNI, options Options) error {
+
+	certId, err := ks.resolveCertificateId(newKongSNI.certificateId)
+	if err != nil {
+		return err
+	}
+
+	var sniURL string = fmt.Sprintf("%s/%s/%s/%s", ks.ServerURL(), certificatesResource, certId, snisResource)
+
+	payload, err := json.Marshal(KongSNIRequest{
+		Name: newKongSNI.name,
+		Tags: newKongSNI.tags,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := ks.sendRequest("POST", sniURL, payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return newKongAPIError("fail sending add sni command to Kong", resp)
+	}
+
+	//	parse response payload
+	var respPayload []byte
+
+	respPayload, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var sniResp KongSNIResponse
+
+	err = json.Unmarshal(respPayload, &sniResp)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+	} else {
+		if options.verbose {
+			fmt.Printf("http response status code: %s\nnew sni ID: %s\n", resp.Status, sniResp.Id)
+		} else {
+			fmt.Printf("%s\n", sniResp.Id)
+		}
+	}
+
+	return nil
+}
+
+// query a SNI by Id or name
+func (ks *KongServerDomain) QuerySNI(id string, options Options) error {
+
+	var sniURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), snisResource, id)
+
+	//	send a request to Kong to query the SNI by id
+	resp, err := ks.sendRequest("GET", sniURL, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return newKongNotFoundError("sni", resp)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return newKongAPIError("fail sending query sni command to Kong", resp)
+	}
+
+	var respPayload []byte
+
+	respPayload, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+	} else {
+		var sniResp KongSNIResponse
+
+		err = json.Unmarshal(respPayload, &sniResp)
+		if err != nil {
+			return err
+		}
+
+		if options.verbose {
+			fmt.Printf("http response status code: %s\n", resp.Status)
+		}
+		fmt.Printf("%s: %s --> certificate: %s (%s)\n", sniResp.Id, sniResp.Name, sniResp.Certificate.Id, sniResp.Tags)
+	}
+
+	return nil
+}
+
+// list all SNIs grouped by certificate, or only the SNIs of a certificate
+func (ks *KongServerDomain) ListSNIs(certificateIdOrName string, pagination *KongPagination, options Options) error {
+
+	var resource string = snisResource
+
+	if len(certificateIdOrName) > 0 {
+		certId, err := ks.resolveCertificateId(certificateIdOrName)
+		if err != nil {
+			return err
+		}
+
+		resource = fmt.Sprintf("%s/%s/%s", certificatesResource, certId, snisResource)
+	}
+
+	//	send a request to Kong to get a list of all SNIs
+	collection, status, err := ks.fetchCollection(resource, pagination, "fail sending list snis command to Kong")
+	if err != nil {
+		return err
+	}
+
+	respPayload, err := json.Marshal(collection)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", status, string(respPayload))
+	} else {
+		var sniListResp KongSNIListResponse
+
+		err = json.Unmarshal(respPayload, &sniListResp)
+		if err != nil {
+			return err
+		}
+
+		if len(sniListResp.Data) == 0 {
+			if options.verbose {
+				fmt.Printf("%s\nNo snis\n", status)
+			} else {
+				fmt.Printf("No snis\n")
+			}
+
+			return nil
+		}
+
+		if options.verbose {
+			fmt.Printf("http response status code: %s\nsni list\n", status)
+		}
+
+		//	group the SNIs under their certificates
+		var certificates []string
+		snisByCertificate := make(map[string][]KongSNIResponse)
+
+		for _, sni := range sniListResp.Data {
+			if _, ok := snisByCertificate[sni.Certificate.Id]; !ok {
+				certificates = append(certificates, sni.Certificate.Id)
+			}
+			snisByCertificate[sni.Certificate.Id] = append(snisByCertificate[sni.Certificate.Id], sni)
+		}
+		sort.Strings(certificates)
+
+		for _, certificate := range certificates {
+			fmt.Printf("certificate: %s\n", certificate)
+			for _, sni := range snisByCertificate[certificate] {
+				fmt.Printf("    %s: %s (%s)\n", sni.Id, sni.Name, sni.Tags)
+			}
+		}
+		printNextOffset(collection)
+	}
+
+	return nil
+}
+
+// update a SNI in Kong
+func (ks *KongServerDomain) UpdateSNI(id string, updatedKongSNI *KongSNI, options Options) error {
+
+	var sniURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), snisResource, id)
+
+	sniRequest := KongSNIRequest{
+		Name: updatedKongSNI.name,
+		Tags: updatedKongSNI.tags,
+	}
+
+	//	move the SNI to another certificate
+	if len(updatedKongSNI.certificateId) > 0 {
+		certId, err := ks.resolveCertificateId(updatedKongSNI.certificateId)
+		if err != nil {
+			return err
+		}
+
+		sniRequest.Certificate = &certificateId{Id: certId}
+	}
+
+	payload, err := json.Marshal(sniRequest)
+	if err != nil {
+		return err
+	}
+
+	resp, err := ks.sendRequest("PATCH", sniURL, payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return newKongNotFoundError("sni", resp)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return newKongAPIError("fail sending patch sni command to Kong", resp)
+	}
+
+	//	parse response payload
+	var respPayload []byte
+
+	respPayload, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var sniResp KongSNIResponse
+
+	err = json.Unmarshal(respPayload, &sniResp)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+	} else {
+		if options.verbose {
+			fmt.Printf("http response status code: %s\n", resp.Status)
+		}
+		fmt.Printf("%s: %s --> certificate: %s (%s)\n", sniResp.Id, sniResp.Name, sniResp.Certificate.Id, sniResp.Tags)
+	}
+
+	return nil
+}
+
+// delete a SNI in Kong
+func (ks *KongServerDomain) DeleteSNI(id string, options Options) error {
+
+	var sniURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), snisResource, id)
+
+	//	send a request to Kong to delete the SNI by id
+	resp, err := ks.sendRequest("DELETE", sniURL, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newKongAPIError("fail sending delete sni command to Kong", resp)
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n{}\n", resp.Status)
+	} else {
+		if options.verbose {
+			fmt.Printf("http response status code: %s\n", resp.Status)
+		}
+	}
+
+	return nil
+}
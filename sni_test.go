@@ -0,0 +1,205 @@
+////////////////////////////////////////////////////////////////////////////////
+//	sni_test.go  -  Oct-17-2026  -  aldebap
+//
+//	Test cases for Kong SNI Configuration
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+// Test_AddSNI unit tests for AddSNI() method
+func Test_AddSNI(t *testing.T) {
+
+	t.Run(">>> AddSNI: scenario 1 - certificate not found", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("certificate not found")
+		got := kongServer.AddSNI(NewKongSNI("api.example.com", "7d3a8c2e-2f1b-4b1e-9a55-2a0c1a3d4f10", nil), Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed adding sni: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> AddSNI: scenario 2 - sni created with certificate resolved by name", func(t *testing.T) {
+
+		var sniPath string
+		var request KongSNIRequest
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			switch r.Method {
+			case "GET":
+				if r.URL.Path != "/certificates/www.example.com" {
+					w.WriteHeader(http.StatusNotFound)
+					return
+				}
+
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"id": "7d3a8c2e-2f1b-4b1e-9a55-2a0c1a3d4f10"}`))
+
+			case "POST":
+				sniPath = r.URL.Path
+				json.NewDecoder(r.Body).Decode(&request)
+
+				w.WriteHeader(http.StatusCreated)
+				w.Write([]byte(`{
+					"id": "3c5f2a1e-8d7b-4c6a-9e0f-1a2b3c4d5e6f",
+					"name": "api.example.com",
+					"certificate": {"id": "7d3a8c2e-2f1b-4b1e-9a55-2a0c1a3d4f10"}
+				}`))
+			}
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"add", "sni", "--name=api.example.com", "--certificate-id=www.example.com"}, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed adding sni: success expected: result: %s", got.Error())
+		}
+
+		if sniPath != "/certificates/7d3a8c2e-2f1b-4b1e-9a55-2a0c1a3d4f10/snis" || request.Name != "api.example.com" {
+			t.Errorf("failed adding sni: unexpected request: %s %+v", sniPath, request)
+		}
+	})
+}
+
+// Test_ListSNIs unit tests for ListSNIs() method
+func Test_ListSNIs(t *testing.T) {
+
+	t.Run(">>> ListSNIs: scenario 1 - snis of a certificate", func(t *testing.T) {
+
+		var listPath string
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.URL.Path == "/certificates/7d3a8c2e-2f1b-4b1e-9a55-2a0c1a3d4f10" {
+				w.WriteHeader(http.StatusOK)
+				w.Write([]byte(`{"id": "7d3a8c2e-2f1b-4b1e-9a55-2a0c1a3d4f10"}`))
+				return
+			}
+
+			listPath = r.URL.Path
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"data": [
+					{"id": "3c5f2a1e-8d7b-4c6a-9e0f-1a2b3c4d5e6f", "name": "api.example.com", "certificate": {"id": "7d3a8c2e-2f1b-4b1e-9a55-2a0c1a3d4f10"}},
+					{"id": "4d6a3b2f-9e8c-4d7b-8f1a-2b3c4d5e6f7a", "name": "www.example.com", "certificate": {"id": "7d3a8c2e-2f1b-4b1e-9a55-2a0c1a3d4f10"}}
+				],
+				"next": null
+			}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kongServer.ListSNIs("7d3a8c2e-2f1b-4b1e-9a55-2a0c1a3d4f10", nil, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed listing snis: success expected: result: %s", got.Error())
+		}
+
+		if listPath != "/certificates/7d3a8c2e-2f1b-4b1e-9a55-2a0c1a3d4f10/snis" {
+			t.Errorf("failed listing snis: nested certificate resource expected: result: %s", listPath)
+		}
+	})
+}
+
+// Test_UpdateSNI unit tests for UpdateSNI() method
+func Test_UpdateSNI(t *testing.T) {
+
+	t.Run(">>> UpdateSNI: scenario 1 - sni not found", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("sni not found")
+		got := kongServer.UpdateSNI("api.example.com", NewKongSNI("", "", []string{"edge"}), Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed updating sni: error expected: %v result: %v", want, got)
+		}
+	})
+}
+
+// Test_DeleteSNI unit tests for DeleteSNI() method
+func Test_DeleteSNI(t *testing.T) {
+
+	t.Run(">>> DeleteSNI: scenario 1 - sni deleted", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kongServer.DeleteSNI("api.example.com", Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got != nil {
+			t.Errorf("failed deleting sni: success expected: result: %s", got.Error())
+		}
+	})
+}
@@ -0,0 +1,1310 @@
+////////////////////////////////////////////////////////////////////////////////
+//	state.go  -  Oct-17-2026  -  aldebap
+//
+//	Kong declarative state
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kong declarative state
+type KongState struct {
+	Services  []KongStateService  `json:"services,omitempty" yaml:"services,omitempty"`
+	Routes    []KongStateRoute    `json:"routes,omitempty" yaml:"routes,omitempty"`
+	Consumers []KongStateConsumer `json:"consumers,omitempty" yaml:"consumers,omitempty"`
+	Plugins   []KongStatePlugin   `json:"plugins,omitempty" yaml:"plugins,omitempty"`
+	Upstreams []KongStateUpstream `json:"upstreams,omitempty" yaml:"upstreams,omitempty"`
+
+	//	consumer groups aren't managed by the state, but plugins are scoped to them by name
+	consumerGroupIds map[string]string
+
+	//	attributes a dumped state can't reproduce when it's applied
+	warnings []string
+}
+
+// kong service state
+type KongStateService struct {
+	id             string
+	Name           string   `json:"name" yaml:"name"`
+	Url            string   `json:"url,omitempty" yaml:"url,omitempty"`
+	Protocol       string   `json:"protocol,omitempty" yaml:"protocol,omitempty"`
+	Host           string   `json:"host,omitempty" yaml:"host,omitempty"`
+	Port           int      `json:"port,omitempty" yaml:"port,omitempty"`
+	Path           string   `json:"path,omitempty" yaml:"path,omitempty"`
+	Retries        *int     `json:"retries,omitempty" yaml:"retries,omitempty"`
+	ConnectTimeout *int     `json:"connect_timeout,omitempty" yaml:"connect_timeout,omitempty"`
+	ReadTimeout    *int     `json:"read_timeout,omitempty" yaml:"read_timeout,omitempty"`
+	WriteTimeout   *int     `json:"write_timeout,omitempty" yaml:"write_timeout,omitempty"`
+	Enabled        *bool    `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Tags           []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// kong route state
+type KongStateRoute struct {
+	id                      string
+	Name                    string              `json:"name" yaml:"name"`
+	Service                 string              `json:"service,omitempty" yaml:"service,omitempty"`
+	Protocols               []string            `json:"protocols,omitempty" yaml:"protocols,omitempty"`
+	Methods                 []string            `json:"methods,omitempty" yaml:"methods,omitempty"`
+	Paths                   []string            `json:"paths,omitempty" yaml:"paths,omitempty"`
+	Hosts                   []string            `json:"hosts,omitempty" yaml:"hosts,omitempty"`
+	Headers                 map[string][]string `json:"headers,omitempty" yaml:"headers,omitempty"`
+	Snis                    []string            `json:"snis,omitempty" yaml:"snis,omitempty"`
+	Sources                 []KongRouteEndpoint `json:"sources,omitempty" yaml:"sources,omitempty"`
+	Destinations            []KongRouteEndpoint `json:"destinations,omitempty" yaml:"destinations,omitempty"`
+	StripPath               *bool               `json:"strip_path,omitempty" yaml:"strip_path,omitempty"`
+	PreserveHost            *bool               `json:"preserve_host,omitempty" yaml:"preserve_host,omitempty"`
+	RegexPriority           *int                `json:"regex_priority,omitempty" yaml:"regex_priority,omitempty"`
+	PathHandling            string              `json:"path_handling,omitempty" yaml:"path_handling,omitempty"`
+	HttpsRedirectStatusCode int                 `json:"https_redirect_status_code,omitempty" yaml:"https_redirect_status_code,omitempty"`
+	RequestBuffering        *bool               `json:"request_buffering,omitempty" yaml:"request_buffering,omitempty"`
+	ResponseBuffering       *bool               `json:"response_buffering,omitempty" yaml:"response_buffering,omitempty"`
+	Expression              string              `json:"expression,omitempty" yaml:"expression,omitempty"`
+	Priority                *int64              `json:"priority,omitempty" yaml:"priority,omitempty"`
+	Tags                    []string            `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// kong consumer state
+type KongStateConsumer struct {
+	id        string
+	UserName  string               `json:"username,omitempty" yaml:"username,omitempty"`
+	CustomId  string               `json:"custom_id,omitempty" yaml:"custom_id,omitempty"`
+	Tags      []string             `json:"tags,omitempty" yaml:"tags,omitempty"`
+	BasicAuth []KongStateBasicAuth `json:"basic_auth,omitempty" yaml:"basic_auth,omitempty"`
+	KeyAuth   []KongStateKeyAuth   `json:"key_auth,omitempty" yaml:"key_auth,omitempty"`
+	JWT       []KongStateJWT       `json:"jwt,omitempty" yaml:"jwt,omitempty"`
+	ACL       []KongStateACL       `json:"acls,omitempty" yaml:"acls,omitempty"`
+	HMACAuth  []KongStateHMACAuth  `json:"hmac_auth,omitempty" yaml:"hmac_auth,omitempty"`
+}
+
+// kong consumer basic auth credential state
+type KongStateBasicAuth struct {
+	id       string
+	consumer string
+	UserName string   `json:"username" yaml:"username"`
+	Password string   `json:"password,omitempty" yaml:"password,omitempty"`
+	Tags     []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// kong consumer key auth credential state
+type KongStateKeyAuth struct {
+	id       string
+	consumer string
+	Key      string   `json:"key" yaml:"key"`
+	Ttl      int64    `json:"ttl,omitempty" yaml:"ttl,omitempty"`
+	Tags     []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// kong consumer JWT credential state
+type KongStateJWT struct {
+	id        string
+	consumer  string
+	Key       string   `json:"key" yaml:"key"`
+	Algorithm string   `json:"algorithm,omitempty" yaml:"algorithm,omitempty"`
+	Secret    string   `json:"secret,omitempty" yaml:"secret,omitempty"`
+	Tags      []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// kong consumer ACL group state
+type KongStateACL struct {
+	id       string
+	consumer string
+	Group    string   `json:"group" yaml:"group"`
+	Tags     []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// kong consumer HMAC auth credential state
+type KongStateHMACAuth struct {
+	id       string
+	consumer string
+	UserName string   `json:"username" yaml:"username"`
+	Secret   string   `json:"secret,omitempty" yaml:"secret,omitempty"`
+	Tags     []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// kong plugin state
+type KongStatePlugin struct {
+	id            string
+	Name          string                 `json:"name" yaml:"name"`
+	InstanceName  string                 `json:"instance_name,omitempty" yaml:"instance_name,omitempty"`
+	Service       string                 `json:"service,omitempty" yaml:"service,omitempty"`
+	Route         string                 `json:"route,omitempty" yaml:"route,omitempty"`
+	Consumer      string                 `json:"consumer,omitempty" yaml:"consumer,omitempty"`
+	ConsumerGroup string                 `json:"consumer_group,omitempty" yaml:"consumer_group,omitempty"`
+	Config        map[string]interface{} `json:"config,omitempty" yaml:"config,omitempty"`
+	Protocols     []string               `json:"protocols,omitempty" yaml:"protocols,omitempty"`
+	Enabled       *bool                  `json:"enabled,omitempty" yaml:"enabled,omitempty"`
+	Tags          []string               `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// kong upstream state
+type KongStateUpstream struct {
+	id                  string
+	Name                string `json:"name" yaml:"name"`
+	Algorithm           string `json:"algorithm,omitempty" yaml:"algorithm,omitempty"`
+	KongUpstreamHashing `yaml:",inline"`
+	Slots               *int                      `json:"slots,omitempty" yaml:"slots,omitempty"`
+	HostHeader          string                    `json:"host_header,omitempty" yaml:"host_header,omitempty"`
+	UseSrvName          *bool                     `json:"use_srv_name,omitempty" yaml:"use_srv_name,omitempty"`
+	Tags                []string                  `json:"tags,omitempty" yaml:"tags,omitempty"`
+	Targets             []KongStateUpstreamTarget `json:"targets,omitempty" yaml:"targets,omitempty"`
+}
+
+// kong upstream target state
+type KongStateUpstreamTarget struct {
+	id       string
+	upstream string
+	Target   string   `json:"target" yaml:"target"`
+	Weight   *int     `json:"weight,omitempty" yaml:"weight,omitempty"`
+	Tags     []string `json:"tags,omitempty" yaml:"tags,omitempty"`
+}
+
+// kong state entity
+type kongStateEntity interface {
+	key() string
+	entityId() string
+}
+
+func (s *KongStateService) key() string      { return s.Name }
+func (s *KongStateService) entityId() string { return s.id }
+
+func (r *KongStateRoute) key() string      { return r.Name }
+func (r *KongStateRoute) entityId() string { return r.id }
+
+func (c *KongStateConsumer) key() string {
+	if len(c.UserName) > 0 {
+		return c.UserName
+	}
+
+	return c.CustomId
+}
+func (c *KongStateConsumer) entityId() string { return c.id }
+
+func (b *KongStateBasicAuth) key() string      { return b.consumer + "/" + b.UserName }
+func (b *KongStateBasicAuth) entityId() string { return b.id }
+
+func (k *KongStateKeyAuth) key() string      { return k.consumer + "/" + k.Key }
+func (k *KongStateKeyAuth) entityId() string { return k.id }
+
+func (j *KongStateJWT) key() string      { return j.consumer + "/" + j.Key }
+func (j *KongStateJWT) entityId() string { return j.id }
+
+func (a *KongStateACL) key() string      { return a.consumer + "/" + a.Group }
+func (a *KongStateACL) entityId() string { return a.id }
+
+func (h *KongStateHMACAuth) key() string      { return h.consumer + "/" + h.UserName }
+func (h *KongStateHMACAuth) entityId() string { return h.id }
+
+func (p *KongStatePlugin) key() string {
+	var scope []string
+
+	if len(p.Service) > 0 {
+		scope = append(scope, "service="+p.Service)
+	}
+	if len(p.Route) > 0 {
+		scope = append(scope, "route="+p.Route)
+	}
+	if len(p.Consumer) > 0 {
+		scope = append(scope, "consumer="+p.Consumer)
+	}
+	if len(p.ConsumerGroup) > 0 {
+		scope = append(scope, "consumer_group="+p.ConsumerGroup)
+	}
+
+	if len(scope) == 0 {
+		return p.Name
+	}
+
+	return p.Name + "[" + strings.Join(scope, ",") + "]"
+}
+func (p *KongStatePlugin) entityId() string { return p.id }
+
+func (u *KongStateUpstream) key() string      { return u.Name }
+func (u *KongStateUpstream) entityId() string { return u.id }
+
+func (t *KongStateUpstreamTarget) key() string      { return t.upstream + "/" + t.Target }
+func (t *KongStateUpstreamTarget) entityId() string { return t.id }
+
+const (
+	stateEntityService        string = "service"
+	stateEntityRoute          string = "route"
+	stateEntityConsumer       string = "consumer"
+	stateEntityBasicAuth      string = "consumer-basic-auth"
+	stateEntityKeyAuth        string = "consumer-key-auth"
+	stateEntityJWT            string = "consumer-jwt"
+	stateEntityACL            string = "consumer-acl"
+	stateEntityHMACAuth       string = "consumer-hmac-auth"
+	stateEntityPlugin         string = "plugin"
+	stateEntityUpstream       string = "upstream"
+	stateEntityUpstreamTarget string = "upstream-target"
+
+	stateActionCreate string = "create"
+	stateActionUpdate string = "update"
+	stateActionDelete string = "delete"
+)
+
+// attributes kept in nested collections and not compared as fields
+var stateNestedFields = map[string]bool{
+	"targets":    true,
+	"basic_auth": true,
+	"key_auth":   true,
+	"jwt":        true,
+	"acls":       true,
+	"hmac_auth":  true,
+}
+
+// attributes Kong replaces as a whole, so they are compared as a whole instead of key by key
+var stateWholeFields = map[string]bool{
+	"headers": true,
+}
+
+// attributes Kong never returns as they were sent, so they are not compared
+var stateWriteOnlyFields = map[string]bool{
+	"password": true,
+}
+
+// load a kong declarative state from a YAML or JSON file
+func LoadKongState(fileName string) (*KongState, error) {
+
+	payload, err := os.ReadFile(fileName)
+	if err != nil {
+		return nil, err
+	}
+
+	var state KongState
+
+	if strings.HasSuffix(strings.ToLower(fileName), ".json") {
+		decoder := json.NewDecoder(bytes.NewReader(payload))
+		decoder.DisallowUnknownFields()
+
+		err = decoder.Decode(&state)
+	} else {
+		decoder := yaml.NewDecoder(bytes.NewReader(payload))
+		decoder.KnownFields(true)
+
+		err = decoder.Decode(&state)
+	}
+	if err != nil {
+		return nil, errors.New("fail parsing state file " + fileName + ": " + err.Error())
+	}
+
+	err = state.normalize()
+	if err != nil {
+		return nil, err
+	}
+
+	return &state, nil
+}
+
+// validate a declarative state and fill attributes derived from others
+func (state *KongState) normalize() error {
+
+	services := make(map[string]bool)
+	for i := range state.Services {
+		service := &state.Services[i]
+
+		if len(service.Name) == 0 {
+			return errors.New("missing name for service #" + strconv.Itoa(i+1))
+		}
+		if services[service.Name] {
+			return errors.New("duplicate service: " + service.Name)
+		}
+		services[service.Name] = true
+
+		if len(service.Url) > 0 {
+			serviceURL, err := url.Parse(service.Url)
+			if err != nil {
+				return errors.New("invalid url for service " + service.Name + ": " + err.Error())
+			}
+
+			service.Protocol = serviceURL.Scheme
+			service.Host = serviceURL.Hostname()
+			service.Path = serviceURL.Path
+			service.Port = 80
+			if serviceURL.Scheme == "https" {
+				service.Port = 443
+			}
+			if len(serviceURL.Port()) > 0 {
+				service.Port, err = strconv.Atoi(serviceURL.Port())
+				if err != nil {
+					return errors.New("invalid port for service " + service.Name + ": " + err.Error())
+				}
+			}
+			service.Url = ""
+		}
+	}
+
+	routes := make(map[string]bool)
+	for i := range state.Routes {
+		route := &state.Routes[i]
+
+		if len(route.Name) == 0 {
+			return errors.New("missing name for route #" + strconv.Itoa(i+1))
+		}
+		if routes[route.Name] {
+			return errors.New("duplicate route: " + route.Name)
+		}
+		routes[route.Name] = true
+
+		if len(route.Service) > 0 && !services[route.Service] {
+			return errors.New("route " + route.Name + " references unknown service: " + route.Service)
+		}
+	}
+
+	consumers := make(map[string]bool)
+	for i := range state.Consumers {
+		consumer := &state.Consumers[i]
+
+		if len(consumer.key()) == 0 {
+			return errors.New("missing username or custom_id for consumer #" + strconv.Itoa(i+1))
+		}
+		if consumers[consumer.key()] {
+			return errors.New("duplicate consumer: " + consumer.key())
+		}
+		consumers[consumer.key()] = true
+
+		credentials := make(map[string]bool)
+		for j := range consumer.BasicAuth {
+			basicAuth := &consumer.BasicAuth[j]
+
+			basicAuth.consumer = consumer.key()
+			if len(basicAuth.UserName) == 0 {
+				return errors.New("missing username for consumer " + consumer.key() + " basic auth #" + strconv.Itoa(j+1))
+			}
+			if credentials[basicAuth.key()] {
+				return errors.New("duplicate basic auth for consumer " + consumer.key() + ": " + basicAuth.UserName)
+			}
+			credentials[basicAuth.key()] = true
+		}
+
+		credentials = make(map[string]bool)
+		for j := range consumer.KeyAuth {
+			keyAuth := &consumer.KeyAuth[j]
+
+			keyAuth.consumer = consumer.key()
+			if len(keyAuth.Key) == 0 {
+				return errors.New("missing key for consumer " + consumer.key() + " key auth #" + strconv.Itoa(j+1))
+			}
+			if credentials[keyAuth.key()] {
+				return errors.New("duplicate key auth for consumer " + consumer.key() + ": " + keyAuth.Key)
+			}
+			credentials[keyAuth.key()] = true
+		}
+
+		credentials = make(map[string]bool)
+		for j := range consumer.JWT {
+			jwt := &consumer.JWT[j]
+
+			jwt.consumer = consumer.key()
+			if len(jwt.Key) == 0 {
+				return errors.New("missing key for consumer " + consumer.key() + " JWT #" + strconv.Itoa(j+1))
+			}
+			if credentials[jwt.key()] {
+				return errors.New("duplicate JWT for consumer " + consumer.key() + ": " + jwt.Key)
+			}
+			credentials[jwt.key()] = true
+		}
+
+		credentials = make(map[string]bool)
+		for j := range consumer.ACL {
+			acl := &consumer.ACL[j]
+
+			acl.consumer = consumer.key()
+			if len(acl.Group) == 0 {
+				return errors.New("missing group for consumer " + consumer.key() + " ACL #" + strconv.Itoa(j+1))
+			}
+			if credentials[acl.key()] {
+				return errors.New("duplicate ACL for consumer " + consumer.key() + ": " + acl.Group)
+			}
+			credentials[acl.key()] = true
+		}
+
+		credentials = make(map[string]bool)
+		for j := range consumer.HMACAuth {
+			hmacAuth := &consumer.HMACAuth[j]
+
+			hmacAuth.consumer = consumer.key()
+			if len(hmacAuth.UserName) == 0 {
+				return errors.New("missing username for consumer " + consumer.key() + " HMAC auth #" + strconv.Itoa(j+1))
+			}
+			if credentials[hmacAuth.key()] {
+				return errors.New("duplicate HMAC auth for consumer " + consumer.key() + ": " + hmacAuth.UserName)
+			}
+			credentials[hmacAuth.key()] = true
+		}
+	}
+
+	upstreams := make(map[string]bool)
+	for i := range state.Upstreams {
+		upstream := &state.Upstreams[i]
+
+		if len(upstream.Name) == 0 {
+			return errors.New("missing name for upstream #" + strconv.Itoa(i+1))
+		}
+		if upstreams[upstream.Name] {
+			return errors.New("duplicate upstream: " + upstream.Name)
+		}
+		upstreams[upstream.Name] = true
+
+		targets := make(map[string]bool)
+		for j := range upstream.Targets {
+			target := &upstream.Targets[j]
+
+			if len(target.Target) == 0 {
+				return errors.New("missing target for upstream " + upstream.Name + " target #" + strconv.Itoa(j+1))
+			}
+			if targets[target.Target] {
+				return errors.New("duplicate target for upstream " + upstream.Name + ": " + target.Target)
+			}
+			targets[target.Target] = true
+			target.upstream = upstream.Name
+		}
+	}
+
+	plugins := make(map[string]bool)
+	for i := range state.Plugins {
+		plugin := &state.Plugins[i]
+
+		if len(plugin.Name) == 0 {
+			return errors.New("missing name for plugin #" + strconv.Itoa(i+1))
+		}
+		if len(plugin.Service) > 0 && !services[plugin.Service] {
+			return errors.New("plugin " + plugin.Name + " references unknown service: " + plugin.Service)
+		}
+		if len(plugin.Route) > 0 && !routes[plugin.Route] {
+			return errors.New("plugin " + plugin.Name + " references unknown route: " + plugin.Route)
+		}
+		if len(plugin.Consumer) > 0 && !consumers[plugin.Consumer] {
+			return errors.New("plugin " + plugin.Name + " references unknown consumer: " + plugin.Consumer)
+		}
+		if plugins[plugin.key()] {
+			return errors.New("duplicate plugin: " + plugin.key())
+		}
+		plugins[plugin.key()] = true
+	}
+
+	return nil
+}
+
+// fetch the current Kong configuration as a declarative state
+func (ks *KongServerDomain) fetchKongState() (*KongState, error) {
+
+	var state KongState
+
+	//	services
+	serviceNames := make(map[string]string)
+
+	data, err := ks.fetchStateCollection(servicesResource)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range data {
+		var serviceResp KongServiceResponse
+
+		err = json.Unmarshal(item, &serviceResp)
+		if err != nil {
+			return nil, err
+		}
+
+		enabled := serviceResp.Enabled
+		state.Services = append(state.Services, KongStateService{
+			id:             serviceResp.Id,
+			Name:           state.dumpedName(stateEntityService, serviceResp.Name, serviceResp.Id),
+			Protocol:       serviceResp.Protocol,
+			Host:           serviceResp.Host,
+			Port:           serviceResp.Port,
+			Path:           serviceResp.Path,
+			Retries:        &serviceResp.Retries,
+			ConnectTimeout: &serviceResp.ConnectTimeout,
+			ReadTimeout:    &serviceResp.ReadTimeout,
+			WriteTimeout:   &serviceResp.WriteTimeout,
+			Enabled:        &enabled,
+			Tags:           serviceResp.Tags,
+		})
+		serviceNames[serviceResp.Id] = stateName(serviceResp.Name, serviceResp.Id)
+	}
+
+	//	routes
+	routeNames := make(map[string]string)
+
+	data, err = ks.fetchStateCollection(routesResource)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range data {
+		var routeResp KongRouteResponse
+
+		err = json.Unmarshal(item, &routeResp)
+		if err != nil {
+			return nil, err
+		}
+
+		//	the priority only exists for routes matched by an expression
+		var priority *int64
+
+		if len(routeResp.Expression) > 0 {
+			priority = &routeResp.Priority
+		}
+
+		state.Routes = append(state.Routes, KongStateRoute{
+			id:                      routeResp.Id,
+			Name:                    state.dumpedName(stateEntityRoute, routeResp.Name, routeResp.Id),
+			Service:                 stateReference(serviceNames, routeResp.Service.Id),
+			Protocols:               routeResp.Protocols,
+			Methods:                 routeResp.Methods,
+			Paths:                   routeResp.Paths,
+			Hosts:                   routeResp.Hosts,
+			Headers:                 routeResp.Headers,
+			Snis:                    routeResp.Snis,
+			Sources:                 routeResp.Sources,
+			Destinations:            routeResp.Destinations,
+			StripPath:               &routeResp.StripPath,
+			PreserveHost:            &routeResp.PreserveHost,
+			RegexPriority:           &routeResp.RegexPriority,
+			PathHandling:            routeResp.PathHandling,
+			HttpsRedirectStatusCode: routeResp.HttpsRedirectStatusCode,
+			RequestBuffering:        &routeResp.RequestBuffering,
+			ResponseBuffering:       &routeResp.ResponseBuffering,
+			Expression:              routeResp.Expression,
+			Priority:                priority,
+			Tags:                    routeResp.Tags,
+		})
+		routeNames[routeResp.Id] = stateName(routeResp.Name, routeResp.Id)
+	}
+
+	//	consumers and their credentials
+	consumerKeys := make(map[string]string)
+	consumerIndexes := make(map[string]int)
+
+	data, err = ks.fetchStateCollection(consumersResource)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range data {
+		var consumerResp KongConsumerResponse
+
+		err = json.Unmarshal(item, &consumerResp)
+		if err != nil {
+			return nil, err
+		}
+
+		consumer := KongStateConsumer{
+			id:       consumerResp.Id,
+			UserName: consumerResp.UserName,
+			CustomId: consumerResp.CustomId,
+			Tags:     consumerResp.Tags,
+		}
+		state.Consumers = append(state.Consumers, consumer)
+		consumerKeys[consumerResp.Id] = stateName(consumer.key(), consumerResp.Id)
+		consumerIndexes[consumerResp.Id] = len(state.Consumers) - 1
+	}
+
+	data, err = ks.fetchStateCollection(basicAuthsResource)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range data {
+		var basicAuthResp KongBasicAuthResponse
+
+		err = json.Unmarshal(item, &basicAuthResp)
+		if err != nil {
+			return nil, err
+		}
+
+		index, ok := consumerIndexes[basicAuthResp.Consumer.Id]
+		if !ok {
+			continue
+		}
+
+		basicAuth := KongStateBasicAuth{
+			id:       basicAuthResp.Id,
+			consumer: consumerKeys[basicAuthResp.Consumer.Id],
+			UserName: basicAuthResp.UserName,
+			Tags:     basicAuthResp.Tags,
+		}
+		state.Consumers[index].BasicAuth = append(state.Consumers[index].BasicAuth, basicAuth)
+		state.warnings = append(state.warnings, stateEntityBasicAuth+" "+basicAuth.key()+
+			": the password is not dumped, since Kong only stores its hash: set it in the state file before applying it")
+	}
+
+	data, err = ks.fetchStateCollection(keyAuthsResource)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range data {
+		var keyAuthResp KongKeyAuthResponse
+
+		err = json.Unmarshal(item, &keyAuthResp)
+		if err != nil {
+			return nil, err
+		}
+
+		index, ok := consumerIndexes[keyAuthResp.Consumer.Id]
+		if !ok {
+			continue
+		}
+
+		state.Consumers[index].KeyAuth = append(state.Consumers[index].KeyAuth, KongStateKeyAuth{
+			id:       keyAuthResp.Id,
+			consumer: consumerKeys[keyAuthResp.Consumer.Id],
+			Key:      keyAuthResp.Key,
+			Ttl:      keyAuthResp.Ttl,
+			Tags:     keyAuthResp.Tags,
+		})
+	}
+
+	data, err = ks.fetchStateCollection(jwtsResource)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range data {
+		var jwtResp KongJWTResponse
+
+		err = json.Unmarshal(item, &jwtResp)
+		if err != nil {
+			return nil, err
+		}
+
+		if jwtResp.Consumer == nil {
+			continue
+		}
+
+		index, ok := consumerIndexes[jwtResp.Consumer.Id]
+		if !ok {
+			continue
+		}
+
+		state.Consumers[index].JWT = append(state.Consumers[index].JWT, KongStateJWT{
+			id:        jwtResp.Id,
+			consumer:  consumerKeys[jwtResp.Consumer.Id],
+			Key:       jwtResp.Key,
+			Algorithm: jwtResp.Algorithm,
+			Secret:    jwtResp.Secret,
+			Tags:      jwtResp.Tags,
+		})
+	}
+
+	data, err = ks.fetchStateCollection(aclsResource)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range data {
+		var aclResp KongACLResponse
+
+		err = json.Unmarshal(item, &aclResp)
+		if err != nil {
+			return nil, err
+		}
+
+		index, ok := consumerIndexes[aclResp.Consumer.Id]
+		if !ok {
+			continue
+		}
+
+		state.Consumers[index].ACL = append(state.Consumers[index].ACL, KongStateACL{
+			id:       aclResp.Id,
+			consumer: consumerKeys[aclResp.Consumer.Id],
+			Group:    aclResp.Group,
+			Tags:     aclResp.Tags,
+		})
+	}
+
+	data, err = ks.fetchStateCollection(hmacAuthsResource)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range data {
+		var hmacAuthResp KongHMACAuthResponse
+
+		err = json.Unmarshal(item, &hmacAuthResp)
+		if err != nil {
+			return nil, err
+		}
+
+		index, ok := consumerIndexes[hmacAuthResp.Consumer.Id]
+		if !ok {
+			continue
+		}
+
+		state.Consumers[index].HMACAuth = append(state.Consumers[index].HMACAuth, KongStateHMACAuth{
+			id:       hmacAuthResp.Id,
+			consumer: consumerKeys[hmacAuthResp.Consumer.Id],
+			UserName: hmacAuthResp.UserName,
+			Secret:   hmacAuthResp.Secret,
+			Tags:     hmacAuthResp.Tags,
+		})
+	}
+
+	//	upstreams and their targets
+	data, err = ks.fetchStateCollection(upstreamResource)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range data {
+		var upstreamResp KongUpstreamResponse
+
+		err = json.Unmarshal(item, &upstreamResp)
+		if err != nil {
+			return nil, err
+		}
+
+		upstream := KongStateUpstream{
+			id:                  upstreamResp.Id,
+			Name:                upstreamResp.Name,
+			Algorithm:           upstreamResp.Algorithm,
+			KongUpstreamHashing: upstreamResp.KongUpstreamHashing,
+			Slots:               &upstreamResp.Slots,
+			HostHeader:          upstreamResp.HostHeader,
+			UseSrvName:          &upstreamResp.UseSrvName,
+			Tags:                upstreamResp.Tags,
+		}
+
+		targetData, err := ks.fetchStateCollection(fmt.Sprintf("%s/%s/%s", upstreamResource, upstreamResp.Id, upstreamTargetResource))
+		if err != nil {
+			return nil, err
+		}
+
+		for _, targetItem := range targetData {
+			var upstreamTargetResp KongUpstreamTargetResponse
+
+			err = json.Unmarshal(targetItem, &upstreamTargetResp)
+			if err != nil {
+				return nil, err
+			}
+
+			weight := upstreamTargetResp.Weight
+			upstream.Targets = append(upstream.Targets, KongStateUpstreamTarget{
+				id:       upstreamTargetResp.Id,
+				upstream: upstream.Name,
+				Target:   upstreamTargetResp.Target,
+				Weight:   &weight,
+				Tags:     upstreamTargetResp.Tags,
+			})
+		}
+		state.Upstreams = append(state.Upstreams, upstream)
+	}
+
+	//	consumer groups, to resolve the scope of plugins
+	consumerGroupNames := make(map[string]string)
+	state.consumerGroupIds = make(map[string]string)
+
+	data, err = ks.fetchStateCollection(consumerGroupsResource)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range data {
+		var consumerGroupResp KongConsumerGroupResponse
+
+		err = json.Unmarshal(item, &consumerGroupResp)
+		if err != nil {
+			return nil, err
+		}
+
+		consumerGroupNames[consumerGroupResp.Id] = stateName(consumerGroupResp.Name, consumerGroupResp.Id)
+		state.consumerGroupIds[stateName(consumerGroupResp.Name, consumerGroupResp.Id)] = consumerGroupResp.Id
+	}
+
+	//	plugins
+	data, err = ks.fetchStateCollection(pluginsResource)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, item := range data {
+		var pluginResp KongPluginResponse
+
+		err = json.Unmarshal(item, &pluginResp)
+		if err != nil {
+			return nil, err
+		}
+
+		enabled := pluginResp.Enabled
+		state.Plugins = append(state.Plugins, KongStatePlugin{
+			id:            pluginResp.Id,
+			Name:          pluginResp.Name,
+			InstanceName:  pluginResp.InstanceName,
+			Service:       stateReference(serviceNames, pluginResp.Service.Id),
+			Route:         stateReference(routeNames, pluginResp.Route.Id),
+			Consumer:      stateReference(consumerKeys, pluginResp.Consumer.Id),
+			ConsumerGroup: stateReference(consumerGroupNames, pluginResp.ConsumerGroup.Id),
+			Config:        stripNullFields(pluginResp.Config),
+			Protocols:     pluginResp.Protocols,
+			Enabled:       &enabled,
+			Tags:          pluginResp.Tags,
+		})
+	}
+
+	return &state, nil
+}
+
+// remove the upstream attributes at the Kong defaults, so a dumped state only has the ones that were set
+func (upstream *KongStateUpstream) omitDefaults() {
+
+	if upstream.HashOn == "none" {
+		upstream.HashOn = ""
+	}
+	if upstream.HashFallback == "none" {
+		upstream.HashFallback = ""
+	}
+	if upstream.HashOnCookiePath == "/" {
+		upstream.HashOnCookiePath = ""
+	}
+	if upstream.Slots != nil && *upstream.Slots == 10000 {
+		upstream.Slots = nil
+	}
+	if upstream.UseSrvName != nil && !*upstream.UseSrvName {
+		upstream.UseSrvName = nil
+	}
+}
+
+// remove attributes without value from a plugin config
+func stripNullFields(fields map[string]interface{}) map[string]interface{} {
+
+	if fields == nil {
+		return nil
+	}
+
+	stripped := make(map[string]interface{})
+
+	for key, value := range fields {
+		if value == nil {
+			continue
+		}
+
+		if nested, ok := value.(map[string]interface{}); ok {
+			value = stripNullFields(nested)
+		}
+		stripped[key] = value
+	}
+
+	return stripped
+}
+
+// fetch all entities from a Kong collection
+func (ks *KongServerDomain) fetchStateCollection(resourcePath string) ([]json.RawMessage, error) {
+
+	collection, _, err := ks.fetchCollection(resourcePath, nil, "fail fetching "+resourcePath+" from Kong")
+	if err != nil {
+		return nil, err
+	}
+
+	return collection.Data, nil
+}
+
+// name used for an entity in the state, falling back to its id when unnamed
+func stateName(name string, id string) string {
+
+	if len(name) == 0 {
+		return id
+	}
+
+	return name
+}
+
+// name of a dumped entity: an entity without a name is named by its id, which apply would give as name to a new entity
+func (state *KongState) dumpedName(entity string, name string, id string) string {
+
+	if len(name) == 0 {
+		state.warnings = append(state.warnings, entity+" "+id+": it has no name, so its id is used as the name")
+	}
+
+	return stateName(name, id)
+}
+
+// resolve a foreign id into the name used in the state
+func stateReference(names map[string]string, id string) string {
+
+	if len(id) == 0 {
+		return ""
+	}
+
+	name, ok := names[id]
+	if !ok {
+		return id
+	}
+
+	return name
+}
+
+// kong state field change
+type KongStateFieldChange struct {
+	Field   string      `json:"field"`
+	Current interface{} `json:"current,omitempty"`
+	Desired interface{} `json:"desired,omitempty"`
+}
+
+// kong state change
+type KongStateChange struct {
+	Entity  string                 `json:"entity"`
+	Key     string                 `json:"key"`
+	Action  string                 `json:"action"`
+	Id      string                 `json:"id,omitempty"`
+	Fields  []KongStateFieldChange `json:"fields,omitempty"`
+	desired kongStateEntity
+}
+
+// compute the changes required to move Kong from the current to the desired state
+func planKongState(desired *KongState, current *KongState) ([]KongStateChange, error) {
+
+	type entityList struct {
+		entity  string
+		desired []kongStateEntity
+		current []kongStateEntity
+	}
+
+	//	entities in dependency order
+	lists := []entityList{
+		{entity: stateEntityService},
+		{entity: stateEntityRoute},
+		{entity: stateEntityConsumer},
+		{entity: stateEntityBasicAuth},
+		{entity: stateEntityKeyAuth},
+		{entity: stateEntityJWT},
+		{entity: stateEntityACL},
+		{entity: stateEntityHMACAuth},
+		{entity: stateEntityUpstream},
+		{entity: stateEntityUpstreamTarget},
+		{entity: stateEntityPlugin},
+	}
+
+	for _, state := range []*KongState{desired, current} {
+		var services, routes, consumers, basicAuths, keyAuths, jwts, acls, hmacAuths, upstreams, targets, plugins []kongStateEntity
+
+		for i := range state.Services {
+			services = append(services, &state.Services[i])
+		}
+		for i := range state.Routes {
+			routes = append(routes, &state.Routes[i])
+		}
+		for i := range state.Consumers {
+			consumer := &state.Consumers[i]
+			consumers = append(consumers, consumer)
+
+			for j := range consumer.BasicAuth {
+				consumer.BasicAuth[j].consumer = consumer.key()
+				basicAuths = append(basicAuths, &consumer.BasicAuth[j])
+			}
+			for j := range consumer.KeyAuth {
+				consumer.KeyAuth[j].consumer = consumer.key()
+				keyAuths = append(keyAuths, &consumer.KeyAuth[j])
+			}
+			for j := range consumer.JWT {
+				consumer.JWT[j].consumer = consumer.key()
+				jwts = append(jwts, &consumer.JWT[j])
+			}
+			for j := range consumer.ACL {
+				consumer.ACL[j].consumer = consumer.key()
+				acls = append(acls, &consumer.ACL[j])
+			}
+			for j := range consumer.HMACAuth {
+				consumer.HMACAuth[j].consumer = consumer.key()
+				hmacAuths = append(hmacAuths, &consumer.HMACAuth[j])
+			}
+		}
+		for i := range state.Upstreams {
+			upstreams = append(upstreams, &state.Upstreams[i])
+
+			for j := range state.Upstreams[i].Targets {
+				state.Upstreams[i].Targets[j].upstream = state.Upstreams[i].Name
+				targets = append(targets, &state.Upstreams[i].Targets[j])
+			}
+		}
+		for i := range state.Plugins {
+			plugins = append(plugins, &state.Plugins[i])
+		}
+
+		entities := [][]kongStateEntity{services, routes, consumers, basicAuths, keyAuths, jwts, acls, hmacAuths, upstreams, targets, plugins}
+		for i := range lists {
+			if state == desired {
+				lists[i].desired = entities[i]
+			} else {
+				lists[i].current = entities[i]
+			}
+		}
+	}
+
+	var (
+		deletes []KongStateChange
+		changes []KongStateChange
+	)
+
+	//	upstreams and consumers being deleted take their targets and credentials with them
+	deletedParents := make(map[string]bool)
+
+	for _, list := range lists {
+		desiredKeys := make(map[string]bool)
+		currentEntities := make(map[string]kongStateEntity)
+
+		for _, entity := range list.current {
+			currentEntities[entity.key()] = entity
+		}
+
+		for _, entity := range list.desired {
+			desiredKeys[entity.key()] = true
+
+			currentEntity, ok := currentEntities[entity.key()]
+			if !ok {
+				changes = append(changes, KongStateChange{
+					Entity:  list.entity,
+					Key:     entity.key(),
+					Action:  stateActionCreate,
+					desired: entity,
+				})
+				continue
+			}
+
+			fields, err := diffStateFields(entity, currentEntity)
+			if err != nil {
+				return nil, err
+			}
+
+			if len(fields) > 0 {
+				changes = append(changes, KongStateChange{
+					Entity:  list.entity,
+					Key:     entity.key(),
+					Action:  stateActionUpdate,
+					Id:      currentEntity.entityId(),
+					Fields:  fields,
+					desired: entity,
+				})
+			}
+		}
+
+		var entityDeletes []KongStateChange
+
+		for _, entity := range list.current {
+			if desiredKeys[entity.key()] {
+				continue
+			}
+
+			deletedParents[list.entity+":"+entity.key()] = true
+			if deletedParents[stateParent(entity)] {
+				continue
+			}
+
+			entityDeletes = append(entityDeletes, KongStateChange{
+				Entity:  list.entity,
+				Key:     entity.key(),
+				Action:  stateActionDelete,
+				Id:      entity.entityId(),
+				desired: entity,
+			})
+		}
+
+		//	deletes run in reverse dependency order
+		deletes = append(entityDeletes, deletes...)
+	}
+
+	//	deletes run after creates and updates, so no entity is deleted while another one still references it
+	return append(changes, deletes...), nil
+}
+
+// parent of a nested state entity
+func stateParent(entity kongStateEntity) string {
+
+	switch nested := entity.(type) {
+	case *KongStateUpstreamTarget:
+		return stateEntityUpstream + ":" + nested.upstream
+
+	case *KongStateBasicAuth:
+		return stateEntityConsumer + ":" + nested.consumer
+
+	case *KongStateKeyAuth:
+		return stateEntityConsumer + ":" + nested.consumer
+
+	case *KongStateJWT:
+		return stateEntityConsumer + ":" + nested.consumer
+
+	case *KongStateACL:
+		return stateEntityConsumer + ":" + nested.consumer
+
+	case *KongStateHMACAuth:
+		return stateEntityConsumer + ":" + nested.consumer
+	}
+
+	return ""
+}
+
+// convert a state entity into a map of Kong attributes
+func stateFields(entity interface{}) (map[string]interface{}, error) {
+
+	payload, err := json.Marshal(entity)
+	if err != nil {
+		return nil, err
+	}
+
+	var fields map[string]interface{}
+
+	err = json.Unmarshal(payload, &fields)
+	if err != nil {
+		return nil, err
+	}
+
+	for field := range stateNestedFields {
+		delete(fields, field)
+	}
+
+	return fields, nil
+}
+
+// compare the attributes set in the desired entity with the current one
+func diffStateFields(desired interface{}, current interface{}) ([]KongStateFieldChange, error) {
+
+	desiredFields, err := stateFields(desired)
+	if err != nil {
+		return nil, err
+	}
+
+	currentFields, err := stateFields(current)
+	if err != nil {
+		return nil, err
+	}
+
+	var changes []KongStateFieldChange
+
+	for _, field := range sortedFieldNames(desiredFields) {
+		if stateWriteOnlyFields[field] {
+			continue
+		}
+
+		if stateWholeFields[field] {
+			if !reflect.DeepEqual(desiredFields[field], currentFields[field]) {
+				changes = append(changes, KongStateFieldChange{
+					Field:   field,
+					Current: currentFields[field],
+					Desired: desiredFields[field],
+				})
+			}
+			continue
+		}
+
+		changes = diffStateValue(field, desiredFields[field], currentFields[field], changes)
+	}
+
+	return changes, nil
+}
+
+// compare a desired value with the current one: maps only compare the desired keys
+func diffStateValue(field string, desired interface{}, current interface{}, changes []KongStateFieldChange) []KongStateFieldChange {
+
+	desiredMap, ok := desired.(map[string]interface{})
+	if !ok {
+		if !reflect.DeepEqual(desired, current) {
+			changes = append(changes, KongStateFieldChange{
+				Field:   field,
+				Current: current,
+				Desired: desired,
+			})
+		}
+
+		return changes
+	}
+
+	currentMap, ok := current.(map[string]interface{})
+	if !ok {
+		return append(changes, KongStateFieldChange{
+			Field:   field,
+			Current: current,
+			Desired: desired,
+		})
+	}
+
+	for _, key := range sortedFieldNames(desiredMap) {
+		changes = diffStateValue(field+"."+key, desiredMap[key], currentMap[key], changes)
+	}
+
+	return changes
+}
+
+// names of the attributes in alphabetical order
+func sortedFieldNames(fields map[string]interface{}) []string {
+
+	var fieldNames []string
+
+	for field := range fields {
+		fieldNames = append(fieldNames, field)
+	}
+	sort.Strings(fieldNames)
+
+	return fieldNames
+}
+
+// sort the entities of a declarative state so that its document is stable
+func (state *KongState) sort() {
+
+	sort.Slice(state.Services, func(i, j int) bool { return state.Services[i].key() < state.Services[j].key() })
+	sort.Slice(state.Routes, func(i, j int) bool { return state.Routes[i].key() < state.Routes[j].key() })
+	sort.Slice(state.Plugins, func(i, j int) bool { return state.Plugins[i].key() < state.Plugins[j].key() })
+
+	sort.Slice(state.Consumers, func(i, j int) bool { return state.Consumers[i].key() < state.Consumers[j].key() })
+	for i := range state.Consumers {
+		consumer := &state.Consumers[i]
+
+		sort.Slice(consumer.BasicAuth, func(i, j int) bool { return consumer.BasicAuth[i].UserName < consumer.BasicAuth[j].UserName })
+		sort.Slice(consumer.KeyAuth, func(i, j int) bool { return consumer.KeyAuth[i].Key < consumer.KeyAuth[j].Key })
+		sort.Slice(consumer.JWT, func(i, j int) bool { return consumer.JWT[i].Key < consumer.JWT[j].Key })
+		sort.Slice(consumer.ACL, func(i, j int) bool { return consumer.ACL[i].Group < consumer.ACL[j].Group })
+		sort.Slice(consumer.HMACAuth, func(i, j int) bool { return consumer.HMACAuth[i].UserName < consumer.HMACAuth[j].UserName })
+	}
+
+	sort.Slice(state.Upstreams, func(i, j int) bool { return state.Upstreams[i].key() < state.Upstreams[j].key() })
+	for i := range state.Upstreams {
+		upstream := &state.Upstreams[i]
+
+		sort.Slice(upstream.Targets, func(i, j int) bool { return upstream.Targets[i].Target < upstream.Targets[j].Target })
+	}
+}
+
+// marshal a declarative state as a YAML or JSON document
+func marshalKongState(state *KongState, jsonFormat bool) ([]byte, error) {
+
+	if jsonFormat {
+		payload, err := json.MarshalIndent(state, "", "    ")
+		if err != nil {
+			return nil, err
+		}
+
+		return append(payload, '\n'), nil
+	}
+
+	var payload bytes.Buffer
+
+	encoder := yaml.NewEncoder(&payload)
+	encoder.SetIndent(2)
+
+	err := encoder.Encode(state)
+	if err != nil {
+		return nil, err
+	}
+
+	err = encoder.Close()
+	if err != nil {
+		return nil, err
+	}
+
+	return payload.Bytes(), nil
+}
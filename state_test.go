@@ -0,0 +1,375 @@
+////////////////////////////////////////////////////////////////////////////////
+//	state_test.go  -  Oct-17-2026  -  aldebap
+//
+//	Test cases for Kong declarative state
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// write a state file for the test cases
+func writeStateFile(t *testing.T, fileName string, content string) string {
+
+	stateFile := filepath.Join(t.TempDir(), fileName)
+
+	err := os.WriteFile(stateFile, []byte(content), 0644)
+	if err != nil {
+		t.Fatalf("fail writing state file: %s", err.Error())
+	}
+
+	return stateFile
+}
+
+// Test_LoadKongState unit tests for LoadKongState() function
+func Test_LoadKongState(t *testing.T) {
+
+	t.Run(">>> LoadKongState: scenario 1 - YAML state file", func(t *testing.T) {
+
+		stateFile := writeStateFile(t, "state.yaml", `
+services:
+  - name: Produtos
+    url: http://192.168.68.107:8080/api/v1/produto
+routes:
+  - name: Produtos
+    service: Produtos
+    paths: [/api/v1/produto]
+upstreams:
+  - name: Pedidos
+    algorithm: consistent-hashing
+    hash_on: header
+    hash_on_header: x-tenant
+    targets:
+      - target: 192.168.68.107:8080
+plugins:
+  - name: rate-limiting
+    route: Produtos
+    config:
+      minute: 10
+`)
+
+		got, err := LoadKongState(stateFile)
+		if err != nil {
+			t.Fatalf("failed loading state file: success expected: result: %s", err.Error())
+		}
+
+		service := got.Services[0]
+		if service.Protocol != "http" || service.Host != "192.168.68.107" || service.Port != 8080 || service.Path != "/api/v1/produto" || len(service.Url) != 0 {
+			t.Errorf("failed loading state file: unexpected service: %+v", service)
+		}
+
+		if upstream := got.Upstreams[0]; upstream.HashOn != "header" || upstream.HashOnHeader != "x-tenant" {
+			t.Errorf("failed loading state file: upstream hashing expected: result: %+v", upstream)
+		}
+
+		if got.Upstreams[0].Targets[0].upstream != "Pedidos" {
+			t.Errorf("failed loading state file: target upstream expected: Pedidos result: %s", got.Upstreams[0].Targets[0].upstream)
+		}
+
+		if got.Plugins[0].key() != "rate-limiting[route=Produtos]" {
+			t.Errorf("failed loading state file: plugin key expected: rate-limiting[route=Produtos] result: %s", got.Plugins[0].key())
+		}
+	})
+
+	t.Run(">>> LoadKongState: scenario 2 - JSON state file", func(t *testing.T) {
+
+		stateFile := writeStateFile(t, "state.json", `{
+			"consumers": [ { "username": "guest", "tags": [ "bronze-tier" ] } ]
+		}`)
+
+		got, err := LoadKongState(stateFile)
+		if err != nil {
+			t.Fatalf("failed loading state file: success expected: result: %s", err.Error())
+		}
+
+		if len(got.Consumers) != 1 || got.Consumers[0].key() != "guest" {
+			t.Errorf("failed loading state file: unexpected consumers: %+v", got.Consumers)
+		}
+	})
+
+	t.Run(">>> LoadKongState: scenario 3 - route referencing unknown service", func(t *testing.T) {
+
+		stateFile := writeStateFile(t, "state.yaml", `
+routes:
+  - name: Produtos
+    service: Produtos
+`)
+
+		want := errors.New("route Produtos references unknown service: Produtos")
+		_, got := LoadKongState(stateFile)
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed loading state file: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> LoadKongState: scenario 4 - duplicate service", func(t *testing.T) {
+
+		stateFile := writeStateFile(t, "state.yaml", `
+services:
+  - name: Produtos
+  - name: Produtos
+`)
+
+		want := errors.New("duplicate service: Produtos")
+		_, got := LoadKongState(stateFile)
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed loading state file: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> LoadKongState: scenario 5 - duplicate consumer ACL group", func(t *testing.T) {
+
+		stateFile := writeStateFile(t, "state.yaml", `
+consumers:
+  - username: guest
+    acls:
+      - group: partners
+      - group: partners
+`)
+
+		want := errors.New("duplicate ACL for consumer guest: partners")
+		_, got := LoadKongState(stateFile)
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed loading state file: error expected: %v result: %v", want, got)
+		}
+	})
+}
+
+// Test_planKongState unit tests for planKongState() function
+func Test_planKongState(t *testing.T) {
+
+	t.Run(">>> planKongState: scenario 1 - create, update and delete", func(t *testing.T) {
+
+		enabled := true
+		desired := &KongState{
+			Services: []KongStateService{
+				{Name: "Produtos", Host: "192.168.68.107", Port: 8080},
+				{Name: "Pedidos", Host: "192.168.68.107", Port: 8081},
+			},
+			Plugins: []KongStatePlugin{
+				{Name: "rate-limiting", Service: "Produtos", Config: map[string]interface{}{"minute": 10}},
+			},
+		}
+		current := &KongState{
+			Services: []KongStateService{
+				{id: "1", Name: "Produtos", Protocol: "http", Host: "192.168.68.107", Port: 8000, Enabled: &enabled},
+				{id: "2", Name: "Clientes", Protocol: "http", Host: "192.168.68.107", Port: 8082, Enabled: &enabled},
+			},
+			Plugins: []KongStatePlugin{
+				{id: "3", Name: "rate-limiting", Service: "Produtos", Enabled: &enabled,
+					Config: map[string]interface{}{"minute": 10, "hour": nil, "policy": "local"}},
+			},
+		}
+
+		got, err := planKongState(desired, current)
+		if err != nil {
+			t.Fatalf("failed planning state: success expected: result: %s", err.Error())
+		}
+
+		want := []KongStateChange{
+			{Entity: stateEntityService, Key: "Produtos", Action: stateActionUpdate, Id: "1"},
+			{Entity: stateEntityService, Key: "Pedidos", Action: stateActionCreate},
+			{Entity: stateEntityService, Key: "Clientes", Action: stateActionDelete, Id: "2"},
+		}
+
+		if len(want) != len(got) {
+			t.Fatalf("failed planning state: changes expected: %d result: %d", len(want), len(got))
+		}
+
+		for i := range want {
+			if want[i].Entity != got[i].Entity || want[i].Key != got[i].Key || want[i].Action != got[i].Action || want[i].Id != got[i].Id {
+				t.Errorf("failed planning state: change expected: %+v result: %+v", want[i], got[i])
+			}
+		}
+
+		if len(got[0].Fields) != 1 || got[0].Fields[0].Field != "port" {
+			t.Errorf("failed planning state: port change expected: result: %+v", got[0].Fields)
+		}
+	})
+
+	t.Run(">>> planKongState: scenario 2 - route moved to a new service before the old one is deleted", func(t *testing.T) {
+
+		desired := &KongState{
+			Services: []KongStateService{{Name: "Produtos-v2", Host: "192.168.68.108", Port: 8080}},
+			Routes:   []KongStateRoute{{Name: "Produtos", Service: "Produtos-v2"}},
+		}
+		current := &KongState{
+			Services: []KongStateService{{id: "1", Name: "Produtos", Host: "192.168.68.107", Port: 8080}},
+			Routes:   []KongStateRoute{{id: "2", Name: "Produtos", Service: "Produtos"}},
+		}
+
+		got, err := planKongState(desired, current)
+		if err != nil {
+			t.Fatalf("failed planning state: success expected: result: %s", err.Error())
+		}
+
+		var summary []string
+		for _, change := range got {
+			summary = append(summary, change.Action+" "+change.Entity+": "+change.Key)
+		}
+
+		want := "create service: Produtos-v2 update route: Produtos delete service: Produtos"
+		if strings.Join(summary, " ") != want {
+			t.Errorf("failed planning state: changes expected: %s result: %s", want, strings.Join(summary, " "))
+		}
+	})
+
+	t.Run(">>> planKongState: scenario 3 - nested config attributes", func(t *testing.T) {
+
+		desired := &KongState{
+			Plugins: []KongStatePlugin{
+				{Name: "rate-limiting", Config: map[string]interface{}{"minute": 20, "redis": map[string]interface{}{"host": "redis"}}},
+			},
+		}
+		current := &KongState{
+			Plugins: []KongStatePlugin{
+				{id: "1", Name: "rate-limiting", Config: map[string]interface{}{"minute": 10, "hour": 100, "redis": map[string]interface{}{"host": "localhost", "port": 6379}}},
+			},
+		}
+
+		got, err := planKongState(desired, current)
+		if err != nil {
+			t.Fatalf("failed planning state: success expected: result: %s", err.Error())
+		}
+
+		if len(got) != 1 || len(got[0].Fields) != 2 || got[0].Fields[0].Field != "config.minute" || got[0].Fields[1].Field != "config.redis.host" {
+			t.Errorf("failed planning state: config.minute and config.redis.host changes expected: result: %+v", got)
+		}
+	})
+
+	t.Run(">>> planKongState: scenario 4 - targets of a deleted upstream", func(t *testing.T) {
+
+		current := &KongState{
+			Upstreams: []KongStateUpstream{
+				{id: "1", Name: "Pedidos", Targets: []KongStateUpstreamTarget{{id: "2", Target: "192.168.68.107:8080"}}},
+			},
+		}
+
+		got, err := planKongState(&KongState{}, current)
+		if err != nil {
+			t.Fatalf("failed planning state: success expected: result: %s", err.Error())
+		}
+
+		if len(got) != 1 || got[0].Entity != stateEntityUpstream || got[0].Action != stateActionDelete {
+			t.Errorf("failed planning state: single upstream delete expected: result: %+v", got)
+		}
+	})
+
+	t.Run(">>> planKongState: scenario 5 - service retries and timeouts", func(t *testing.T) {
+
+		retries, currentRetries, timeout := 3, 5, 60000
+		desired := &KongState{
+			Services: []KongStateService{
+				{Name: "Produtos", Retries: &retries, ReadTimeout: &timeout},
+				{Name: "Pedidos"},
+			},
+		}
+		current := &KongState{
+			Services: []KongStateService{
+				{id: "1", Name: "Produtos", Retries: &currentRetries, ReadTimeout: &timeout, WriteTimeout: &timeout},
+				{id: "2", Name: "Pedidos", Retries: &currentRetries, ReadTimeout: &timeout},
+			},
+		}
+
+		got, err := planKongState(desired, current)
+		if err != nil {
+			t.Fatalf("failed planning state: success expected: result: %s", err.Error())
+		}
+
+		if len(got) != 1 || got[0].Key != "Produtos" || len(got[0].Fields) != 1 || got[0].Fields[0].Field != "retries" {
+			t.Errorf("failed planning state: single retries change expected: result: %+v", got)
+		}
+	})
+
+	t.Run(">>> planKongState: scenario 6 - route headers compared as a whole", func(t *testing.T) {
+
+		stripPath := false
+		desired := &KongState{
+			Routes: []KongStateRoute{
+				{Name: "Produtos", Hosts: []string{"api.example.com"}, Headers: map[string][]string{"x-version": {"v2"}}, StripPath: &stripPath},
+			},
+		}
+		current := &KongState{
+			Routes: []KongStateRoute{
+				{id: "1", Name: "Produtos", Hosts: []string{"api.example.com"}, StripPath: &stripPath,
+					Headers: map[string][]string{"x-version": {"v2"}, "x-tenant": {"acme"}}},
+			},
+		}
+
+		got, err := planKongState(desired, current)
+		if err != nil {
+			t.Fatalf("failed planning state: success expected: result: %s", err.Error())
+		}
+
+		if len(got) != 1 || len(got[0].Fields) != 1 || got[0].Fields[0].Field != "headers" {
+			t.Errorf("failed planning state: single headers change expected: result: %+v", got)
+		}
+	})
+
+	t.Run(">>> planKongState: scenario 7 - consumer ACL groups and HMAC auth", func(t *testing.T) {
+
+		desired := &KongState{
+			Consumers: []KongStateConsumer{
+				{UserName: "guest", ACL: []KongStateACL{{Group: "partners"}}, HMACAuth: []KongStateHMACAuth{{UserName: "guest-hmac", Secret: "n3w"}}},
+			},
+		}
+		current := &KongState{
+			Consumers: []KongStateConsumer{
+				{id: "c1", UserName: "guest", ACL: []KongStateACL{{id: "a1", Group: "admins"}},
+					HMACAuth: []KongStateHMACAuth{{id: "h1", UserName: "guest-hmac", Secret: "old"}}},
+			},
+		}
+
+		got, err := planKongState(desired, current)
+		if err != nil {
+			t.Fatalf("failed planning state: success expected: result: %s", err.Error())
+		}
+
+		var summary []string
+		for _, change := range got {
+			summary = append(summary, change.Action+" "+change.Entity+": "+change.Key)
+		}
+
+		want := "create consumer-acl: guest/partners update consumer-hmac-auth: guest/guest-hmac delete consumer-acl: guest/admins"
+		if strings.Join(summary, " ") != want {
+			t.Errorf("failed planning state: changes expected: %s result: %s", want, strings.Join(summary, " "))
+		}
+	})
+
+	t.Run(">>> planKongState: scenario 8 - upstream hashing attributes", func(t *testing.T) {
+
+		slots := 10000
+		desired := &KongState{
+			Upstreams: []KongStateUpstream{
+				{Name: "Pedidos", Algorithm: "consistent-hashing", KongUpstreamHashing: KongUpstreamHashing{HashOn: "ip"}, Slots: &slots},
+			},
+		}
+		current := &KongState{
+			Upstreams: []KongStateUpstream{
+				{id: "u1", Name: "Pedidos", Algorithm: "consistent-hashing", Slots: &slots,
+					KongUpstreamHashing: KongUpstreamHashing{HashOn: "header", HashOnHeader: "x-tenant", HashFallback: "none"}},
+			},
+		}
+
+		got, err := planKongState(desired, current)
+		if err != nil {
+			t.Fatalf("failed planning state: success expected: result: %s", err.Error())
+		}
+
+		if len(got) != 1 || len(got[0].Fields) != 1 || got[0].Fields[0].Field != "hash_on" || got[0].Fields[0].Desired != "ip" {
+			t.Errorf("failed planning state: single hash_on change expected: result: %+v", got)
+		}
+	})
+}
@@ -0,0 +1,89 @@
+////////////////////////////////////////////////////////////////////////////////
+//	tls.go  -  Oct-17-2026  -  aldebap
+//
+//	TLS and mutual TLS connection to Kong Admin API
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http"
+	"os"
+)
+
+// Kong Admin API TLS attributes
+type KongTLS struct {
+	caCert             string
+	clientCert         string
+	clientKey          string
+	serverName         string
+	insecureSkipVerify bool
+}
+
+// create a new Kong Admin API TLS configuration
+func NewKongTLS(caCert string, clientCert string, clientKey string, serverName string, insecureSkipVerify bool) *KongTLS {
+
+	return &KongTLS{
+		caCert:             caCert,
+		clientCert:         clientCert,
+		clientKey:          clientKey,
+		serverName:         serverName,
+		insecureSkipVerify: insecureSkipVerify,
+	}
+}
+
+// create the TLS client configuration: the CA certificate to verify Kong and the client certificate for mutual TLS
+func (kt *KongTLS) clientConfig() (*tls.Config, error) {
+
+	tlsConfig := &tls.Config{
+		ServerName:         kt.serverName,
+		InsecureSkipVerify: kt.insecureSkipVerify,
+	}
+
+	if len(kt.caCert) > 0 {
+		caCertPEM, err := os.ReadFile(kt.caCert)
+		if err != nil {
+			return nil, err
+		}
+
+		tlsConfig.RootCAs = x509.NewCertPool()
+		if !tlsConfig.RootCAs.AppendCertsFromPEM(caCertPEM) {
+			return nil, errors.New("no valid certificate found in CA certificate file: " + kt.caCert)
+		}
+	}
+
+	if len(kt.clientCert) > 0 || len(kt.clientKey) > 0 {
+		if len(kt.clientCert) == 0 || len(kt.clientKey) == 0 {
+			return nil, errors.New("both client certificate and client key are required for mutual TLS")
+		}
+
+		clientCert, err := tls.LoadX509KeyPair(kt.clientCert, kt.clientKey)
+		if err != nil {
+			return nil, errors.New("fail loading client certificate: " + err.Error())
+		}
+		tlsConfig.Certificates = []tls.Certificate{clientCert}
+	}
+
+	return tlsConfig, nil
+}
+
+// create the HTTP client shared by all requests to Kong Admin API
+func newKongHTTPClient(kongTLS *KongTLS) (*http.Client, error) {
+
+	if kongTLS == nil {
+		return &http.Client{}, nil
+	}
+
+	tlsConfig, err := kongTLS.clientConfig()
+	if err != nil {
+		return nil, err
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.TLSClientConfig = tlsConfig
+
+	return &http.Client{Transport: transport}, nil
+}
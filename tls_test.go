@@ -0,0 +1,238 @@
+////////////////////////////////////////////////////////////////////////////////
+//	tls_test.go  -  Oct-17-2026  -  aldebap
+//
+//	Test cases for TLS connection to Kong Admin API
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+)
+
+// write the mock server certificate as a CA certificate file
+func writeCACertFile(t *testing.T, server *httptest.Server) string {
+
+	caCertFile := filepath.Join(t.TempDir(), "ca.pem")
+
+	err := os.WriteFile(caCertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw}), 0644)
+	if err != nil {
+		t.Fatalf("fail writing CA certificate file: %s", err.Error())
+	}
+
+	return caCertFile
+}
+
+// write a self signed client certificate and key files
+func writeClientCertFiles(t *testing.T) (string, string) {
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatalf("fail generating client key: %s", err.Error())
+	}
+
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "kconf"},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+
+	certDER, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("fail generating client certificate: %s", err.Error())
+	}
+
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	if err != nil {
+		t.Fatalf("fail encoding client key: %s", err.Error())
+	}
+
+	dir := t.TempDir()
+	certFile := filepath.Join(dir, "client.pem")
+	keyFile := filepath.Join(dir, "client-key.pem")
+
+	os.WriteFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: certDER}), 0644)
+	os.WriteFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0600)
+
+	return certFile, keyFile
+}
+
+// split the mock server URL in address and port
+func serverAddressPort(t *testing.T, server *httptest.Server) (string, int) {
+
+	serverURL, err := url.Parse(server.URL)
+	if err != nil {
+		t.Fatalf("fail parsing mock server URL: %s", err.Error())
+	}
+
+	port, _ := strconv.Atoi(serverURL.Port())
+
+	return serverURL.Hostname(), port
+}
+
+// Test_TLSConnection unit tests for TLS connection to Kong Admin API
+func Test_TLSConnection(t *testing.T) {
+
+	//	mock for Kong Admin with HTTPS
+	var mockKongAdmin *httptest.Server = httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer mockKongAdmin.Close()
+
+	address, port := serverAddressPort(t, mockKongAdmin)
+	caCertFile := writeCACertFile(t, mockKongAdmin)
+
+	t.Run(">>> TLSConnection: scenario 1 - https URL with CA certificate", func(t *testing.T) {
+
+		kongServer, err := NewKongServerConnection(address, port, NewKongConnection("", nil, "", "", NewKongTLS(caCertFile, "", "", "", false), ""))
+		if err != nil {
+			t.Fatalf("fail connectring to mock Kong Admin: %s", err.Error())
+		}
+
+		if kongServer.ServerURL() != mockKongAdmin.URL {
+			t.Errorf("failed building server URL: expected: %s result: %s", mockKongAdmin.URL, kongServer.ServerURL())
+		}
+
+		got := kongServer.CheckStatus(Options{})
+		if got != nil {
+			t.Errorf("failed checking kong status: success expected: result: %s", got.Error())
+		}
+	})
+
+	t.Run(">>> TLSConnection: scenario 2 - unknown certificate authority", func(t *testing.T) {
+
+		kongServer, err := NewKongServerConnection(address, port, NewKongConnection("", nil, "", "", NewKongTLS("", "", "", "", false), ""))
+		if err != nil {
+			t.Fatalf("fail connectring to mock Kong Admin: %s", err.Error())
+		}
+
+		got := kongServer.CheckStatus(Options{})
+		if got == nil {
+			t.Errorf("failed checking kong status: certificate verification error expected")
+		}
+	})
+
+	t.Run(">>> TLSConnection: scenario 3 - insecure skip verify", func(t *testing.T) {
+
+		kongServer, err := NewKongServerConnection(address, port, NewKongConnection("", nil, "", "", NewKongTLS("", "", "", "", true), ""))
+		if err != nil {
+			t.Fatalf("fail connectring to mock Kong Admin: %s", err.Error())
+		}
+
+		got := kongServer.CheckStatus(Options{})
+		if got != nil {
+			t.Errorf("failed checking kong status: success expected: result: %s", got.Error())
+		}
+	})
+
+	t.Run(">>> TLSConnection: scenario 4 - TLS server name", func(t *testing.T) {
+
+		//	the mock server certificate is valid for example.com
+		kongServer, err := NewKongServerConnection(address, port, NewKongConnection("", nil, "", "", NewKongTLS(caCertFile, "", "", "example.com", false), ""))
+		if err != nil {
+			t.Fatalf("fail connectring to mock Kong Admin: %s", err.Error())
+		}
+
+		got := kongServer.CheckStatus(Options{})
+		if got != nil {
+			t.Errorf("failed checking kong status: success expected: result: %s", got.Error())
+		}
+
+		kongServer, err = NewKongServerConnection(address, port, NewKongConnection("", nil, "", "", NewKongTLS(caCertFile, "", "", "kong.local", false), ""))
+		if err != nil {
+			t.Fatalf("fail connectring to mock Kong Admin: %s", err.Error())
+		}
+
+		got = kongServer.CheckStatus(Options{})
+		if got == nil {
+			t.Errorf("failed checking kong status: certificate name mismatch error expected")
+		}
+	})
+
+	t.Run(">>> TLSConnection: scenario 5 - invalid CA certificate file", func(t *testing.T) {
+
+		invalidCACertFile := filepath.Join(t.TempDir(), "ca.pem")
+		os.WriteFile(invalidCACertFile, []byte("not a certificate"), 0644)
+
+		_, err := NewKongServerConnection(address, port, NewKongConnection("", nil, "", "", NewKongTLS(invalidCACertFile, "", "", "", false), ""))
+		if err == nil || err.Error() != "no valid certificate found in CA certificate file: "+invalidCACertFile {
+			t.Errorf("failed connecting to Kong: invalid CA certificate error expected: result: %v", err)
+		}
+	})
+}
+
+// Test_MutualTLSConnection unit tests for mutual TLS connection to Kong Admin API
+func Test_MutualTLSConnection(t *testing.T) {
+
+	var clientCommonName string
+
+	//	mock for Kong Admin requiring a client certificate
+	var mockKongAdmin *httptest.Server = httptest.NewUnstartedServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		clientCommonName = r.TLS.PeerCertificates[0].Subject.CommonName
+		w.WriteHeader(http.StatusOK)
+	}))
+	mockKongAdmin.TLS = &tls.Config{ClientAuth: tls.RequireAnyClientCert}
+	mockKongAdmin.StartTLS()
+	defer mockKongAdmin.Close()
+
+	address, port := serverAddressPort(t, mockKongAdmin)
+	caCertFile := writeCACertFile(t, mockKongAdmin)
+	clientCertFile, clientKeyFile := writeClientCertFiles(t)
+
+	t.Run(">>> MutualTLSConnection: scenario 1 - client certificate sent", func(t *testing.T) {
+
+		kongServer, err := NewKongServerConnection(address, port, NewKongConnection("", nil, "", "", NewKongTLS(caCertFile, clientCertFile, clientKeyFile, "", false), ""))
+		if err != nil {
+			t.Fatalf("fail connectring to mock Kong Admin: %s", err.Error())
+		}
+
+		got := kongServer.CheckStatus(Options{})
+		if got != nil {
+			t.Fatalf("failed checking kong status: success expected: result: %s", got.Error())
+		}
+
+		if clientCommonName != "kconf" {
+			t.Errorf("failed checking kong status: client certificate expected: kconf result: %s", clientCommonName)
+		}
+	})
+
+	t.Run(">>> MutualTLSConnection: scenario 2 - missing client certificate", func(t *testing.T) {
+
+		kongServer, err := NewKongServerConnection(address, port, NewKongConnection("", nil, "", "", NewKongTLS(caCertFile, "", "", "", false), ""))
+		if err != nil {
+			t.Fatalf("fail connectring to mock Kong Admin: %s", err.Error())
+		}
+
+		got := kongServer.CheckStatus(Options{})
+		if got == nil {
+			t.Errorf("failed checking kong status: handshake error expected")
+		}
+	})
+
+	t.Run(">>> MutualTLSConnection: scenario 3 - client key missing", func(t *testing.T) {
+
+		want := "both client certificate and client key are required for mutual TLS"
+		_, got := NewKongServerConnection(address, port, NewKongConnection("", nil, "", "", NewKongTLS(caCertFile, clientCertFile, "", "", false), ""))
+
+		if got == nil || want != got.Error() {
+			t.Errorf("failed connecting to Kong: error expected: %s result: %v", want, got)
+		}
+	})
+}
@@ -7,19 +7,38 @@
 package main
 
 import (
-	"bytes"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
+	"strings"
 )
 
 // kong upstream attributes
 type KongUpstream struct {
-	name      string
-	algorithm string
-	tags      []string
+	name         string
+	algorithm    string
+	hashing      KongUpstreamHashing
+	slots        *int
+	hostHeader   string
+	useSrvName   *bool
+	healthchecks *KongUpstreamHealthchecks
+	tags         []string
+}
+
+// kong upstream consistent hashing attributes
+type KongUpstreamHashing struct {
+	HashOn                 string `json:"hash_on,omitempty" yaml:"hash_on,omitempty"`
+	HashFallback           string `json:"hash_fallback,omitempty" yaml:"hash_fallback,omitempty"`
+	HashOnHeader           string `json:"hash_on_header,omitempty" yaml:"hash_on_header,omitempty"`
+	HashFallbackHeader     string `json:"hash_fallback_header,omitempty" yaml:"hash_fallback_header,omitempty"`
+	HashOnCookie           string `json:"hash_on_cookie,omitempty" yaml:"hash_on_cookie,omitempty"`
+	HashOnCookiePath       string `json:"hash_on_cookie_path,omitempty" yaml:"hash_on_cookie_path,omitempty"`
+	HashOnQueryArg         string `json:"hash_on_query_arg,omitempty" yaml:"hash_on_query_arg,omitempty"`
+	HashFallbackQueryArg   string `json:"hash_fallback_query_arg,omitempty" yaml:"hash_fallback_query_arg,omitempty"`
+	HashOnURICapture       string `json:"hash_on_uri_capture,omitempty" yaml:"hash_on_uri_capture,omitempty"`
+	HashFallbackURICapture string `json:"hash_fallback_uri_capture,omitempty" yaml:"hash_fallback_uri_capture,omitempty"`
 }
 
 // create a new Kong upstream
@@ -32,19 +51,47 @@ func NewKongUpstream(name string, algorithm string, tags []string) *KongUpstream
 	}
 }
 
+// set the load balancing attributes of the upstream
+func (upstream *KongUpstream) setBalancing(hashing KongUpstreamHashing, slots *int, hostHeader string, useSrvName *bool) {
+
+	upstream.hashing = hashing
+	upstream.slots = slots
+	upstream.hostHeader = hostHeader
+	upstream.useSrvName = useSrvName
+}
+
+// set the health checks of the upstream
+func (upstream *KongUpstream) setHealthchecks(healthchecks *KongUpstreamHealthchecks) {
+
+	if healthchecks != nil && healthchecks.isEmpty() {
+		healthchecks = nil
+	}
+	upstream.healthchecks = healthchecks
+}
+
 // kong upstream request payload
 type KongUpstreamRequest struct {
-	Name      string   `json:"name,omitempty"`
-	Algorithm string   `json:"algorithm,omitempty"`
-	Tags      []string `json:"tags,omitempty"`
+	Name      string `json:"name,omitempty"`
+	Algorithm string `json:"algorithm,omitempty"`
+	KongUpstreamHashing
+	Slots        *int                      `json:"slots,omitempty"`
+	HostHeader   string                    `json:"host_header,omitempty"`
+	UseSrvName   *bool                     `json:"use_srv_name,omitempty"`
+	Healthchecks *KongUpstreamHealthchecks `json:"healthchecks,omitempty"`
+	Tags         []string                  `json:"tags,omitempty"`
 }
 
 // kong upstream response payload
 type KongUpstreamResponse struct {
-	Id        string   `json:"id"`
-	Name      string   `json:"name"`
-	Algorithm string   `json:"algorithm,omitempty"`
-	Tags      []string `json:"tags,omitempty"`
+	Id        string `json:"id"`
+	Name      string `json:"name"`
+	Algorithm string `json:"algorithm,omitempty"`
+	KongUpstreamHashing
+	Slots        int                       `json:"slots,omitempty"`
+	HostHeader   string                    `json:"host_header,omitempty"`
+	UseSrvName   bool                      `json:"use_srv_name,omitempty"`
+	Healthchecks *KongUpstreamHealthchecks `json:"healthchecks,omitempty"`
+	Tags         []string                  `json:"tags,omitempty"`
 }
 
 // kong upstream list response payload
@@ -55,37 +102,194 @@ type KongUpstreamListResponse struct {
 
 const (
 	upstreamResource string = "upstreams"
+	minUpstreamSlots int    = 10
+	maxUpstreamSlots int    = 65536
+)
+
+var (
+	upstreamAlgorithms    = []string{"round-robin", "consistent-hashing", "least-connections", "latency"}
+	upstreamHashInputs    = []string{"none", "consumer", "ip", "header", "cookie", "path", "query_arg", "uri_capture"}
+	upstreamHashFallbacks = []string{"none", "consumer", "ip", "header", "path", "query_arg", "uri_capture"}
 )
 
+// check if a value is one of the valid values of an option
+func validOptionValue(option string, value string, validValues []string) error {
+
+	for _, validValue := range validValues {
+		if value == validValue {
+			return nil
+		}
+	}
+
+	return errors.New("wrong value for option --" + option + ": " + value + ": must be one of " + strings.Join(validValues, ", "))
+}
+
+// validate the upstream load balancing attributes: as an update may change a single attribute, the dependencies
+// between hash inputs and their companion attributes are only checked when the hash input is set; the cookie path
+// is the exception, as it only makes sense when the hash input is set to cookie in the same command
+func (upstream *KongUpstream) validate() error {
+
+	if len(upstream.algorithm) > 0 {
+		err := validOptionValue("algorithm", upstream.algorithm, upstreamAlgorithms)
+		if err != nil {
+			return err
+		}
+	}
+
+	hashing := upstream.hashing
+
+	if len(hashing.HashOn) > 0 {
+		err := validOptionValue("hash-on", hashing.HashOn, upstreamHashInputs)
+		if err != nil {
+			return err
+		}
+
+		//	Kong only hashes with the consistent-hashing algorithm, ignoring the hash input otherwise
+		if hashing.HashOn != "none" && len(upstream.algorithm) > 0 && upstream.algorithm != "consistent-hashing" {
+			return errors.New("option --hash-on=" + hashing.HashOn + " requires option --algorithm=consistent-hashing")
+		}
+
+		err = validateHashCompanions("hash-on", hashing.HashOn, []hashCompanion{
+			{input: "header", value: hashing.HashOnHeader},
+			{input: "cookie", value: hashing.HashOnCookie},
+			{input: "query_arg", value: hashing.HashOnQueryArg},
+			{input: "uri_capture", value: hashing.HashOnURICapture},
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if len(hashing.HashOnCookiePath) > 0 && hashing.HashOn != "cookie" {
+		return errors.New("option --hash-on-cookie-path requires option --hash-on=cookie")
+	}
+
+	if len(hashing.HashFallback) > 0 {
+		err := validOptionValue("hash-fallback", hashing.HashFallback, upstreamHashFallbacks)
+		if err != nil {
+			return err
+		}
+
+		err = validateHashCompanions("hash-fallback", hashing.HashFallback, []hashCompanion{
+			{input: "header", value: hashing.HashFallbackHeader},
+			{input: "query_arg", value: hashing.HashFallbackQueryArg},
+			{input: "uri_capture", value: hashing.HashFallbackURICapture},
+		})
+		if err != nil {
+			return err
+		}
+
+		if hashing.HashFallback != "none" {
+			switch hashing.HashOn {
+			case "none", "cookie":
+				return errors.New("option --hash-fallback=" + hashing.HashFallback + " can't be used with option --hash-on=" + hashing.HashOn)
+
+			case hashing.HashFallback:
+				//	the same hash input can only be a fallback with a different header, query argument or URI capture
+				var distinct bool
+
+				switch hashing.HashOn {
+				case "header":
+					distinct = hashing.HashOnHeader != hashing.HashFallbackHeader
+
+				case "query_arg":
+					distinct = hashing.HashOnQueryArg != hashing.HashFallbackQueryArg
+
+				case "uri_capture":
+					distinct = hashing.HashOnURICapture != hashing.HashFallbackURICapture
+				}
+
+				if !distinct {
+					return errors.New("option --hash-fallback must be different from option --hash-on")
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+// companion attribute of a hash input (the header name for hash input header, etc)
+type hashCompanion struct {
+	input string
+	value string
+}
+
+// check the companion attributes of a hash input: the one for the selected input is required and the others can't be used
+func validateHashCompanions(option string, hashInput string, companions []hashCompanion) error {
+
+	for _, companion := range companions {
+		companionOption := option + "-" + strings.ReplaceAll(companion.input, "_", "-")
+
+		if companion.input == hashInput && len(companion.value) == 0 {
+			return errors.New("missing option --" + companionOption + ": required for option --" + option + "=" + hashInput)
+		}
+
+		if companion.input != hashInput && len(companion.value) > 0 {
+			return errors.New("option --" + companionOption + " requires option --" + option + "=" + companion.input)
+		}
+	}
+
+	return nil
+}
+
+// summary of the consistent hashing attributes of an upstream
+func hashingSummary(hashing KongUpstreamHashing) string {
+
+	describe := func(hashInput string, header string, cookie string, queryArg string, uriCapture string) string {
+		switch hashInput {
+		case "header":
+			return "header " + header
+
+		case "cookie":
+			return "cookie " + cookie
+
+		case "query_arg":
+			return "query argument " + queryArg
+
+		case "uri_capture":
+			return "URI capture " + uriCapture
+		}
+
+		return hashInput
+	}
+
+	summary := "hash on " + describe(hashing.HashOn, hashing.HashOnHeader, hashing.HashOnCookie, hashing.HashOnQueryArg, hashing.HashOnURICapture)
+
+	if len(hashing.HashFallback) > 0 && hashing.HashFallback != "none" {
+		summary += ", fallback " + describe(hashing.HashFallback, hashing.HashFallbackHeader, "", hashing.HashFallbackQueryArg, hashing.HashFallbackURICapture)
+	}
+
+	return summary
+}
+
 // add a new upstream to Kong
 func (ks *KongServerDomain) AddUpstream(newKongUpstream *KongUpstream, options Options) error {
 
 	var upstreamURL string = fmt.Sprintf("%s/%s", ks.ServerURL(), upstreamResource)
 
 	payload, err := json.Marshal(KongUpstreamRequest{
-		Name:      newKongUpstream.name,
-		Algorithm: newKongUpstream.algorithm,
-		Tags:      newKongUpstream.tags,
+		Name:                newKongUpstream.name,
+		Algorithm:           newKongUpstream.algorithm,
+		KongUpstreamHashing: newKongUpstream.hashing,
+		Slots:               newKongUpstream.slots,
+		HostHeader:          newKongUpstream.hostHeader,
+		UseSrvName:          newKongUpstream.useSrvName,
+		Healthchecks:        newKongUpstream.healthchecks,
+		Tags:                newKongUpstream.tags,
 	})
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequest("POST", upstreamURL, bytes.NewBuffer([]byte(payload)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ks.sendRequest("POST", upstreamURL, payload)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		return errors.New("fail sending add upstream command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending add upstream command to Kong", resp)
 	}
 
 	//	parse response payload
@@ -122,17 +326,17 @@ func (ks *KongServerDomain) QueryUpstream(id string, options Options) error {
 	var upstreamURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), upstreamResource, id)
 
 	//	send a request to Kong to query the upstream by id
-	resp, err := http.Get(upstreamURL)
+	resp, err := ks.sendRequest("GET", upstreamURL, nil)
 	if err != nil {
 		return err
 	}
 
 	if resp.StatusCode == http.StatusNotFound {
-		return errors.New("upstream not found")
+		return newKongNotFoundError("upstream", resp)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.New("fail sending query upstream command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending query upstream command to Kong", resp)
 	}
 
 	var respPayload []byte
@@ -159,35 +363,31 @@ func (ks *KongServerDomain) QueryUpstream(id string, options Options) error {
 			fmt.Printf("upstream: %s --> %s (%s)\n",
 				upstreamResp.Name, upstreamResp.Algorithm, upstreamResp.Tags)
 		}
+		if len(upstreamResp.HashOn) > 0 && upstreamResp.HashOn != "none" {
+			fmt.Printf("hashing: %s\n", hashingSummary(upstreamResp.KongUpstreamHashing))
+		}
+		fmt.Printf("health checks: %s\n", healthchecksSummary(upstreamResp.Healthchecks))
 	}
 
 	return nil
 }
 
 // list all upstreams
-func (ks *KongServerDomain) ListUpstreams(options Options) error {
-
-	var upstreamURL string = fmt.Sprintf("%s/%s/", ks.ServerURL(), upstreamResource)
+func (ks *KongServerDomain) ListUpstreams(pagination *KongPagination, options Options) error {
 
 	//	send a request to Kong to get a list of all upstreams
-	resp, err := http.Get(upstreamURL)
+	collection, status, err := ks.fetchCollection(upstreamResource, pagination, "fail sending list upstreams command to Kong")
 	if err != nil {
 		return err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return errors.New("fail sending list upstreams command to Kong: " + resp.Status)
-	}
-
-	var respPayload []byte
-
-	respPayload, err = io.ReadAll(resp.Body)
+	respPayload, err := json.Marshal(collection)
 	if err != nil {
 		return err
 	}
 
 	if options.jsonOutput {
-		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+		fmt.Printf("%s\n%s\n", status, string(respPayload))
 	} else {
 		var upstreamListResp KongUpstreamListResponse
 
@@ -198,7 +398,7 @@ func (ks *KongServerDomain) ListUpstreams(options Options) error {
 
 		if len(upstreamListResp.Data) == 0 {
 			if options.verbose {
-				fmt.Printf("%s\nNo upstreams\n", resp.Status)
+				fmt.Printf("%s\nNo upstreams\n", status)
 			} else {
 				fmt.Printf("No upstreams\n")
 			}
@@ -207,13 +407,14 @@ func (ks *KongServerDomain) ListUpstreams(options Options) error {
 		}
 
 		if options.verbose {
-			fmt.Printf("http response status code: %s\nupstream list\n", resp.Status)
+			fmt.Printf("http response status code: %s\nupstream list\n", status)
 		}
 
 		for _, upstream := range upstreamListResp.Data {
 			fmt.Printf("%s: %s --> %s (%s)\n", upstream.Id,
 				upstream.Name, upstream.Algorithm, upstream.Tags)
 		}
+		printNextOffset(collection)
 	}
 
 	return nil
@@ -225,9 +426,14 @@ func (ks *KongServerDomain) UpdateUpstream(id string, updatedKongUpstream *KongU
 	var upstreamURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), upstreamResource, id)
 
 	payload, err := json.Marshal(KongUpstreamRequest{
-		Name:      updatedKongUpstream.name,
-		Algorithm: updatedKongUpstream.algorithm,
-		Tags:      updatedKongUpstream.tags,
+		Name:                updatedKongUpstream.name,
+		Algorithm:           updatedKongUpstream.algorithm,
+		KongUpstreamHashing: updatedKongUpstream.hashing,
+		Slots:               updatedKongUpstream.slots,
+		HostHeader:          updatedKongUpstream.hostHeader,
+		UseSrvName:          updatedKongUpstream.useSrvName,
+		Healthchecks:        updatedKongUpstream.healthchecks,
+		Tags:                updatedKongUpstream.tags,
 	})
 	if err != nil {
 		return err
@@ -235,25 +441,18 @@ func (ks *KongServerDomain) UpdateUpstream(id string, updatedKongUpstream *KongU
 
 	//	log.Printf("[debug] patch payload: %s", payload)
 
-	req, err := http.NewRequest("PATCH", upstreamURL, bytes.NewBuffer([]byte(payload)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ks.sendRequest("PATCH", upstreamURL, payload)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode == http.StatusNotFound {
-		return errors.New("upstream not found")
+		return newKongNotFoundError("upstream", resp)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.New("fail sending patch upstream command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending patch upstream command to Kong", resp)
 	}
 
 	//	parse response payload
@@ -292,21 +491,14 @@ func (ks *KongServerDomain) DeleteUpstream(id string, options Options) error {
 	var upstreamURL string = fmt.Sprintf("%s/%s/%s", ks.ServerURL(), upstreamResource, id)
 
 	//	send a request to Kong to delete the upstream by id
-	req, err := http.NewRequest("DELETE", upstreamURL, bytes.NewBuffer([]byte("")))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ks.sendRequest("DELETE", upstreamURL, nil)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		return errors.New("fail sending delete upstream command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending delete upstream command to Kong", resp)
 	}
 
 	if options.jsonOutput {
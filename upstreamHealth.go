@@ -0,0 +1,336 @@
+////////////////////////////////////////////////////////////////////////////////
+//	upstreamHealth.go  -  Oct-17-2026  -  aldebap
+//
+//	Kong upstream health checks configuration and targets health
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// kong upstream health checks thresholds to consider a target healthy
+type KongHealthcheckHealthy struct {
+	Interval     *float64 `json:"interval,omitempty" yaml:"interval,omitempty"`
+	HTTPStatuses []int    `json:"http_statuses,omitempty" yaml:"http_statuses,omitempty"`
+	Successes    *int     `json:"successes,omitempty" yaml:"successes,omitempty"`
+}
+
+// kong upstream health checks thresholds to consider a target unhealthy
+type KongHealthcheckUnhealthy struct {
+	Interval     *float64 `json:"interval,omitempty" yaml:"interval,omitempty"`
+	HTTPStatuses []int    `json:"http_statuses,omitempty" yaml:"http_statuses,omitempty"`
+	HTTPFailures *int     `json:"http_failures,omitempty" yaml:"http_failures,omitempty"`
+	TCPFailures  *int     `json:"tcp_failures,omitempty" yaml:"tcp_failures,omitempty"`
+	Timeouts     *int     `json:"timeouts,omitempty" yaml:"timeouts,omitempty"`
+}
+
+// kong upstream active health checks: targets are probed periodically
+type KongActiveHealthcheck struct {
+	Type                   string                    `json:"type,omitempty" yaml:"type,omitempty"`
+	HTTPPath               string                    `json:"http_path,omitempty" yaml:"http_path,omitempty"`
+	HTTPSSni               string                    `json:"https_sni,omitempty" yaml:"https_sni,omitempty"`
+	HTTPSVerifyCertificate *bool                     `json:"https_verify_certificate,omitempty" yaml:"https_verify_certificate,omitempty"`
+	Timeout                *float64                  `json:"timeout,omitempty" yaml:"timeout,omitempty"`
+	Concurrency            *int                      `json:"concurrency,omitempty" yaml:"concurrency,omitempty"`
+	Healthy                *KongHealthcheckHealthy   `json:"healthy,omitempty" yaml:"healthy,omitempty"`
+	Unhealthy              *KongHealthcheckUnhealthy `json:"unhealthy,omitempty" yaml:"unhealthy,omitempty"`
+}
+
+// kong upstream passive health checks: targets are evaluated from proxied traffic
+type KongPassiveHealthcheck struct {
+	Type      string                    `json:"type,omitempty" yaml:"type,omitempty"`
+	Healthy   *KongHealthcheckHealthy   `json:"healthy,omitempty" yaml:"healthy,omitempty"`
+	Unhealthy *KongHealthcheckUnhealthy `json:"unhealthy,omitempty" yaml:"unhealthy,omitempty"`
+}
+
+// kong upstream health checks configuration
+type KongUpstreamHealthchecks struct {
+	Active    *KongActiveHealthcheck  `json:"active,omitempty" yaml:"active,omitempty"`
+	Passive   *KongPassiveHealthcheck `json:"passive,omitempty" yaml:"passive,omitempty"`
+	Threshold *float64                `json:"threshold,omitempty" yaml:"threshold,omitempty"`
+}
+
+// create a Kong upstream health checks object from a health checks file (YAML or JSON)
+func NewKongUpstreamHealthchecks(healthchecksFile string) (*KongUpstreamHealthchecks, error) {
+
+	healthchecks := &KongUpstreamHealthchecks{}
+
+	if len(healthchecksFile) == 0 {
+		return healthchecks, nil
+	}
+
+	content, err := os.ReadFile(healthchecksFile)
+	if err != nil {
+		return nil, err
+	}
+
+	switch strings.ToLower(filepath.Ext(healthchecksFile)) {
+	case ".yaml", ".yml":
+		err = yaml.Unmarshal(content, healthchecks)
+
+	default:
+		err = json.Unmarshal(content, healthchecks)
+	}
+	if err != nil {
+		return nil, errors.New("invalid health checks file " + healthchecksFile + ": " + err.Error())
+	}
+
+	return healthchecks, nil
+}
+
+// check if no health checks attribute was set
+func (healthchecks *KongUpstreamHealthchecks) isEmpty() bool {
+
+	return healthchecks.Active == nil && healthchecks.Passive == nil && healthchecks.Threshold == nil
+}
+
+// set the path probed by the active health checks
+func (healthchecks *KongUpstreamHealthchecks) setActiveHTTPPath(httpPath string) {
+
+	if healthchecks.Active == nil {
+		healthchecks.Active = &KongActiveHealthcheck{}
+	}
+	healthchecks.Active.HTTPPath = httpPath
+}
+
+// set a health checks threshold from an option like --{active|passive}-{healthy|unhealthy}-{counter}
+func (healthchecks *KongUpstreamHealthchecks) setThreshold(checkType string, state string, counter string, value string) error {
+
+	var option string = checkType + "-" + state + "-" + counter
+
+	if checkType == "passive" && counter == "interval" {
+		return errors.New("invalid option --" + option + ": passive health checks have no interval")
+	}
+	if state == "healthy" && counter != "interval" && counter != "successes" {
+		return errors.New("invalid option --" + option + ": healthy thresholds are interval and successes")
+	}
+	if state == "unhealthy" && counter == "successes" {
+		return errors.New("invalid option --" + option + ": unhealthy thresholds are interval, http-failures, tcp-failures and timeouts")
+	}
+
+	var healthy **KongHealthcheckHealthy
+	var unhealthy **KongHealthcheckUnhealthy
+
+	switch checkType {
+	case "active":
+		if healthchecks.Active == nil {
+			healthchecks.Active = &KongActiveHealthcheck{}
+		}
+		healthy = &healthchecks.Active.Healthy
+		unhealthy = &healthchecks.Active.Unhealthy
+
+	case "passive":
+		if healthchecks.Passive == nil {
+			healthchecks.Passive = &KongPassiveHealthcheck{}
+		}
+		healthy = &healthchecks.Passive.Healthy
+		unhealthy = &healthchecks.Passive.Unhealthy
+	}
+
+	if counter == "interval" {
+		interval, err := strconv.ParseFloat(value, 64)
+		if err != nil || interval < 0 || interval > 65535 {
+			return errors.New("wrong value for option --" + option + ": " + value + ": must be between 0 and 65535")
+		}
+
+		if state == "healthy" {
+			if *healthy == nil {
+				*healthy = &KongHealthcheckHealthy{}
+			}
+			(*healthy).Interval = &interval
+		} else {
+			if *unhealthy == nil {
+				*unhealthy = &KongHealthcheckUnhealthy{}
+			}
+			(*unhealthy).Interval = &interval
+		}
+
+		return nil
+	}
+
+	number, err := intOption(option, value, 0, 255)
+	if err != nil {
+		return err
+	}
+
+	if state == "healthy" {
+		if *healthy == nil {
+			*healthy = &KongHealthcheckHealthy{}
+		}
+		(*healthy).Successes = number
+
+		return nil
+	}
+
+	if *unhealthy == nil {
+		*unhealthy = &KongHealthcheckUnhealthy{}
+	}
+
+	switch counter {
+	case "http-failures":
+		(*unhealthy).HTTPFailures = number
+
+	case "tcp-failures":
+		(*unhealthy).TCPFailures = number
+
+	case "timeouts":
+		(*unhealthy).Timeouts = number
+	}
+
+	return nil
+}
+
+// summary of the health checks of an upstream
+func healthchecksSummary(healthchecks *KongUpstreamHealthchecks) string {
+
+	if healthchecks == nil {
+		return "disabled"
+	}
+
+	var checks []string
+
+	if active := healthchecks.Active; active != nil {
+		var healthyInterval, unhealthyInterval float64
+
+		if active.Healthy != nil && active.Healthy.Interval != nil {
+			healthyInterval = *active.Healthy.Interval
+		}
+		if active.Unhealthy != nil && active.Unhealthy.Interval != nil {
+			unhealthyInterval = *active.Unhealthy.Interval
+		}
+
+		if healthyInterval > 0 || unhealthyInterval > 0 {
+			checks = append(checks, fmt.Sprintf("active %s %s (healthy interval: %gs, unhealthy interval: %gs)",
+				active.Type, active.HTTPPath, healthyInterval, unhealthyInterval))
+		}
+	}
+
+	if passive := healthchecks.Passive; passive != nil && passive.Unhealthy != nil {
+		var unhealthy *KongHealthcheckUnhealthy = passive.Unhealthy
+		var failures []string
+
+		if unhealthy.HTTPFailures != nil && *unhealthy.HTTPFailures > 0 {
+			failures = append(failures, fmt.Sprintf("http failures: %d", *unhealthy.HTTPFailures))
+		}
+		if unhealthy.TCPFailures != nil && *unhealthy.TCPFailures > 0 {
+			failures = append(failures, fmt.Sprintf("tcp failures: %d", *unhealthy.TCPFailures))
+		}
+		if unhealthy.Timeouts != nil && *unhealthy.Timeouts > 0 {
+			failures = append(failures, fmt.Sprintf("timeouts: %d", *unhealthy.Timeouts))
+		}
+
+		if len(failures) > 0 {
+			checks = append(checks, fmt.Sprintf("passive %s (%s)", passive.Type, strings.Join(failures, ", ")))
+		}
+	}
+
+	if len(checks) == 0 {
+		return "disabled"
+	}
+
+	return strings.Join(checks, " ; ")
+}
+
+// kong upstream target health response payload
+type KongUpstreamTargetHealthResponse struct {
+	Id     string `json:"id"`
+	Target string `json:"target"`
+	Health string `json:"health"`
+	Weight int    `json:"weight"`
+	Data   struct {
+		Weight struct {
+			Total       int `json:"total"`
+			Available   int `json:"available"`
+			Unavailable int `json:"unavailable"`
+		} `json:"weight"`
+	} `json:"data"`
+}
+
+// kong upstream health response payload
+type KongUpstreamHealthResponse struct {
+	Data   []KongUpstreamTargetHealthResponse `json:"data"`
+	Next   string                             `json:"next"`
+	NodeId string                             `json:"node_id"`
+}
+
+const (
+	upstreamHealthResource string = "health"
+)
+
+// fetch the health of all targets of an upstream, following the pages of the health collection
+func (ks *KongServerDomain) fetchUpstreamHealth(id string) (*KongUpstreamHealthResponse, []byte, string, error) {
+
+	var resource string = fmt.Sprintf("%s/%s/%s", upstreamResource, id, upstreamHealthResource)
+
+	//	send a request to Kong to get the health of the upstream targets
+	collection, status, err := ks.fetchCollection(resource, nil, "fail sending upstream health command to Kong")
+	if err != nil {
+		return nil, nil, "", kongNotFoundAsEntity("upstream", err)
+	}
+
+	respPayload, err := json.Marshal(collection)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	var upstreamHealthResp KongUpstreamHealthResponse
+
+	err = json.Unmarshal(respPayload, &upstreamHealthResp)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	return &upstreamHealthResp, respPayload, status, nil
+}
+
+// show the health and weight of all targets of an upstream
+func (ks *KongServerDomain) UpstreamHealth(id string, options Options) error {
+
+	upstreamHealthResp, respPayload, status, err := ks.fetchUpstreamHealth(id)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", status, string(respPayload))
+
+		return nil
+	}
+
+	if options.verbose {
+		fmt.Printf("http response status code: %s\n", status)
+	}
+
+	if len(upstreamHealthResp.Data) == 0 {
+		fmt.Printf("No upstream targets\n")
+
+		return nil
+	}
+
+	//	render the targets health as a table
+	var targetWidth int = len("TARGET")
+
+	for _, target := range upstreamHealthResp.Data {
+		if len(target.Target) > targetWidth {
+			targetWidth = len(target.Target)
+		}
+	}
+
+	fmt.Printf("%-*s  %-11s  %6s  %s\n", targetWidth, "TARGET", "HEALTH", "WEIGHT", "AVAILABLE")
+	for _, target := range upstreamHealthResp.Data {
+		fmt.Printf("%-*s  %-11s  %6d  %d/%d\n", targetWidth, target.Target, target.Health, target.Weight,
+			target.Data.Weight.Available, target.Data.Weight.Total)
+	}
+
+	return nil
+}
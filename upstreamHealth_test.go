@@ -0,0 +1,278 @@
+////////////////////////////////////////////////////////////////////////////////
+//	upstreamHealth_test.go  -  Oct-17-2026  -  aldebap
+//
+//	Test cases for Kong upstream health checks and targets health
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Test_UpstreamHealthchecksOptions unit tests for the upstream health checks options
+func Test_UpstreamHealthchecksOptions(t *testing.T) {
+
+	t.Run(">>> UpstreamHealthchecksOptions: scenario 1 - health checks file and options", func(t *testing.T) {
+
+		var request KongUpstreamRequest
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&request)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "1343894e-404a-4f9e-a982-9e5c0e9d1733"}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		healthchecksFile := filepath.Join(t.TempDir(), "healthchecks.yaml")
+		os.WriteFile(healthchecksFile, []byte("active:\n  http_path: /health\n  healthy:\n    interval: 5\n    successes: 2\npassive:\n  unhealthy:\n    http_failures: 3\n"), 0644)
+
+		got := kconf(kongServer, []string{"add", "upstream", "--name=Pedidos", "--healthchecks-file=" + healthchecksFile,
+			"--active-http-path=/status", "--active-unhealthy-interval=10", "--passive-unhealthy-http-failures=5"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed adding upstream: success expected: result: %s", got.Error())
+		}
+
+		healthchecks := request.Healthchecks
+		if healthchecks == nil || healthchecks.Active == nil || healthchecks.Passive == nil {
+			t.Fatalf("failed adding upstream: health checks expected in the request: %+v", request)
+		}
+		if healthchecks.Active.HTTPPath != "/status" || *healthchecks.Active.Healthy.Interval != 5 || *healthchecks.Active.Healthy.Successes != 2 ||
+			*healthchecks.Active.Unhealthy.Interval != 10 || *healthchecks.Passive.Unhealthy.HTTPFailures != 5 {
+			t.Errorf("failed adding upstream: unexpected health checks in the request: %+v %+v", healthchecks.Active, healthchecks.Passive)
+		}
+	})
+
+	t.Run(">>> UpstreamHealthchecksOptions: scenario 2 - no health checks", func(t *testing.T) {
+
+		upstream, err := upstreamOptions([]string{"--name=Pedidos", "--algorithm=round-robin"})
+		if err != nil {
+			t.Fatalf("failed parsing upstream options: success expected: result: %s", err.Error())
+		}
+
+		if upstream.healthchecks != nil {
+			t.Errorf("failed parsing upstream options: no health checks expected: %+v", upstream.healthchecks)
+		}
+	})
+
+	t.Run(">>> UpstreamHealthchecksOptions: scenario 3 - invalid health checks options", func(t *testing.T) {
+
+		var testScenarios = []struct {
+			option string
+			want   string
+		}{
+			{option: "--passive-healthy-interval=5", want: "invalid option --passive-healthy-interval: passive health checks have no interval"},
+			{option: "--active-healthy-timeouts=5", want: "invalid option --active-healthy-timeouts: healthy thresholds are interval and successes"},
+			{option: "--passive-unhealthy-successes=5", want: "invalid option --passive-unhealthy-successes: unhealthy thresholds are interval, http-failures, tcp-failures and timeouts"},
+			{option: "--active-healthy-interval=fast", want: "wrong value for option --active-healthy-interval: fast: must be between 0 and 65535"},
+			{option: "--passive-unhealthy-tcp-failures=300", want: "wrong value for option --passive-unhealthy-tcp-failures: 300: must be between 0 and 255"},
+		}
+
+		for _, test := range testScenarios {
+			got := kconf(NewKongServer("http://localhost:8001", 0), []string{"add", "upstream", "--name=Pedidos", test.option}, Options{})
+
+			//	check the invocation result
+			if got == nil || got.Error() != test.want {
+				t.Errorf("failed checking option %s: error expected: %s result: %v", test.option, test.want, got)
+			}
+		}
+	})
+}
+
+// Test_HealthchecksSummary unit tests for healthchecksSummary() function
+func Test_HealthchecksSummary(t *testing.T) {
+
+	t.Run(">>> HealthchecksSummary: scenario 1 - Kong default health checks", func(t *testing.T) {
+
+		var upstreamResp KongUpstreamResponse
+
+		json.Unmarshal([]byte(`{
+			"id": "1343894e-404a-4f9e-a982-9e5c0e9d1733",
+			"healthchecks": {
+				"threshold": 0,
+				"active": { "type": "http", "http_path": "/", "timeout": 1, "concurrency": 10,
+					"healthy": { "interval": 0, "successes": 0, "http_statuses": [200, 302] },
+					"unhealthy": { "interval": 0, "http_failures": 0, "tcp_failures": 0, "timeouts": 0 } },
+				"passive": { "type": "http",
+					"healthy": { "successes": 0 },
+					"unhealthy": { "http_failures": 0, "tcp_failures": 0, "timeouts": 0 } }
+			}
+		}`), &upstreamResp)
+
+		want := "disabled"
+		got := healthchecksSummary(upstreamResp.Healthchecks)
+
+		if want != got {
+			t.Errorf("failed checking health checks summary: expected: %s result: %s", want, got)
+		}
+	})
+
+	t.Run(">>> HealthchecksSummary: scenario 2 - active and passive health checks", func(t *testing.T) {
+
+		healthchecks := &KongUpstreamHealthchecks{}
+		healthchecks.setActiveHTTPPath("/health")
+		healthchecks.Active.Type = "http"
+		healthchecks.Passive = &KongPassiveHealthcheck{Type: "http"}
+		healthchecks.setThreshold("active", "healthy", "interval", "5")
+		healthchecks.setThreshold("passive", "unhealthy", "http-failures", "3")
+
+		want := "active http /health (healthy interval: 5s, unhealthy interval: 0s) ; passive http (http failures: 3)"
+		got := healthchecksSummary(healthchecks)
+
+		if want != got {
+			t.Errorf("failed checking health checks summary: expected: %s result: %s", want, got)
+		}
+	})
+}
+
+// Test_UpstreamHealth unit tests for UpstreamHealth() method
+func Test_UpstreamHealth(t *testing.T) {
+
+	t.Run(">>> UpstreamHealth: scenario 1 - upstream not found", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("upstream not found")
+		got := kongServer.UpstreamHealth("1234", Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed checking upstream health: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> UpstreamHealth: scenario 2 - internal server error", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("fail sending upstream health command to Kong: 500 Internal Server Error")
+		got := kongServer.UpstreamHealth("1234", Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed checking upstream health: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> UpstreamHealth: scenario 3 - targets health returned successfuly", func(t *testing.T) {
+
+		var requestPath string
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestPath = r.URL.Path
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{
+				"data": [
+					{ "id": "a0110455-2652-4e83-9202-9ca212277abc", "target": "192.168.68.107:8080", "health": "HEALTHY", "weight": 100,
+						"data": { "weight": { "total": 100, "available": 100, "unavailable": 0 } } },
+					{ "id": "b1221566-3763-4f94-a313-0db323388bcd", "target": "192.168.68.108:8080", "health": "UNHEALTHY", "weight": 100,
+						"data": { "weight": { "total": 100, "available": 0, "unavailable": 100 } } }
+				],
+				"next": null,
+				"node_id": "cbb297c0-14a9-46bc-ad91-1d0ef9b42df9"
+			}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"health", "upstream", "--id=Pedidos"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed checking upstream health: success expected: result: %s", got.Error())
+		}
+
+		if requestPath != "/upstreams/Pedidos/health" {
+			t.Errorf("failed checking upstream health: unexpected request path: %s", requestPath)
+		}
+	})
+
+	t.Run(">>> UpstreamHealth: scenario 4 - targets health in more than one page", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusOK)
+			if len(r.URL.Query().Get("offset")) == 0 {
+				w.Write([]byte(`{
+					"data": [ { "target": "192.168.68.107:8080", "health": "HEALTHY", "weight": 100 } ],
+					"next": "/upstreams/Pedidos/health?offset=WyIxOTIuMTY4LjY4LjEwNzo4MDgwIl0",
+					"node_id": "cbb297c0-14a9-46bc-ad91-1d0ef9b42df9"
+				}`))
+				return
+			}
+			w.Write([]byte(`{
+				"data": [ { "target": "192.168.68.108:8080", "health": "UNHEALTHY", "weight": 100 } ],
+				"next": null,
+				"node_id": "cbb297c0-14a9-46bc-ad91-1d0ef9b42df9"
+			}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0).(*KongServerDomain)
+
+		upstreamHealthResp, respPayload, _, err := kongServer.fetchUpstreamHealth("Pedidos")
+
+		//	check the invocation result
+		if err != nil {
+			t.Fatalf("failed checking upstream health: success expected: result: %s", err.Error())
+		}
+
+		if len(upstreamHealthResp.Data) != 2 || upstreamHealthResp.Data[1].Target != "192.168.68.108:8080" ||
+			upstreamHealthResp.Data[1].Health != "UNHEALTHY" {
+			t.Errorf("failed checking upstream health: targets of both pages expected: result: %+v", upstreamHealthResp.Data)
+		}
+
+		if upstreamHealthResp.NodeId != "cbb297c0-14a9-46bc-ad91-1d0ef9b42df9" || !strings.Contains(string(respPayload), `"node_id":"cbb297c0-14a9-46bc-ad91-1d0ef9b42df9"`) {
+			t.Errorf("failed checking upstream health: node id expected: result: %s", string(respPayload))
+		}
+	})
+}
@@ -0,0 +1,299 @@
+////////////////////////////////////////////////////////////////////////////////
+//	upstreamShift.go  -  Oct-17-2026  -  aldebap
+//
+//	Gradual traffic shifting between Kong upstream targets
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// kong upstream traffic shift attributes
+type KongTrafficShift struct {
+	from     string
+	to       string
+	steps    int
+	interval time.Duration
+}
+
+// create a new Kong upstream traffic shift
+func NewKongTrafficShift(from string, to string, steps int, interval time.Duration) *KongTrafficShift {
+
+	return &KongTrafficShift{
+		from:     from,
+		to:       to,
+		steps:    steps,
+		interval: interval,
+	}
+}
+
+const (
+	defaultTrafficShiftSteps    int           = 5
+	defaultTrafficShiftInterval time.Duration = time.Minute
+)
+
+// the weights of the targets in a traffic shift step
+type trafficShiftWeights struct {
+	from int
+	to   int
+}
+
+// kong upstream traffic shift step output payload
+type KongTrafficShiftStep struct {
+	Step  int                    `json:"step"`
+	Steps int                    `json:"steps"`
+	From  KongTrafficShiftTarget `json:"from"`
+	To    KongTrafficShiftTarget `json:"to"`
+}
+
+// kong upstream traffic shift target weight output payload
+type KongTrafficShiftTarget struct {
+	Target string `json:"target"`
+	Weight int    `json:"weight"`
+}
+
+// weights of the targets at a given step: the whole weight of both targets moves from one to the other
+func (shift *KongTrafficShift) stepWeights(original trafficShiftWeights, step int) trafficShiftWeights {
+
+	total := original.from + original.to
+	to := original.to + (total-original.to)*step/shift.steps
+
+	return trafficShiftWeights{from: total - to, to: to}
+}
+
+// set the weight of an upstream target without any output
+func (ks *KongServerDomain) setUpstreamTargetWeight(upstreamId string, target string, weight int) error {
+
+	var upstreamTargetURL string = fmt.Sprintf("%s/%s/%s/%s/%s", ks.ServerURL(), upstreamResource, upstreamId, upstreamTargetResource, target)
+
+	payload, err := json.Marshal(KongUpstreamTargetRequest{
+		Weight: &weight,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := ks.sendRequest("PATCH", upstreamTargetURL, payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return newKongAPIError("fail sending patch upstream target command to Kong", resp)
+	}
+
+	return nil
+}
+
+// remove an upstream target without any output
+func (ks *KongServerDomain) removeUpstreamTarget(upstreamId string, target string) error {
+
+	var upstreamTargetURL string = fmt.Sprintf("%s/%s/%s/%s/%s", ks.ServerURL(), upstreamResource, upstreamId, upstreamTargetResource, target)
+
+	resp, err := ks.sendRequest("DELETE", upstreamTargetURL, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newKongAPIError("fail sending delete upstream target command to Kong", resp)
+	}
+
+	return nil
+}
+
+// set the weights of both targets of a traffic shift
+func (ks *KongServerDomain) setTrafficShiftWeights(upstreamId string, shift *KongTrafficShift, weights trafficShiftWeights) error {
+
+	err := ks.setUpstreamTargetWeight(upstreamId, shift.from, weights.from)
+	if err != nil {
+		return err
+	}
+
+	return ks.setUpstreamTargetWeight(upstreamId, shift.to, weights.to)
+}
+
+// fetch the current weights of the targets of a traffic shift, adding the new target with weight zero if needed;
+// also tells if the new target was added, so a rollback can remove it
+func (ks *KongServerDomain) fetchTrafficShiftWeights(upstreamId string, shift *KongTrafficShift) (trafficShiftWeights, bool, error) {
+
+	var weights trafficShiftWeights
+	var fromFound, toFound bool
+
+	data, err := ks.fetchStateCollection(fmt.Sprintf("%s/%s/%s", upstreamResource, upstreamId, upstreamTargetResource))
+	if err != nil {
+		return weights, false, err
+	}
+
+	for _, item := range data {
+		var upstreamTargetResp KongUpstreamTargetResponse
+
+		err = json.Unmarshal(item, &upstreamTargetResp)
+		if err != nil {
+			return weights, false, err
+		}
+
+		switch upstreamTargetResp.Target {
+		case shift.from:
+			weights.from = upstreamTargetResp.Weight
+			fromFound = true
+
+		case shift.to:
+			weights.to = upstreamTargetResp.Weight
+			toFound = true
+		}
+	}
+
+	if !fromFound {
+		return weights, false, errors.New("upstream target not found: " + shift.from)
+	}
+
+	if weights.from+weights.to == 0 {
+		return weights, false, errors.New("no traffic to shift: upstream targets " + shift.from + " and " + shift.to + " have weight 0")
+	}
+
+	if toFound {
+		return weights, false, nil
+	}
+
+	var upstreamTargetURL string = fmt.Sprintf("%s/%s/%s/%s", ks.ServerURL(), upstreamResource, upstreamId, upstreamTargetResource)
+
+	payload, err := json.Marshal(KongUpstreamTargetRequest{
+		Target: shift.to,
+		Weight: &weights.to,
+	})
+	if err != nil {
+		return weights, false, err
+	}
+
+	resp, err := ks.sendRequest("POST", upstreamTargetURL, payload)
+	if err != nil {
+		return weights, false, err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusCreated {
+		return weights, false, newKongAPIError("fail sending add upstream target command to Kong", resp)
+	}
+
+	return weights, true, nil
+}
+
+// check if an upstream target is unhealthy
+func (ks *KongServerDomain) isUpstreamTargetUnhealthy(upstreamId string, target string) (bool, string, error) {
+
+	upstreamHealthResp, _, _, err := ks.fetchUpstreamHealth(upstreamId)
+	if err != nil {
+		return false, "", err
+	}
+
+	for _, targetHealth := range upstreamHealthResp.Data {
+		if targetHealth.Target == target {
+			switch targetHealth.Health {
+			case "UNHEALTHY", "DNS_ERROR":
+				return true, targetHealth.Health, nil
+			}
+
+			return false, targetHealth.Health, nil
+		}
+	}
+
+	return false, "", nil
+}
+
+// gradually shift the traffic of an upstream from one target to another, rolling back on failures
+func (ks *KongServerDomain) ShiftUpstreamTraffic(upstreamId string, shift *KongTrafficShift, options Options) error {
+
+	original, created, err := ks.fetchTrafficShiftWeights(upstreamId, shift)
+	if err != nil {
+		return err
+	}
+
+	//	an interruption (Ctrl-C) restores the original weights
+	interrupt := make(chan os.Signal, 1)
+	signal.Notify(interrupt, os.Interrupt, syscall.SIGTERM)
+	defer signal.Stop(interrupt)
+
+	//	a new target added by the shift is removed after its weight is back to zero
+	rollback := func(reason string) error {
+		if !options.jsonOutput {
+			fmt.Printf("rolling back: %s --> weight: %d, %s --> weight: %d\n", shift.from, original.from, shift.to, original.to)
+		}
+
+		err := ks.setTrafficShiftWeights(upstreamId, shift, original)
+		if err != nil {
+			return errors.New(reason + ": fail restoring the original weights: " + err.Error())
+		}
+
+		if created {
+			if !options.jsonOutput {
+				fmt.Printf("removing upstream target: %s\n", shift.to)
+			}
+
+			err = ks.removeUpstreamTarget(upstreamId, shift.to)
+			if err != nil {
+				return errors.New(reason + ": fail removing upstream target " + shift.to + ": " + err.Error())
+			}
+		}
+
+		return errors.New(reason + ": original weights restored")
+	}
+
+	for step := 1; step <= shift.steps; step++ {
+		weights := shift.stepWeights(original, step)
+
+		err = ks.setTrafficShiftWeights(upstreamId, shift, weights)
+		if err != nil {
+			return rollback("traffic shift failed: " + err.Error())
+		}
+
+		if options.jsonOutput {
+			payload, err := json.Marshal(KongTrafficShiftStep{
+				Step:  step,
+				Steps: shift.steps,
+				From:  KongTrafficShiftTarget{Target: shift.from, Weight: weights.from},
+				To:    KongTrafficShiftTarget{Target: shift.to, Weight: weights.to},
+			})
+			if err != nil {
+				return rollback("traffic shift failed: " + err.Error())
+			}
+			fmt.Printf("%s\n", string(payload))
+		} else {
+			fmt.Printf("step %d/%d: %s --> weight: %d, %s --> weight: %d\n", step, shift.steps, shift.from, weights.from, shift.to, weights.to)
+		}
+
+		//	wait and check the new target health before moving more traffic to it, and once more after the last step
+		select {
+		case <-interrupt:
+			return rollback("traffic shift interrupted")
+
+		case <-time.After(shift.interval):
+		}
+
+		unhealthy, health, err := ks.isUpstreamTargetUnhealthy(upstreamId, shift.to)
+		if err != nil {
+			return rollback("traffic shift failed: " + err.Error())
+		}
+
+		if unhealthy {
+			return rollback("upstream target " + shift.to + " is " + health)
+		}
+
+		if options.verbose {
+			fmt.Printf("upstream target %s health: %s\n", shift.to, health)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,331 @@
+////////////////////////////////////////////////////////////////////////////////
+//	upstreamShift_test.go  -  Oct-17-2026  -  aldebap
+//
+//	Test cases for gradual traffic shifting between Kong upstream targets
+////////////////////////////////////////////////////////////////////////////////
+
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync"
+	"syscall"
+	"testing"
+	"time"
+)
+
+// mock for Kong Admin with the targets of an upstream, recording the weights sent to Kong
+type mockTrafficShiftAdmin struct {
+	mutex          sync.Mutex
+	targets        string
+	health         string
+	weights        []string
+	onPatch        func()
+	newTargets     []string
+	deletedTargets []string
+	healthChecks   int
+}
+
+// handle the Kong Admin requests of a traffic shift
+func (mock *mockTrafficShiftAdmin) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+
+	mock.mutex.Lock()
+	defer mock.mutex.Unlock()
+
+	switch {
+	case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/targets"):
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"data": ` + mock.targets + `, "next": null}`))
+
+	case r.Method == "POST" && strings.HasSuffix(r.URL.Path, "/targets"):
+		var request KongUpstreamTargetRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+		mock.newTargets = append(mock.newTargets, request.Target)
+		w.WriteHeader(http.StatusCreated)
+		w.Write([]byte(`{"id": "b1221566-3763-4f94-a313-0db323388bcd"}`))
+
+	case r.Method == "PATCH":
+		var request KongUpstreamTargetRequest
+
+		json.NewDecoder(r.Body).Decode(&request)
+		target := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+		mock.weights = append(mock.weights, target+"="+strconv.Itoa(*request.Weight))
+		if mock.onPatch != nil {
+			mock.onPatch()
+			mock.onPatch = nil
+		}
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{}`))
+
+	case r.Method == "DELETE":
+		mock.deletedTargets = append(mock.deletedTargets, r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:])
+		w.WriteHeader(http.StatusNoContent)
+
+	case r.Method == "GET" && strings.HasSuffix(r.URL.Path, "/health"):
+		mock.healthChecks++
+		w.WriteHeader(http.StatusOK)
+		w.Write([]byte(`{"data": [
+			{ "target": "old:8080", "health": "HEALTHY", "weight": 100 },
+			{ "target": "new:8080", "health": "` + mock.health + `", "weight": 100 }
+		], "next": null}`))
+
+	default:
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+// Test_TrafficShiftStepWeights unit tests for stepWeights() method
+func Test_TrafficShiftStepWeights(t *testing.T) {
+
+	var testScenarios = []struct {
+		original trafficShiftWeights
+		steps    int
+		want     []trafficShiftWeights
+	}{
+		{original: trafficShiftWeights{from: 100, to: 0}, steps: 5,
+			want: []trafficShiftWeights{{80, 20}, {60, 40}, {40, 60}, {20, 80}, {0, 100}}},
+		{original: trafficShiftWeights{from: 90, to: 10}, steps: 3,
+			want: []trafficShiftWeights{{60, 40}, {30, 70}, {0, 100}}},
+		{original: trafficShiftWeights{from: 7, to: 0}, steps: 2,
+			want: []trafficShiftWeights{{4, 3}, {0, 7}}},
+	}
+
+	for i, test := range testScenarios {
+		t.Run(">>> TrafficShiftStepWeights: scenario "+strconv.Itoa(i+1), func(t *testing.T) {
+
+			shift := NewKongTrafficShift("old:8080", "new:8080", test.steps, 0)
+
+			for step := 1; step <= test.steps; step++ {
+				got := shift.stepWeights(test.original, step)
+
+				if got != test.want[step-1] {
+					t.Errorf("failed checking step %d weights: expected: %+v result: %+v", step, test.want[step-1], got)
+				}
+			}
+		})
+	}
+}
+
+// Test_ShiftUpstreamTraffic unit tests for ShiftUpstreamTraffic() method
+func Test_ShiftUpstreamTraffic(t *testing.T) {
+
+	t.Run(">>> ShiftUpstreamTraffic: scenario 1 - traffic shifted successfuly", func(t *testing.T) {
+
+		mock := &mockTrafficShiftAdmin{
+			targets: `[{ "id": "a0110455-2652-4e83-9202-9ca212277abc", "target": "old:8080", "weight": 100 }]`,
+			health:  "HEALTHY",
+		}
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(mock)
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"upstream", "shift", "--upstream-id=Pedidos", "--from=old:8080", "--to=new:8080",
+			"--steps=2", "--interval=1ms"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed shifting upstream traffic: success expected: result: %s", got.Error())
+		}
+
+		want := "old:8080=50 new:8080=50 old:8080=0 new:8080=100"
+		if strings.Join(mock.weights, " ") != want || len(mock.newTargets) != 1 || mock.newTargets[0] != "new:8080" {
+			t.Errorf("failed shifting upstream traffic: expected weights: %s result: %s (new targets: %s)", want, mock.weights, mock.newTargets)
+		}
+
+		//	the new target health is checked after every step, including the last one
+		if mock.healthChecks != 2 || len(mock.deletedTargets) != 0 {
+			t.Errorf("failed shifting upstream traffic: expected health checks: 2 result: %d (deleted targets: %s)", mock.healthChecks, mock.deletedTargets)
+		}
+	})
+
+	t.Run(">>> ShiftUpstreamTraffic: scenario 2 - rollback when the new target is unhealthy", func(t *testing.T) {
+
+		mock := &mockTrafficShiftAdmin{
+			targets: `[{ "target": "old:8080", "weight": 100 }, { "target": "new:8080", "weight": 0 }]`,
+			health:  "UNHEALTHY",
+		}
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(mock)
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("upstream target new:8080 is UNHEALTHY: original weights restored")
+		got := kongServer.ShiftUpstreamTraffic("Pedidos", NewKongTrafficShift("old:8080", "new:8080", 4, time.Millisecond), Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Fatalf("failed shifting upstream traffic: error expected: %v result: %v", want, got)
+		}
+
+		wantWeights := "old:8080=75 new:8080=25 old:8080=100 new:8080=0"
+		if strings.Join(mock.weights, " ") != wantWeights || len(mock.newTargets) != 0 {
+			t.Errorf("failed shifting upstream traffic: expected weights: %s result: %s", wantWeights, mock.weights)
+		}
+	})
+
+	t.Run(">>> ShiftUpstreamTraffic: scenario 3 - rollback when interrupted", func(t *testing.T) {
+
+		mock := &mockTrafficShiftAdmin{
+			targets: `[{ "target": "old:8080", "weight": 80 }, { "target": "new:8080", "weight": 20 }]`,
+			health:  "HEALTHY",
+			onPatch: func() { syscall.Kill(syscall.Getpid(), syscall.SIGINT) },
+		}
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(mock)
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("traffic shift interrupted: original weights restored")
+		got := kongServer.ShiftUpstreamTraffic("Pedidos", NewKongTrafficShift("old:8080", "new:8080", 2, time.Minute), Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Fatalf("failed shifting upstream traffic: error expected: %v result: %v", want, got)
+		}
+
+		wantWeights := "old:8080=40 new:8080=60 old:8080=80 new:8080=20"
+		if strings.Join(mock.weights, " ") != wantWeights {
+			t.Errorf("failed shifting upstream traffic: expected weights: %s result: %s", wantWeights, mock.weights)
+		}
+	})
+
+	t.Run(">>> ShiftUpstreamTraffic: scenario 4 - upstream target not found", func(t *testing.T) {
+
+		mock := &mockTrafficShiftAdmin{
+			targets: `[{ "target": "new:8080", "weight": 0 }]`,
+		}
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(mock)
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("upstream target not found: old:8080")
+		got := kongServer.ShiftUpstreamTraffic("Pedidos", NewKongTrafficShift("old:8080", "new:8080", 2, time.Millisecond), Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed shifting upstream traffic: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> ShiftUpstreamTraffic: scenario 5 - invalid options", func(t *testing.T) {
+
+		var testScenarios = []struct {
+			command []string
+			want    string
+		}{
+			{command: []string{"upstream", "shift", "--from=old:8080", "--to=new:8080"},
+				want: "missing upstream id: option --upstream-id={id} required for this command"},
+			{command: []string{"upstream", "shift", "--upstream-id=Pedidos", "--from=old:8080"},
+				want: "missing upstream targets: options --from={target} and --to={target} required for this command"},
+			{command: []string{"upstream", "shift", "--upstream-id=Pedidos", "--from=old:8080", "--to=old:8080"},
+				want: "options --from and --to must be different upstream targets"},
+			{command: []string{"upstream", "shift", "--upstream-id=Pedidos", "--from=old:8080", "--to=new:8080", "--steps=0"},
+				want: "wrong value for option --steps: 0: must be between 1 and 100"},
+			{command: []string{"upstream", "shift", "--upstream-id=Pedidos", "--from=old:8080", "--to=new:8080", "--interval=2"},
+				want: "wrong value for option --interval: 2: must be a duration (e.g. 2m)"},
+		}
+
+		for _, test := range testScenarios {
+			got := kconf(NewKongServer("http://localhost:8001", 0), test.command, Options{})
+
+			//	check the invocation result
+			if got == nil || got.Error() != test.want {
+				t.Errorf("failed shifting upstream traffic: error expected: %s result: %v", test.want, got)
+			}
+		}
+	})
+
+	t.Run(">>> ShiftUpstreamTraffic: scenario 6 - rollback removes the target added by the shift", func(t *testing.T) {
+
+		mock := &mockTrafficShiftAdmin{
+			targets: `[{ "target": "old:8080", "weight": 100 }]`,
+			health:  "DNS_ERROR",
+		}
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(mock)
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("upstream target new:8080 is DNS_ERROR: original weights restored")
+		got := kongServer.ShiftUpstreamTraffic("Pedidos", NewKongTrafficShift("old:8080", "new:8080", 4, time.Millisecond), Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Fatalf("failed shifting upstream traffic: error expected: %v result: %v", want, got)
+		}
+
+		wantWeights := "old:8080=75 new:8080=25 old:8080=100 new:8080=0"
+		if strings.Join(mock.weights, " ") != wantWeights || strings.Join(mock.deletedTargets, " ") != "new:8080" {
+			t.Errorf("failed shifting upstream traffic: expected weights: %s result: %s (deleted targets: %s)", wantWeights, mock.weights, mock.deletedTargets)
+		}
+	})
+
+	t.Run(">>> ShiftUpstreamTraffic: scenario 7 - rollback when the new target is unhealthy after the last step", func(t *testing.T) {
+
+		mock := &mockTrafficShiftAdmin{
+			targets: `[{ "target": "old:8080", "weight": 100 }, { "target": "new:8080", "weight": 0 }]`,
+			health:  "UNHEALTHY",
+		}
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(mock)
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("upstream target new:8080 is UNHEALTHY: original weights restored")
+		got := kongServer.ShiftUpstreamTraffic("Pedidos", NewKongTrafficShift("old:8080", "new:8080", 1, time.Millisecond), Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Fatalf("failed shifting upstream traffic: error expected: %v result: %v", want, got)
+		}
+
+		wantWeights := "old:8080=0 new:8080=100 old:8080=100 new:8080=0"
+		if strings.Join(mock.weights, " ") != wantWeights || len(mock.deletedTargets) != 0 {
+			t.Errorf("failed shifting upstream traffic: expected weights: %s result: %s (deleted targets: %s)", wantWeights, mock.weights, mock.deletedTargets)
+		}
+	})
+}
@@ -7,9 +7,7 @@
 package main
 
 import (
-	"bytes"
 	"encoding/json"
-	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -18,25 +16,33 @@ import (
 // kong upstream target attributes
 type KongUpstreamTarget struct {
 	target string
+	weight *int
+	tags   []string
 }
 
 // create a new Kong upstreamTarget
-func NewKongUpstreamTarget(target string) *KongUpstreamTarget {
+func NewKongUpstreamTarget(target string, weight *int, tags []string) *KongUpstreamTarget {
 
 	return &KongUpstreamTarget{
 		target: target,
+		weight: weight,
+		tags:   tags,
 	}
 }
 
 // kong upstream target request payload
 type KongUpstreamTargetRequest struct {
-	Target string `json:"target,omitempty"`
+	Target string   `json:"target,omitempty"`
+	Weight *int     `json:"weight,omitempty"`
+	Tags   []string `json:"tags,omitempty"`
 }
 
 // kong upstream target response payload
 type KongUpstreamTargetResponse struct {
-	Id     string `json:"id"`
-	Target string `json:"target,omitempty"`
+	Id     string   `json:"id"`
+	Target string   `json:"target,omitempty"`
+	Weight int      `json:"weight"`
+	Tags   []string `json:"tags"`
 }
 
 // kong upstream target list response payload
@@ -46,7 +52,8 @@ type KongUpstreamTargetListResponse struct {
 }
 
 const (
-	upstreamTargetResource string = "targets"
+	upstreamTargetResource  string = "targets"
+	maxUpstreamTargetWeight int    = 65535
 )
 
 // add a new upstreamTarget to Kong
@@ -56,26 +63,21 @@ func (ks *KongServerDomain) AddUpstreamTarget(upstreamId string, newKongUpstream
 
 	payload, err := json.Marshal(KongUpstreamTargetRequest{
 		Target: newKongUpstreamTarget.target,
+		Weight: newKongUpstreamTarget.weight,
+		Tags:   newKongUpstreamTarget.tags,
 	})
 	if err != nil {
 		return err
 	}
 
-	req, err := http.NewRequest("POST", upstreamTargetURL, bytes.NewBuffer([]byte(payload)))
-	if err != nil {
-		return err
-	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
-
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	resp, err := ks.sendRequest("POST", upstreamTargetURL, payload)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusCreated {
-		return errors.New("fail sending add upstream target command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending add upstream target command to Kong", resp)
 	}
 
 	//	parse response payload
@@ -86,7 +88,7 @@ func (ks *KongServerDomain) AddUpstreamTarget(upstreamId string, newKongUpstream
 		return err
 	}
 
-	var upstreamTargetResp KongUpstreamResponse
+	var upstreamTargetResp KongUpstreamTargetResponse
 
 	err = json.Unmarshal(respPayload, &upstreamTargetResp)
 	if err != nil {
@@ -112,17 +114,17 @@ func (ks *KongServerDomain) QueryUpstreamTarget(upstreamId string, id string, op
 	var upstreamTargetURL string = fmt.Sprintf("%s/%s/%s/%s/%s", ks.ServerURL(), upstreamResource, upstreamId, upstreamTargetResource, id)
 
 	//	send a request to Kong to query the upstream target by id
-	resp, err := http.Get(upstreamTargetURL)
+	resp, err := ks.sendRequest("GET", upstreamTargetURL, nil)
 	if err != nil {
 		return err
 	}
 
 	if resp.StatusCode == http.StatusNotFound {
-		return errors.New("upstream target not found")
+		return newKongNotFoundError("upstream target", resp)
 	}
 
 	if resp.StatusCode != http.StatusOK {
-		return errors.New("fail sending query upstream target command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending query upstream target command to Kong", resp)
 	}
 
 	var respPayload []byte
@@ -143,41 +145,37 @@ func (ks *KongServerDomain) QueryUpstreamTarget(upstreamId string, id string, op
 		}
 
 		if options.verbose {
-			fmt.Printf("http response status code: %s\nupstream target: %s\n", resp.Status,
-				upstreamTargetResp.Target)
+			fmt.Printf("http response status code: %s\nupstream target: %s --> weight: %d (%s)\n", resp.Status,
+				upstreamTargetResp.Target, upstreamTargetResp.Weight, upstreamTargetResp.Tags)
 		} else {
-			fmt.Printf("upstream target: %s\n",
-				upstreamTargetResp.Target)
+			fmt.Printf("upstream target: %s --> weight: %d (%s)\n",
+				upstreamTargetResp.Target, upstreamTargetResp.Weight, upstreamTargetResp.Tags)
 		}
 	}
 
 	return nil
 }
 
-// list all upstreams
-func (ks *KongServerDomain) ListUpstreamTargets(upstreamId string, options Options) error {
+// list all targets of an upstream, or the targets of all upstreams when no upstream is given
+func (ks *KongServerDomain) ListUpstreamTargets(upstreamId string, pagination *KongPagination, options Options) error {
 
-	var upstreamTargetURL string = fmt.Sprintf("%s/%s/%s/%s", ks.ServerURL(), upstreamResource, upstreamId, upstreamTargetResource)
+	if len(upstreamId) == 0 {
+		return ks.listAllUpstreamTargets(pagination, options)
+	}
 
 	//	send a request to Kong to get a list of all upstream targets
-	resp, err := http.Get(upstreamTargetURL)
+	collection, status, err := ks.fetchCollection(fmt.Sprintf("%s/%s/%s", upstreamResource, upstreamId, upstreamTargetResource), pagination, "fail sending list upstream targets command to Kong")
 	if err != nil {
 		return err
 	}
 
-	if resp.StatusCode != http.StatusOK {
-		return errors.New("fail sending list upstream targets command to Kong: " + resp.Status)
-	}
-
-	var respPayload []byte
-
-	respPayload, err = io.ReadAll(resp.Body)
+	respPayload, err := json.Marshal(collection)
 	if err != nil {
 		return err
 	}
 
 	if options.jsonOutput {
-		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+		fmt.Printf("%s\n%s\n", status, string(respPayload))
 	} else {
 		var upstreamTargetListResp KongUpstreamTargetListResponse
 
@@ -188,7 +186,7 @@ func (ks *KongServerDomain) ListUpstreamTargets(upstreamId string, options Optio
 
 		if len(upstreamTargetListResp.Data) == 0 {
 			if options.verbose {
-				fmt.Printf("%s\nNo upstream targets\n", resp.Status)
+				fmt.Printf("%s\nNo upstream targets\n", status)
 			} else {
 				fmt.Printf("No upstream targets\n")
 			}
@@ -197,39 +195,210 @@ func (ks *KongServerDomain) ListUpstreamTargets(upstreamId string, options Optio
 		}
 
 		if options.verbose {
-			fmt.Printf("http response status code: %s\nupstream target list\n", resp.Status)
+			fmt.Printf("http response status code: %s\nupstream target list\n", status)
 		}
 
 		for _, upstreamTarget := range upstreamTargetListResp.Data {
-			fmt.Printf("%s: %s\n", upstreamTarget.Id,
-				upstreamTarget.Target)
+			fmt.Printf("%s: %s --> weight: %d (%s)\n", upstreamTarget.Id,
+				upstreamTarget.Target, upstreamTarget.Weight, upstreamTarget.Tags)
 		}
+		printNextOffset(collection)
 	}
 
 	return nil
 }
 
-// delete a upstream target in Kong
-func (ks *KongServerDomain) DeleteUpstreamTarget(upstreamId string, id string, options Options) error {
+// update an upstream target in Kong
+func (ks *KongServerDomain) UpdateUpstreamTarget(upstreamId string, id string, updatedKongUpstreamTarget *KongUpstreamTarget, options Options) error {
 
 	var upstreamTargetURL string = fmt.Sprintf("%s/%s/%s/%s/%s", ks.ServerURL(), upstreamResource, upstreamId, upstreamTargetResource, id)
 
-	//	send a request to Kong to delete the upstream target by id
-	req, err := http.NewRequest("DELETE", upstreamTargetURL, bytes.NewBuffer([]byte("")))
+	payload, err := json.Marshal(KongUpstreamTargetRequest{
+		Target: updatedKongUpstreamTarget.target,
+		Weight: updatedKongUpstreamTarget.weight,
+		Tags:   updatedKongUpstreamTarget.tags,
+	})
+	if err != nil {
+		return err
+	}
+
+	resp, err := ks.sendRequest("PATCH", upstreamTargetURL, payload)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return newKongNotFoundError("upstream target", resp)
+	}
+
+	if resp.StatusCode != http.StatusOK {
+		return newKongAPIError("fail sending patch upstream target command to Kong", resp)
+	}
+
+	//	parse response payload
+	var respPayload []byte
+
+	respPayload, err = io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+
+	var upstreamTargetResp KongUpstreamTargetResponse
+
+	err = json.Unmarshal(respPayload, &upstreamTargetResp)
+	if err != nil {
+		return err
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n%s\n", resp.Status, string(respPayload))
+	} else {
+		if options.verbose {
+			fmt.Printf("http response status code: %s\nupstream target: %s --> weight: %d (%s)\n", resp.Status,
+				upstreamTargetResp.Target, upstreamTargetResp.Weight, upstreamTargetResp.Tags)
+		} else {
+			fmt.Printf("upstream target: %s --> weight: %d (%s)\n",
+				upstreamTargetResp.Target, upstreamTargetResp.Weight, upstreamTargetResp.Tags)
+		}
+	}
+
+	return nil
+}
+
+// set the health of an upstream target in Kong, overriding the health checks until the next health check
+func (ks *KongServerDomain) SetUpstreamTargetHealth(upstreamId string, id string, healthy bool, options Options) error {
+
+	var health string = "unhealthy"
+
+	if healthy {
+		health = "healthy"
+	}
+
+	var upstreamTargetURL string = fmt.Sprintf("%s/%s/%s/%s/%s/%s", ks.ServerURL(), upstreamResource, upstreamId, upstreamTargetResource, id, health)
+
+	//	send a request to Kong to set the upstream target health
+	resp, err := ks.sendRequest("PUT", upstreamTargetURL, nil)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusNotFound {
+		return newKongNotFoundError("upstream target", resp)
+	}
+
+	if resp.StatusCode != http.StatusNoContent {
+		return newKongAPIError("fail sending set upstream target health command to Kong", resp)
+	}
+
+	if options.jsonOutput {
+		fmt.Printf("%s\n{}\n", resp.Status)
+	} else {
+		if options.verbose {
+			fmt.Printf("http response status code: %s\n", resp.Status)
+		}
+		fmt.Printf("upstream target %s set %s\n", id, health)
+	}
+
+	return nil
+}
+
+// list the targets of all upstreams, grouped by upstream: pagination applies to the upstreams
+func (ks *KongServerDomain) listAllUpstreamTargets(pagination *KongPagination, options Options) error {
+
+	upstreamsCollection, status, err := ks.fetchCollection(upstreamResource, pagination, "fail sending list upstreams command to Kong")
 	if err != nil {
 		return err
 	}
-	req.Header.Set("Content-Type", "application/json; charset=UTF-8")
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
+	var targets KongCollectionPage
+	var upstreams []KongUpstreamResponse
+	targetsByUpstream := make(map[string][]KongUpstreamTargetResponse)
+
+	for _, item := range upstreamsCollection.Data {
+		var upstreamResp KongUpstreamResponse
+
+		err = json.Unmarshal(item, &upstreamResp)
+		if err != nil {
+			return err
+		}
+		upstreams = append(upstreams, upstreamResp)
+
+		collection, _, err := ks.fetchCollection(fmt.Sprintf("%s/%s/%s", upstreamResource, upstreamResp.Id, upstreamTargetResource), nil,
+			"fail sending list upstream targets command to Kong")
+		if err != nil {
+			return err
+		}
+		targets.Data = append(targets.Data, collection.Data...)
+
+		for _, targetItem := range collection.Data {
+			var upstreamTargetResp KongUpstreamTargetResponse
+
+			err = json.Unmarshal(targetItem, &upstreamTargetResp)
+			if err != nil {
+				return err
+			}
+			targetsByUpstream[upstreamResp.Id] = append(targetsByUpstream[upstreamResp.Id], upstreamTargetResp)
+		}
+	}
+	targets.Next = upstreamsCollection.Next
+	targets.Offset = upstreamsCollection.Offset
+
+	if options.jsonOutput {
+		respPayload, err := json.Marshal(targets)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("%s\n%s\n", status, string(respPayload))
+
+		return nil
+	}
+
+	if len(targets.Data) == 0 {
+		if options.verbose {
+			fmt.Printf("%s\nNo upstream targets\n", status)
+		} else {
+			fmt.Printf("No upstream targets\n")
+		}
+
+		return nil
+	}
+
+	if options.verbose {
+		fmt.Printf("http response status code: %s\nupstream target list\n", status)
+	}
+
+	for _, upstream := range upstreams {
+		if len(targetsByUpstream[upstream.Id]) == 0 {
+			continue
+		}
+
+		fmt.Printf("upstream: %s (%s)\n", upstream.Name, upstream.Id)
+		for _, upstreamTarget := range targetsByUpstream[upstream.Id] {
+			fmt.Printf("    %s: %s --> weight: %d (%s)\n", upstreamTarget.Id,
+				upstreamTarget.Target, upstreamTarget.Weight, upstreamTarget.Tags)
+		}
+	}
+	printNextOffset(&targets)
+
+	return nil
+}
+
+// delete a upstream target in Kong
+func (ks *KongServerDomain) DeleteUpstreamTarget(upstreamId string, id string, options Options) error {
+
+	var upstreamTargetURL string = fmt.Sprintf("%s/%s/%s/%s/%s", ks.ServerURL(), upstreamResource, upstreamId, upstreamTargetResource, id)
+
+	//	send a request to Kong to delete the upstream target by id
+	resp, err := ks.sendRequest("DELETE", upstreamTargetURL, nil)
 	if err != nil {
 		return err
 	}
 	defer resp.Body.Close()
 
 	if resp.StatusCode != http.StatusNoContent {
-		return errors.New("fail sending delete upstream target command to Kong: " + resp.Status)
+		return newKongAPIError("fail sending delete upstream target command to Kong", resp)
 	}
 
 	if options.jsonOutput {
@@ -7,6 +7,7 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -71,6 +72,49 @@ func Test_AddUpstreamTarget(t *testing.T) {
 			t.Errorf("failed checking kong status: success expected: result: %s", got.Error())
 		}
 	})
+
+	t.Run(">>> AddUpstreamTarget: scenario 3 - upstream target with weight and tags", func(t *testing.T) {
+
+		var request KongUpstreamTargetRequest
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&request)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "1343894e-404a-4f9e-a982-9e5c0e9d1733", "target": "192.168.68.107:8080", "weight": 0}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"add", "upstream-target", "--upstream-id=1234", "--target=192.168.68.107:8080",
+			"--weight=0", "--tags=canary"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed adding upstream target: success expected: result: %s", got.Error())
+		}
+
+		if request.Target != "192.168.68.107:8080" || request.Weight == nil || *request.Weight != 0 || len(request.Tags) != 1 || request.Tags[0] != "canary" {
+			t.Errorf("failed adding upstream target: unexpected attributes in the request: %+v", request)
+		}
+	})
+
+	t.Run(">>> AddUpstreamTarget: scenario 4 - invalid weight", func(t *testing.T) {
+
+		want := errors.New("wrong value for option --weight: 70000: must be between 0 and 65535")
+		got := kconf(NewKongServer("http://localhost:8001", 0), []string{"add", "upstream-target", "--upstream-id=1234",
+			"--target=192.168.68.107:8080", "--weight=70000"}, Options{})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed adding upstream target: error expected: %v result: %v", want, got)
+		}
+	})
 }
 
 // Test_QueryUpstreamTarget unit tests for QueryUpstreamTarget() method
@@ -177,7 +221,7 @@ func Test_ListUpstreamTarget(t *testing.T) {
 		}
 
 		want := errors.New("fail sending list upstream targets command to Kong: 500 Internal Server Error")
-		got := kongServer.ListUpstreamTargets("1234", Options{
+		got := kongServer.ListUpstreamTargets("1234", nil, Options{
 			verbose:    false,
 			jsonOutput: false,
 		})
@@ -212,7 +256,7 @@ func Test_ListUpstreamTarget(t *testing.T) {
 		}
 
 		var want error = nil
-		got := kongServer.ListUpstreamTargets("1234", Options{
+		got := kongServer.ListUpstreamTargets("1234", nil, Options{
 			verbose:    false,
 			jsonOutput: false,
 		})
@@ -222,6 +266,49 @@ func Test_ListUpstreamTarget(t *testing.T) {
 			t.Errorf("failed checking kong status: success expected: result: %s", got.Error())
 		}
 	})
+
+	t.Run(">>> ListUpstreamTarget: scenario 3 - targets of all upstreams", func(t *testing.T) {
+
+		var requestPaths []string
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestPaths = append(requestPaths, r.URL.Path)
+			w.WriteHeader(http.StatusOK)
+
+			switch r.URL.Path {
+			case "/upstreams":
+				w.Write([]byte(`{"data": [
+					{ "id": "a4775f39-0ddf-4d43-a9ee-31451419b812", "name": "Pedidos" },
+					{ "id": "c5886a40-1eea-4e54-b0ff-42562520c923", "name": "Produtos" }
+				], "next": null}`))
+
+			case "/upstreams/a4775f39-0ddf-4d43-a9ee-31451419b812/targets":
+				w.Write([]byte(`{"data": [{ "id": "a0110455-2652-4e83-9202-9ca212277abc", "target": "192.168.68.107:8080", "weight": 100 }], "next": null}`))
+
+			default:
+				w.Write([]byte(`{"data": [], "next": null}`))
+			}
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"list", "upstream-target"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed listing upstream targets: success expected: result: %s", got.Error())
+		}
+
+		if len(requestPaths) != 3 || requestPaths[0] != "/upstreams" || requestPaths[2] != "/upstreams/c5886a40-1eea-4e54-b0ff-42562520c923/targets" {
+			t.Errorf("failed listing upstream targets: unexpected requests: %s", requestPaths)
+		}
+	})
 }
 
 // Test_DeleteUpstreamTarget unit tests for DeleteUpstreamTarget() method
@@ -280,3 +367,174 @@ func Test_DeleteUpstreamTarget(t *testing.T) {
 		}
 	})
 }
+
+// Test_UpdateUpstreamTarget unit tests for UpdateUpstreamTarget() method
+func Test_UpdateUpstreamTarget(t *testing.T) {
+
+	t.Run(">>> UpdateUpstreamTarget: scenario 1 - upstream target not found", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("upstream target not found")
+		got := kongServer.UpdateUpstreamTarget("1234", "5678", &KongUpstreamTarget{}, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed updating upstream target: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> UpdateUpstreamTarget: scenario 2 - internal server error", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusInternalServerError)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("fail sending patch upstream target command to Kong: 500 Internal Server Error")
+		got := kongServer.UpdateUpstreamTarget("1234", "5678", &KongUpstreamTarget{}, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed updating upstream target: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> UpdateUpstreamTarget: scenario 3 - upstream target weight updated successfuly", func(t *testing.T) {
+
+		var method, path string
+		var request KongUpstreamTargetRequest
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method = r.Method
+			path = r.URL.Path
+			json.NewDecoder(r.Body).Decode(&request)
+			w.WriteHeader(http.StatusOK)
+			w.Write([]byte(`{"id": "a0110455-2652-4e83-9202-9ca212277abc", "target": "192.168.68.107:8080", "weight": 50, "tags": null}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"update", "upstream-target", "--upstream-id=Pedidos", "--id=192.168.68.107:8080", "--weight=50"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed updating upstream target: success expected: result: %s", got.Error())
+		}
+
+		if method != "PATCH" || path != "/upstreams/Pedidos/targets/192.168.68.107:8080" || request.Weight == nil || *request.Weight != 50 {
+			t.Errorf("failed updating upstream target: unexpected request: %s %s %+v", method, path, request)
+		}
+	})
+}
+
+// Test_SetUpstreamTargetHealth unit tests for SetUpstreamTargetHealth() method
+func Test_SetUpstreamTargetHealth(t *testing.T) {
+
+	t.Run(">>> SetUpstreamTargetHealth: scenario 1 - upstream target not found", func(t *testing.T) {
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			w.WriteHeader(http.StatusNotFound)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		want := errors.New("upstream target not found")
+		got := kongServer.SetUpstreamTargetHealth("1234", "5678", true, Options{
+			verbose:    false,
+			jsonOutput: false,
+		})
+
+		//	check the invocation result
+		if got == nil || want.Error() != got.Error() {
+			t.Errorf("failed setting upstream target health: error expected: %v result: %v", want, got)
+		}
+	})
+
+	t.Run(">>> SetUpstreamTargetHealth: scenario 2 - upstream target set unhealthy", func(t *testing.T) {
+
+		var method, path string
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			method = r.Method
+			path = r.URL.Path
+			w.WriteHeader(http.StatusNoContent)
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"upstream-target", "set-health", "--upstream-id=Pedidos", "--id=192.168.68.107:8080", "--unhealthy"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed setting upstream target health: success expected: result: %s", got.Error())
+		}
+
+		if method != "PUT" || path != "/upstreams/Pedidos/targets/192.168.68.107:8080/unhealthy" {
+			t.Errorf("failed setting upstream target health: unexpected request: %s %s", method, path)
+		}
+	})
+
+	t.Run(">>> SetUpstreamTargetHealth: scenario 3 - missing or conflicting health options", func(t *testing.T) {
+
+		var testScenarios = []struct {
+			command []string
+			want    string
+		}{
+			{command: []string{"upstream-target", "set-health", "--upstream-id=Pedidos", "--id=192.168.68.107:8080"},
+				want: "missing target health: option --healthy or --unhealthy required for this command"},
+			{command: []string{"upstream-target", "set-health", "--upstream-id=Pedidos", "--id=192.168.68.107:8080", "--healthy", "--unhealthy"},
+				want: "options --healthy and --unhealthy can't be used together"},
+		}
+
+		for _, test := range testScenarios {
+			got := kconf(NewKongServer("http://localhost:8001", 0), test.command, Options{})
+
+			//	check the invocation result
+			if got == nil || got.Error() != test.want {
+				t.Errorf("failed setting upstream target health: error expected: %s result: %v", test.want, got)
+			}
+		}
+	})
+}
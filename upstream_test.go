@@ -7,6 +7,7 @@
 package main
 
 import (
+	"encoding/json"
 	"errors"
 	"net/http"
 	"net/http/httptest"
@@ -181,7 +182,7 @@ func Test_ListUpstream(t *testing.T) {
 		}
 
 		want := errors.New("fail sending list upstreams command to Kong: 500 Internal Server Error")
-		got := kongServer.ListUpstreams(Options{
+		got := kongServer.ListUpstreams(nil, Options{
 			verbose:    false,
 			jsonOutput: false,
 		})
@@ -218,7 +219,7 @@ func Test_ListUpstream(t *testing.T) {
 		}
 
 		var want error = nil
-		got := kongServer.ListUpstreams(Options{
+		got := kongServer.ListUpstreams(nil, Options{
 			verbose:    false,
 			jsonOutput: false,
 		})
@@ -374,3 +375,118 @@ func Test_DeleteUpstream(t *testing.T) {
 		}
 	})
 }
+
+// Test_UpstreamBalancingOptions unit tests for the upstream load balancing options
+func Test_UpstreamBalancingOptions(t *testing.T) {
+
+	t.Run(">>> UpstreamBalancingOptions: scenario 1 - consistent hashing on a header", func(t *testing.T) {
+
+		var request map[string]interface{}
+
+		//	mock for Kong Admin
+		var mockKongAdmin *httptest.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			json.NewDecoder(r.Body).Decode(&request)
+			w.WriteHeader(http.StatusCreated)
+			w.Write([]byte(`{"id": "1343894e-404a-4f9e-a982-9e5c0e9d1733"}`))
+		}))
+		defer mockKongAdmin.Close()
+
+		//	connect to mock server
+		kongServer := NewKongServer(mockKongAdmin.URL, 0)
+		if kongServer == nil {
+			t.Errorf("fail connectring to mock Kong Admin")
+		}
+
+		got := kconf(kongServer, []string{"add", "upstream", "--name=Pedidos", "--algorithm=consistent-hashing",
+			"--hash-on=header", "--hash-on-header=x-user-id", "--hash-fallback=ip", "--slots=1000",
+			"--host-header=pedidos.internal", "--use-srv-name=true"}, Options{})
+
+		//	check the invocation result
+		if got != nil {
+			t.Fatalf("failed adding upstream: success expected: result: %s", got.Error())
+		}
+
+		want := map[string]interface{}{
+			"name":           "Pedidos",
+			"algorithm":      "consistent-hashing",
+			"hash_on":        "header",
+			"hash_on_header": "x-user-id",
+			"hash_fallback":  "ip",
+			"slots":          float64(1000),
+			"host_header":    "pedidos.internal",
+			"use_srv_name":   true,
+		}
+		if len(request) != len(want) {
+			t.Errorf("failed adding upstream: unexpected attributes in the request: %v", request)
+		}
+		for attribute, value := range want {
+			if request[attribute] != value {
+				t.Errorf("failed adding upstream: attribute %s expected: %v result: %v", attribute, value, request[attribute])
+			}
+		}
+	})
+
+	t.Run(">>> UpstreamBalancingOptions: scenario 2 - invalid load balancing options", func(t *testing.T) {
+
+		var testScenarios = []struct {
+			options []string
+			want    string
+		}{
+			{options: []string{"--algorithm=random"},
+				want: "wrong value for option --algorithm: random: must be one of round-robin, consistent-hashing, least-connections, latency"},
+			{options: []string{"--hash-on=body"},
+				want: "wrong value for option --hash-on: body: must be one of none, consumer, ip, header, cookie, path, query_arg, uri_capture"},
+			{options: []string{"--hash-on=header"},
+				want: "missing option --hash-on-header: required for option --hash-on=header"},
+			{options: []string{"--hash-on=ip", "--hash-on-cookie=session"},
+				want: "option --hash-on-cookie requires option --hash-on=cookie"},
+			{options: []string{"--hash-on=header", "--hash-on-header=x-user-id", "--hash-on-cookie-path=/"},
+				want: "option --hash-on-cookie-path requires option --hash-on=cookie"},
+			{options: []string{"--hash-on-cookie-path=/"},
+				want: "option --hash-on-cookie-path requires option --hash-on=cookie"},
+			{options: []string{"--algorithm=round-robin", "--hash-on=ip"},
+				want: "option --hash-on=ip requires option --algorithm=consistent-hashing"},
+			{options: []string{"--algorithm=least-connections", "--hash-on=header", "--hash-on-header=x-user-id"},
+				want: "option --hash-on=header requires option --algorithm=consistent-hashing"},
+			{options: []string{"--hash-on=cookie", "--hash-on-cookie=session", "--hash-fallback=ip"},
+				want: "option --hash-fallback=ip can't be used with option --hash-on=cookie"},
+			{options: []string{"--hash-on=none", "--hash-fallback=consumer"},
+				want: "option --hash-fallback=consumer can't be used with option --hash-on=none"},
+			{options: []string{"--hash-on=consumer", "--hash-fallback=cookie"},
+				want: "wrong value for option --hash-fallback: cookie: must be one of none, consumer, ip, header, path, query_arg, uri_capture"},
+			{options: []string{"--hash-on=ip", "--hash-fallback=ip"},
+				want: "option --hash-fallback must be different from option --hash-on"},
+			{options: []string{"--hash-on=header", "--hash-on-header=x-user-id", "--hash-fallback=header", "--hash-fallback-header=x-user-id"},
+				want: "option --hash-fallback must be different from option --hash-on"},
+			{options: []string{"--hash-on=ip", "--hash-fallback=query_arg"},
+				want: "missing option --hash-fallback-query-arg: required for option --hash-fallback=query_arg"},
+			{options: []string{"--hash-fallback-cookie=session"},
+				want: "invalid option: --hash-fallback-cookie=session"},
+			{options: []string{"--slots=5"},
+				want: "wrong value for option --slots: 5: must be between 10 and 65536"},
+			{options: []string{"--use-srv-name=yes"},
+				want: "wrong value for option --use-srv-name: yes"},
+		}
+
+		for _, test := range testScenarios {
+			got := kconf(NewKongServer("http://localhost:8001", 0), append([]string{"add", "upstream", "--name=Pedidos"}, test.options...), Options{})
+
+			//	check the invocation result
+			if got == nil || got.Error() != test.want {
+				t.Errorf("failed checking options %v: error expected: %s result: %v", test.options, test.want, got)
+			}
+		}
+	})
+
+	t.Run(">>> UpstreamBalancingOptions: scenario 3 - update a companion attribute only", func(t *testing.T) {
+
+		upstream, err := upstreamOptions([]string{"--hash-on-header=x-tenant-id"})
+		if err != nil {
+			t.Fatalf("failed parsing upstream options: success expected: result: %s", err.Error())
+		}
+
+		if upstream.hashing.HashOnHeader != "x-tenant-id" {
+			t.Errorf("failed parsing upstream options: unexpected hashing attributes: %+v", upstream.hashing)
+		}
+	})
+}